@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/gateway"
 	"github.com/VirrageS/chirp/backend/service"
 
 	"github.com/VirrageS/chirp/backend/token"
@@ -13,6 +14,9 @@ type API struct {
 	service      service.ServiceProvider
 	tokenManager token.Manager
 	googleOAuth2 oauth2.Config
+	mediaConfig  config.MediaConfigProvider
+	streamConfig config.StreamConfigProvider
+	gateway      *gateway.Gateway
 }
 
 // Constructs an API object that uses given ServiceProvider.
@@ -20,6 +24,9 @@ func New(
 	service service.ServiceProvider,
 	tokenManager token.Manager,
 	authorizationGoogleConfig config.AuthorizationGoogleConfigProvider,
+	mediaConfig config.MediaConfigProvider,
+	streamConfig config.StreamConfigProvider,
+	gateway *gateway.Gateway,
 ) APIProvider {
 	googleOAuth2 := oauth2.Config{
 		ClientID:     authorizationGoogleConfig.GetClientID(),
@@ -36,5 +43,8 @@ func New(
 		service:      service,
 		tokenManager: tokenManager,
 		googleOAuth2: googleOAuth2,
+		mediaConfig:  mediaConfig,
+		streamConfig: streamConfig,
+		gateway:      gateway,
 	}
 }
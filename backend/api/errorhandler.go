@@ -13,9 +13,30 @@ var errorToCodeMap = map[error]int{
 	errors.UnexpectedError:                    http.StatusInternalServerError,
 	errors.UserAlreadyExistsError:             http.StatusConflict,
 	errors.ForbiddenError:                     http.StatusForbidden,
+	errors.EditWindowExpiredError:             http.StatusForbidden,
+	errors.RestoreWindowExpiredError:          http.StatusForbidden,
+	errors.PublishTimeInPastError:             http.StatusBadRequest,
+	errors.EmptyContentError:                  http.StatusBadRequest,
+	errors.ContentTooLongError:                http.StatusUnprocessableEntity,
+	errors.BannedContentError:                 http.StatusUnprocessableEntity,
+	errors.DuplicateContentError:              http.StatusUnprocessableEntity,
+	errors.InvalidVisibilityError:             http.StatusBadRequest,
+	errors.InvalidGranularityError:            http.StatusBadRequest,
 	errors.InvalidCredentialsError:            http.StatusUnauthorized,
 	errors.NotExistingUserAuthenticatingError: http.StatusBadRequest,
 	errors.NoUserAgentHeaderError:             http.StatusBadRequest,
+	errors.TooManyMediaError:                  http.StatusBadRequest,
+	errors.InvalidMediaError:                  http.StatusBadRequest,
+	errors.MediaTooLargeError:                 http.StatusRequestEntityTooLarge,
+	errors.UnsupportedMediaTypeError:          http.StatusUnsupportedMediaType,
+	errors.InvalidPollError:                   http.StatusBadRequest,
+	errors.InvalidPollOptionError:             http.StatusBadRequest,
+	errors.PollClosedError:                    http.StatusForbidden,
+	errors.AlreadyVotedError:                  http.StatusConflict,
+	errors.ScheduledPollError:                 http.StatusBadRequest,
+	errors.InvalidListNameError:               http.StatusBadRequest,
+	errors.InvalidMutedWordError:              http.StatusBadRequest,
+	errors.ShuttingDownError:                  http.StatusServiceUnavailable,
 }
 
 func getStatusCodeFromError(err error) int {
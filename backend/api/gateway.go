@@ -0,0 +1,18 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Gateway upgrades the request to WebSocket connection over which the user
+// receives notifications and typing indicators and sends own messages.
+func (api *API) Gateway(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	err := api.gateway.Serve(context.Writer, context.Request, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+}
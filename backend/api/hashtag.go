@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+func (api *API) HashtagTweets(context *gin.Context) {
+	requestingUserID := context.MustGet("userID").(int64)
+	hashtag := context.Param("tag")
+
+	if hashtag == "" {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid hashtag. Expected non-empty."))
+		return
+	}
+
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	tweets, nextCursor, err := api.service.HashtagTweets(hashtag, requestingUserID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &model.TweetsPage{
+		Tweets:     tweets,
+		NextCursor: encodeCursor(nextCursor),
+	})
+}
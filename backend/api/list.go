@@ -0,0 +1,263 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+func (api *API) CreateList(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	var newList model.NewList
+
+	if err := context.BindJSON(&newList); err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Field name is required."))
+		return
+	}
+
+	list, err := api.service.CreateList(&newList, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, list)
+}
+
+func (api *API) GetList(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	listID, err := getListID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := api.service.GetList(listID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, list)
+}
+
+func (api *API) DeleteList(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	listID, err := getListID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	err = api.service.DeleteList(listID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+func (api *API) Lists(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	lists, err := api.service.Lists(requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, lists)
+}
+
+func (api *API) UserLists(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	userID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid user ID. Expected an integer."))
+		return
+	}
+
+	lists, err := api.service.UserLists(userID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, lists)
+}
+
+func (api *API) ListMembers(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	listID, err := getListID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	users, nextCursor, err := api.service.ListMembers(listID, requestingUserID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &model.UsersPage{
+		Users:      users,
+		NextCursor: encodeCursor(nextCursor),
+	})
+}
+
+func (api *API) AddListMember(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	listID, userID, err := getListMemberID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := api.service.AddListMember(listID, userID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, list)
+}
+
+func (api *API) RemoveListMember(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	listID, userID, err := getListMemberID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := api.service.RemoveListMember(listID, userID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, list)
+}
+
+func (api *API) ListTimeline(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	listID, err := getListID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	sinceID, err := getSinceID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	tweets, nextCursor, err := api.service.ListTimeline(listID, requestingUserID, sinceID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &model.TweetsPage{
+		Tweets:     tweets,
+		NextCursor: encodeCursor(nextCursor),
+	})
+}
+
+func (api *API) SubscribeList(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	listID, err := getListID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := api.service.SubscribeList(listID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, list)
+}
+
+func (api *API) UnsubscribeList(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	listID, err := getListID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := api.service.UnsubscribeList(listID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, list)
+}
+
+func getListID(context *gin.Context) (int64, error) {
+	listID, err := strconv.ParseInt(context.Param("id"), 10, 64)
+	if err != nil {
+		return 0, errors.New("Invalid list ID. Expected an integer.")
+	}
+
+	return listID, nil
+}
+
+// getListMemberID returns ID of the list and ID of its member from the path.
+func getListMemberID(context *gin.Context) (int64, int64, error) {
+	listID, err := getListID(context)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	userID, err := strconv.ParseInt(context.Param("user_id"), 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("Invalid user ID. Expected an integer.")
+	}
+
+	return listID, userID, nil
+}
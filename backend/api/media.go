@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	appErrors "github.com/VirrageS/chirp/backend/model/errors"
+)
+
+// multipartOverhead is the space reserved in the request body for multipart
+// headers and boundaries around the uploaded file.
+const multipartOverhead = 64 * 1024
+
+func (api *API) UploadMedia(context *gin.Context) {
+	requestingUserID := context.MustGet("userID").(int64)
+	maxSize := api.mediaConfig.GetMaxSize()
+
+	// the body is limited before parsing so too large uploads are never
+	// buffered in memory or temporary files
+	maxBodySize := maxSize + multipartOverhead
+	if context.Request.ContentLength > maxBodySize {
+		context.AbortWithError(http.StatusRequestEntityTooLarge, appErrors.MediaTooLargeError)
+		return
+	}
+	context.Request.Body = http.MaxBytesReader(context.Writer, context.Request.Body, maxBodySize)
+
+	file, _, err := context.Request.FormFile("media")
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Field media with uploaded file is required."))
+		return
+	}
+	defer file.Close()
+
+	// one more byte than allowed tells the service that the file is too large
+	data, err := ioutil.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Failed to read uploaded file."))
+		return
+	}
+
+	media, err := api.service.UploadMedia(data, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, media)
+}
+
+func (api *API) GetMedia(context *gin.Context) {
+	requestingUserID := context.MustGet("userID").(int64)
+
+	media, data, err := api.service.GetMedia(context.Param("key"), requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	// media of followers-only tweets must not be kept by shared caches
+	context.Header("Cache-Control", "private")
+	context.Data(http.StatusOK, media.MimeType, data)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+func (api *API) MuteUser(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	userID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid user ID. Expected an integer."))
+		return
+	}
+	if userID == requestingUserID {
+		context.AbortWithError(http.StatusBadRequest, errors.New("User can't mute himself."))
+		return
+	}
+
+	err = api.service.MuteUser(userID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+func (api *API) UnmuteUser(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	userID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid user ID. Expected an integer."))
+		return
+	}
+
+	err = api.service.UnmuteUser(userID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+func (api *API) MutedUsers(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	users, err := api.service.MutedUsers(requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, users)
+}
+
+func (api *API) MuteWord(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	var newMutedWord model.NewMutedWord
+
+	if err := context.BindJSON(&newMutedWord); err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Field word is required."))
+		return
+	}
+
+	err := api.service.MuteWord(newMutedWord.Word, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+func (api *API) UnmuteWord(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	err := api.service.UnmuteWord(context.Param("word"), requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
+
+func (api *API) MutedWords(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	words, err := api.service.MutedWords(requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, words)
+}
@@ -10,11 +10,46 @@ type APIProvider interface {
 	CreateOrLoginUserWithGoogle(context *gin.Context)
 
 	GetTweet(context *gin.Context)
+	TweetAnalytics(context *gin.Context)
 	PostTweet(context *gin.Context)
 	DeleteTweet(context *gin.Context)
+	RestoreTweet(context *gin.Context)
+	ScheduleTweet(context *gin.Context)
+	GetScheduledTweets(context *gin.Context)
+	CancelScheduledTweet(context *gin.Context)
+	EditTweet(context *gin.Context)
+	GetTweetRevisions(context *gin.Context)
+	GetConversation(context *gin.Context)
 	LikeTweet(context *gin.Context)
 	UnlikeTweet(context *gin.Context)
+	RetweetTweet(context *gin.Context)
+	UnretweetTweet(context *gin.Context)
+	VotePoll(context *gin.Context)
+	BookmarkTweet(context *gin.Context)
+	UnbookmarkTweet(context *gin.Context)
+	Bookmarks(context *gin.Context)
+	TweetLikers(context *gin.Context)
+	PinTweet(context *gin.Context)
+	UnpinTweet(context *gin.Context)
+	HashtagTweets(context *gin.Context)
+	UploadMedia(context *gin.Context)
+	GetMedia(context *gin.Context)
 	Feed(context *gin.Context)
+	FeedNewCount(context *gin.Context)
+	StreamFeed(context *gin.Context)
+	Gateway(context *gin.Context)
+
+	CreateList(context *gin.Context)
+	GetList(context *gin.Context)
+	DeleteList(context *gin.Context)
+	Lists(context *gin.Context)
+	UserLists(context *gin.Context)
+	ListMembers(context *gin.Context)
+	AddListMember(context *gin.Context)
+	RemoveListMember(context *gin.Context)
+	ListTimeline(context *gin.Context)
+	SubscribeList(context *gin.Context)
+	UnsubscribeList(context *gin.Context)
 
 	GetUser(context *gin.Context)
 	FollowUser(context *gin.Context)
@@ -22,6 +57,15 @@ type APIProvider interface {
 	UserFollowers(context *gin.Context)
 	UserFollowees(context *gin.Context)
 	UserTweets(context *gin.Context)
+	UserMentions(context *gin.Context)
+	UserLikes(context *gin.Context)
+
+	MuteUser(context *gin.Context)
+	UnmuteUser(context *gin.Context)
+	MutedUsers(context *gin.Context)
+	MuteWord(context *gin.Context)
+	UnmuteWord(context *gin.Context)
+	MutedWords(context *gin.Context)
 
 	Search(context *gin.Context)
 }
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+func (api *API) ScheduleTweet(context *gin.Context) {
+	requestingUserID := context.MustGet("userID").(int64)
+	var newTweet model.NewScheduledTweet
+
+	if err := context.BindJSON(&newTweet); err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Fields content and publish_at are required."))
+		return
+	}
+
+	newTweet.AuthorID = requestingUserID
+
+	scheduledTweet, err := api.service.ScheduleTweet(&newTweet, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, scheduledTweet)
+}
+
+func (api *API) GetScheduledTweets(context *gin.Context) {
+	requestingUserID := context.MustGet("userID").(int64)
+
+	scheduledTweets, err := api.service.GetScheduledTweets(requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, scheduledTweets)
+}
+
+func (api *API) CancelScheduledTweet(context *gin.Context) {
+	requestingUserID := context.MustGet("userID").(int64)
+	parameterID := context.Param("id")
+
+	scheduledTweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid scheduled tweet ID. Expected an integer."))
+		return
+	}
+
+	err = api.service.CancelScheduledTweet(scheduledTweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.Status(http.StatusNoContent)
+}
@@ -16,12 +16,20 @@ func (api *API) Search(context *gin.Context) {
 		return
 	}
 
-	ftsResult, err := api.service.FullTextSearch(queryString, requestingUserID)
+	cursor, limit, err := getSearchPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	ftsResult, nextCursor, err := api.service.FullTextSearch(queryString, requestingUserID, cursor, limit)
 	if err != nil {
 		statusCode := getStatusCodeFromError(err)
 		context.AbortWithError(statusCode, err)
 		return
 	}
 
+	ftsResult.NextCursor = encodeSearchCursor(nextCursor)
+
 	context.IndentedJSON(http.StatusOK, ftsResult)
 }
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamFeed sends live updates of the feed as Server-Sent Events until the
+// client disconnects. The stream is closed when the client can not keep up
+// with the events so it should reconnect and catch up with `/feed`.
+func (api *API) StreamFeed(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	subscription, err := api.service.SubscribeFeed(requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+	defer subscription.Close()
+
+	heartbeat := time.NewTicker(api.streamConfig.GetHeartbeatInterval())
+	defer heartbeat.Stop()
+
+	context.Header("Cache-Control", "no-cache")
+	// nginx would buffer the events otherwise
+	context.Header("X-Accel-Buffering", "no")
+	context.Status(http.StatusOK)
+	// headers are sent right away so the client knows the stream is open
+	context.Writer.Flush()
+
+	clientGone := context.Writer.CloseNotify()
+	context.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return false
+			}
+
+			context.SSEvent(event.Type, event.Data)
+			return true
+		case <-heartbeat.C:
+			// comments are ignored by the clients but keep the connection open
+			fmt.Fprint(w, ":\n\n")
+			return true
+		case <-clientGone:
+			return false
+		}
+	})
+}
@@ -31,6 +31,26 @@ func (api *API) GetTweet(context *gin.Context) {
 	context.IndentedJSON(http.StatusOK, responseTweet)
 }
 
+func (api *API) TweetAnalytics(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	analytics, err := api.service.TweetAnalytics(tweetID, requestingUserID, context.Query("granularity"))
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, analytics)
+}
+
 func (api *API) PostTweet(context *gin.Context) {
 	// for now lets panic when userID is not set, or when its not an int because that would mean a BUG in token_auth middleware
 	requestingUserID := (context.MustGet("userID").(int64))
@@ -76,6 +96,100 @@ func (api *API) DeleteTweet(context *gin.Context) {
 	context.Status(http.StatusNoContent)
 }
 
+func (api *API) RestoreTweet(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	tweet, err := api.service.RestoreTweet(tweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, tweet)
+}
+
+func (api *API) EditTweet(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	var tweetContent model.NewTweetContent
+	if err := context.BindJSON(&tweetContent); err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Field content is required."))
+		return
+	}
+
+	tweet, err := api.service.EditTweet(tweetID, tweetContent.Content, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, tweet)
+}
+
+func (api *API) GetTweetRevisions(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	revisions, err := api.service.GetTweetRevisions(tweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, revisions)
+}
+
+func (api *API) GetConversation(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	conversation, nextCursor, err := api.service.GetConversation(tweetID, requestingUserID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	conversation.NextCursor = encodeCursor(nextCursor)
+
+	context.IndentedJSON(http.StatusOK, conversation)
+}
+
 func (api *API) LikeTweet(context *gin.Context) {
 	// for now lets panic when userID is not set, or when its not an int because that would mean a BUG
 	requestingUserID := (context.MustGet("userID").(int64))
@@ -118,16 +232,252 @@ func (api *API) UnlikeTweet(context *gin.Context) {
 	context.IndentedJSON(http.StatusOK, tweet)
 }
 
+func (api *API) RetweetTweet(context *gin.Context) {
+	// for now lets panic when userID is not set, or when its not an int because that would mean a BUG
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	tweet, err := api.service.RetweetTweet(tweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, tweet)
+}
+
+func (api *API) UnretweetTweet(context *gin.Context) {
+	// for now lets panic when userID is not set, or when its not an int because that would mean a BUG
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	tweet, err := api.service.UnretweetTweet(tweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, tweet)
+}
+
+func (api *API) BookmarkTweet(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	tweet, err := api.service.BookmarkTweet(tweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, tweet)
+}
+
+func (api *API) UnbookmarkTweet(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	tweet, err := api.service.UnbookmarkTweet(tweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, tweet)
+}
+
+func (api *API) Bookmarks(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	tweets, nextCursor, err := api.service.Bookmarks(requestingUserID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &model.TweetsPage{
+		Tweets:     tweets,
+		NextCursor: encodeCursor(nextCursor),
+	})
+}
+
+func (api *API) TweetLikers(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	users, nextCursor, err := api.service.TweetLikers(tweetID, requestingUserID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &model.UsersPage{
+		Users:      users,
+		NextCursor: encodeCursor(nextCursor),
+	})
+}
+
+func (api *API) PinTweet(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	user, err := api.service.PinTweet(tweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, user)
+}
+
+func (api *API) UnpinTweet(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	user, err := api.service.UnpinTweet(tweetID, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, user)
+}
+
+func (api *API) VotePoll(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	tweetID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+		return
+	}
+
+	var vote model.PollVote
+	if err := context.BindJSON(&vote); err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Field option is required."))
+		return
+	}
+
+	tweet, err := api.service.VotePoll(tweetID, *vote.Option, requestingUserID)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, tweet)
+}
+
 func (api *API) Feed(context *gin.Context) {
 	// for now lets panic when userID is not set, or when its not an int because that would mean a BUG in token_auth middleware
 	requestingUserID := (context.MustGet("userID").(int64))
 
-	tweets, err := api.service.Feed(requestingUserID)
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	sinceID, err := getSinceID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	tweets, nextCursor, err := api.service.Feed(requestingUserID, sinceID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &model.TweetsPage{
+		Tweets:     tweets,
+		NextCursor: encodeCursor(nextCursor),
+	})
+}
+
+func (api *API) FeedNewCount(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+
+	sinceID, err := getSinceID(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	} else if sinceID == 0 {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Field since_id is required."))
+		return
+	}
+
+	count, err := api.service.FeedNewCount(requestingUserID, sinceID)
 	if err != nil {
 		statusCode := getStatusCodeFromError(err)
 		context.AbortWithError(statusCode, err)
 		return
 	}
 
-	context.IndentedJSON(http.StatusOK, tweets)
+	context.IndentedJSON(http.StatusOK, count)
 }
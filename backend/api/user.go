@@ -6,6 +6,8 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/VirrageS/chirp/backend/model"
 )
 
 func (api *API) GetUser(context *gin.Context) {
@@ -18,6 +20,16 @@ func (api *API) GetUser(context *gin.Context) {
 		return
 	}
 
+	// `from_tweet` is set when the profile was opened from one of the tweets
+	var fromTweetID int64
+	if parameterFromTweet := context.Query("from_tweet"); parameterFromTweet != "" {
+		fromTweetID, err = strconv.ParseInt(parameterFromTweet, 10, 64)
+		if err != nil {
+			context.AbortWithError(http.StatusBadRequest, errors.New("Invalid tweet ID. Expected an integer."))
+			return
+		}
+	}
+
 	user, err := api.service.GetUser(userID, requestingUserID)
 	if err != nil {
 		statusCode := getStatusCodeFromError(err)
@@ -25,6 +37,11 @@ func (api *API) GetUser(context *gin.Context) {
 		return
 	}
 
+	if fromTweetID != 0 {
+		// the profile is served even if the click could not be counted
+		api.service.RecordProfileClick(fromTweetID, userID, requestingUserID)
+	}
+
 	context.IndentedJSON(http.StatusOK, user)
 }
 
@@ -82,14 +99,23 @@ func (api *API) UserFollowers(context *gin.Context) {
 		return
 	}
 
-	users, err := api.service.UserFollowers(userID, requestingUserID)
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	users, nextCursor, err := api.service.UserFollowers(userID, requestingUserID, cursor, limit)
 	if err != nil {
 		statusCode := getStatusCodeFromError(err)
 		context.AbortWithError(statusCode, err)
 		return
 	}
 
-	context.IndentedJSON(http.StatusOK, users)
+	context.IndentedJSON(http.StatusOK, &model.UsersPage{
+		Users:      users,
+		NextCursor: encodeCursor(nextCursor),
+	})
 }
 
 func (api *API) UserFollowees(context *gin.Context) {
@@ -102,14 +128,23 @@ func (api *API) UserFollowees(context *gin.Context) {
 		return
 	}
 
-	users, err := api.service.UserFollowees(userID, requestingUserID)
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	users, nextCursor, err := api.service.UserFollowees(userID, requestingUserID, cursor, limit)
 	if err != nil {
 		statusCode := getStatusCodeFromError(err)
 		context.AbortWithError(statusCode, err)
 		return
 	}
 
-	context.IndentedJSON(http.StatusOK, users)
+	context.IndentedJSON(http.StatusOK, &model.UsersPage{
+		Users:      users,
+		NextCursor: encodeCursor(nextCursor),
+	})
 }
 
 func (api *API) UserTweets(context *gin.Context) {
@@ -122,12 +157,79 @@ func (api *API) UserTweets(context *gin.Context) {
 		return
 	}
 
-	tweets, err := api.service.GetTweetsOfUserWithID(userID, requestingUserID)
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	tweets, nextCursor, err := api.service.GetTweetsOfUserWithID(userID, requestingUserID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &model.TweetsPage{
+		Tweets:     tweets,
+		NextCursor: encodeCursor(nextCursor),
+	})
+}
+
+func (api *API) UserMentions(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	userID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid user ID. Expected an integer."))
+		return
+	}
+
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	tweets, nextCursor, err := api.service.UserMentions(userID, requestingUserID, cursor, limit)
+	if err != nil {
+		statusCode := getStatusCodeFromError(err)
+		context.AbortWithError(statusCode, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &model.TweetsPage{
+		Tweets:     tweets,
+		NextCursor: encodeCursor(nextCursor),
+	})
+}
+
+func (api *API) UserLikes(context *gin.Context) {
+	requestingUserID := (context.MustGet("userID").(int64))
+	parameterID := context.Param("id")
+
+	userID, err := strconv.ParseInt(parameterID, 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, errors.New("Invalid user ID. Expected an integer."))
+		return
+	}
+
+	cursor, limit, err := getCursorPagination(context)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	tweets, nextCursor, err := api.service.UserLikes(userID, requestingUserID, cursor, limit)
 	if err != nil {
 		statusCode := getStatusCodeFromError(err)
 		context.AbortWithError(statusCode, err)
 		return
 	}
 
-	context.IndentedJSON(http.StatusOK, tweets)
+	context.IndentedJSON(http.StatusOK, &model.TweetsPage{
+		Tweets:     tweets,
+		NextCursor: encodeCursor(nextCursor),
+	})
 }
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+const (
+	// Number of items returned when `limit` is not specified in query.
+	defaultLimit = 20
+
+	// Maximal number of items which can be requested at once.
+	maxLimit = 100
+)
+
+// getCursorPagination reads `cursor` and `limit` query parameters from request.
+// Cursor is nil when the first page is requested.
+func getCursorPagination(context *gin.Context) (*model.Cursor, int, error) {
+	limit, err := getLimit(context)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	encodedCursor := context.Query("cursor")
+	if encodedCursor == "" {
+		return nil, limit, nil
+	}
+
+	cursor, err := decodeCursor(encodedCursor)
+	if err != nil {
+		return nil, 0, errors.New("Invalid cursor.")
+	}
+
+	return cursor, limit, nil
+}
+
+// getSearchPagination works like `getCursorPagination` but reads cursor of
+// search results (see `encodeSearchCursor`).
+func getSearchPagination(context *gin.Context) (*model.SearchCursor, int, error) {
+	limit, err := getLimit(context)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	encodedCursor := context.Query("cursor")
+	if encodedCursor == "" {
+		return nil, limit, nil
+	}
+
+	cursor, err := decodeSearchCursor(encodedCursor)
+	if err != nil {
+		return nil, 0, errors.New("Invalid cursor.")
+	}
+
+	return cursor, limit, nil
+}
+
+// getLimit reads `limit` query parameter from request. Missing parameter is
+// replaced with default.
+func getLimit(context *gin.Context) (int, error) {
+	limit, err := strconv.Atoi(context.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit <= 0 || limit > maxLimit {
+		return 0, errors.New("Invalid limit. Expected an integer between 1 and 100.")
+	}
+
+	return limit, nil
+}
+
+// getSinceID reads `since_id` query parameter from request. Zero is returned
+// when the parameter is missing.
+func getSinceID(context *gin.Context) (int64, error) {
+	parameter, ok := context.GetQuery("since_id")
+	if !ok {
+		return 0, nil
+	}
+
+	sinceID, err := strconv.ParseInt(parameter, 10, 64)
+	if err != nil || sinceID <= 0 {
+		return 0, errors.New("Invalid since_id. Expected a positive integer.")
+	}
+
+	return sinceID, nil
+}
+
+// encodeCursor encodes cursor so it can be passed to clients which should
+// treat it as an opaque string. Nil cursor is encoded as empty string.
+func encodeCursor(cursor *model.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	raw := fmt.Sprintf("%d:%d", cursor.Time.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(encodedCursor string) (*model.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var nanoseconds, id int64
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanoseconds, &id); err != nil {
+		return nil, err
+	}
+
+	return &model.Cursor{Time: time.Unix(0, nanoseconds).UTC(), ID: id}, nil
+}
+
+// encodeSearchCursor encodes cursors of users and tweets as single opaque
+// string. Cursors of lists which have no more results are left empty.
+func encodeSearchCursor(cursor *model.SearchCursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	// "." is not used by the base64 URL encoding
+	raw := encodeCursor(cursor.Users) + "." + encodeCursor(cursor.Tweets)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSearchCursor(encodedCursor string) (*model.SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(string(raw), ".")
+	if len(parts) != 2 || (parts[0] == "" && parts[1] == "") {
+		return nil, errors.New("invalid search cursor")
+	}
+
+	var cursor model.SearchCursor
+	if parts[0] != "" {
+		if cursor.Users, err = decodeCursor(parts[0]); err != nil {
+			return nil, err
+		}
+	}
+	if parts[1] != "" {
+		if cursor.Tweets, err = decodeCursor(parts[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cursor, nil
+}
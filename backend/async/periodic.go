@@ -0,0 +1,54 @@
+package async
+
+import (
+	"time"
+)
+
+// Periodic calls a function periodically in the background. Calls never
+// overlap, so a call which takes longer than the interval delays the next one.
+type Periodic struct {
+	call     func()
+	interval time.Duration
+
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewPeriodic creates new instance of `Periodic` which calls `call` every
+// `interval` once it is started.
+func NewPeriodic(interval time.Duration, call func()) *Periodic {
+	return &Periodic{
+		call:     call,
+		interval: interval,
+		stopChan: make(chan struct{}),
+		doneChan: make(chan struct{}),
+	}
+}
+
+// Start starts calling the function in the background.
+func (p *Periodic) Start() {
+	go p.run()
+}
+
+// Stop stops calling the function and waits until the current call is
+// finished.
+func (p *Periodic) Stop() {
+	close(p.stopChan)
+	<-p.doneChan
+}
+
+func (p *Periodic) run() {
+	defer close(p.doneChan)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.call()
+		case <-p.stopChan:
+			return
+		}
+	}
+}
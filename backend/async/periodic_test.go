@@ -0,0 +1,72 @@
+package async
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Periodic", func() {
+	var (
+		mutex    sync.Mutex
+		calls    int
+		periodic *Periodic
+	)
+
+	getCalls := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return calls
+	}
+
+	BeforeEach(func() {
+		calls = 0
+		periodic = NewPeriodic(10*time.Millisecond, func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+			calls++
+		})
+	})
+
+	It("should call function periodically", func() {
+		periodic.Start()
+		defer periodic.Stop()
+
+		Eventually(getCalls).Should(BeNumerically(">=", 2))
+	})
+
+	It("should not call function before it was started", func() {
+		time.Sleep(50 * time.Millisecond)
+		Expect(getCalls()).To(BeZero())
+	})
+
+	It("should not call function after it was stopped", func() {
+		periodic.Start()
+		Eventually(getCalls).Should(BeNumerically(">=", 1))
+		periodic.Stop()
+
+		stoppedCalls := getCalls()
+		time.Sleep(50 * time.Millisecond)
+		Expect(getCalls()).To(Equal(stoppedCalls))
+	})
+
+	It("should wait for the current call when stopped", func() {
+		started := make(chan struct{})
+		finished := false
+		periodic = NewPeriodic(10*time.Millisecond, func() {
+			if !finished {
+				close(started)
+				time.Sleep(50 * time.Millisecond)
+				finished = true
+			}
+		})
+
+		periodic.Start()
+		<-started
+		periodic.Stop()
+
+		Expect(finished).To(BeTrue())
+	})
+})
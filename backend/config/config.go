@@ -17,6 +17,14 @@ type Configuration struct {
 	Redis               RedisConfigProvider
 	Elasticsearch       ElasticsearchConfigProvider
 	AuthorizationGoogle AuthorizationGoogleConfigProvider
+	Media               MediaConfigProvider
+	Tweets              TweetsConfigProvider
+	Scheduler           SchedulerConfigProvider
+	Purger              PurgerConfigProvider
+	Analytics           AnalyticsConfigProvider
+	Timelines           TimelinesConfigProvider
+	Stream              StreamConfigProvider
+	Gateway             GatewayConfigProvider
 }
 
 // New reads and creates configuration from path provided in env `$CHIRP_CONFIG_PATH`
@@ -58,5 +66,13 @@ func New() *Configuration {
 		Redis:               config.getRedisConfig(),
 		Elasticsearch:       config.getElasticsearchConfig(),
 		AuthorizationGoogle: config.getAuthorizationGoogleConfig(),
+		Media:               config.getMediaConfig(),
+		Tweets:              config.getTweetsConfig(),
+		Scheduler:           config.getSchedulerConfig(),
+		Purger:              config.getPurgerConfig(),
+		Analytics:           config.getAnalyticsConfig(),
+		Timelines:           config.getTimelinesConfig(),
+		Stream:              config.getStreamConfig(),
+		Gateway:             config.getGatewayConfig(),
 	}
 }
@@ -41,6 +41,45 @@ elasticsearch_defaults: &elasticsearch_defaults
   host: "localhost"
   port: "9200"
 
+media_defaults: &media_defaults
+  directory: "/tmp/chirp/media"
+  base_url: "http://localhost:8080/media"
+  max_size: 5242880 # 5MB
+
+tweets_defaults: &tweets_defaults
+  edit_window: 30m # how long after posting tweet can be edited
+  max_length: 150 # maximal number of characters (emoji count as one)
+  duplicate_window: 1h # how long the same tweet can not be posted again (0 disables)
+  banned_terms: [] # words which can not be used in tweets
+  restore_window: 720h # how long after deletion tweet can be restored
+
+scheduler_defaults: &scheduler_defaults
+  interval: 10s # how often scheduled tweets are checked
+  batch_size: 100 # maximal number of tweets published at once
+
+purger_defaults: &purger_defaults
+  interval: 1h # how often deleted tweets are checked
+  batch_size: 100 # maximal number of tweets purged at once
+
+analytics_defaults: &analytics_defaults
+  flush_interval: 1m # how often counted impressions and clicks are saved in the database
+
+timelines_defaults: &timelines_defaults
+  size: 800 # maximal number of entries kept in the home timeline of the user
+  fanout_limit: 10000 # authors with more followers are read on request instead of pushed to timelines
+  expiration_time: 72h # how long timeline is kept before it is built again (drops timelines of inactive users)
+
+stream_defaults: &stream_defaults
+  heartbeat_interval: 15s # how often idle event streams are kept alive (has to be shorter than proxy timeouts)
+  buffer_size: 64 # maximal number of events waiting for slow client before the stream is closed
+
+gateway_defaults: &gateway_defaults
+  heartbeat_interval: 30s # how often WebSocket connections are pinged
+  heartbeat_timeout: 1m # connection is closed when client does not respond for so long
+  buffer_size: 64 # maximal number of replies waiting for slow client before the connection is closed
+  shutdown_timeout: 5s # how long clients can take to close connections when the server shuts down
+  typing_interval: 2s # typing indicators sent to the same user more often over single connection are dropped
+
 defaults: &defaults
   <<: *server_defaults
   postgres:
@@ -51,6 +90,22 @@ defaults: &defaults
     <<: *authorization_google_defaults
   elasticsearch:
     <<: *elasticsearch_defaults
+  media:
+    <<: *media_defaults
+  tweets:
+    <<: *tweets_defaults
+  scheduler:
+    <<: *scheduler_defaults
+  purger:
+    <<: *purger_defaults
+  analytics:
+    <<: *analytics_defaults
+  timelines:
+    <<: *timelines_defaults
+  stream:
+    <<: *stream_defaults
+  gateway:
+    <<: *gateway_defaults
 
 development:
   <<: *defaults
@@ -87,6 +87,14 @@ var _ = Describe("Config", func() {
 		Expect(config.Redis).NotTo(BeNil())
 		Expect(config.AuthorizationGoogle).NotTo(BeNil())
 		Expect(config.Elasticsearch).NotTo(BeNil())
+		Expect(config.Media).NotTo(BeNil())
+		Expect(config.Tweets).NotTo(BeNil())
+		Expect(config.Scheduler).NotTo(BeNil())
+		Expect(config.Purger).NotTo(BeNil())
+		Expect(config.Analytics).NotTo(BeNil())
+		Expect(config.Timelines).NotTo(BeNil())
+		Expect(config.Stream).NotTo(BeNil())
+		Expect(config.Gateway).NotTo(BeNil())
 	})
 
 	It(`should return valid config when CHIRP_CONFIG_PATH is set and
@@ -57,3 +57,58 @@ type ElasticsearchConfigProvider interface {
 	GetHost() string
 	GetPort() string
 }
+
+// MediaConfigProvider provides configuration of uploaded media.
+type MediaConfigProvider interface {
+	GetDirectory() string
+	GetBaseURL() string
+	GetMaxSize() int64
+}
+
+// TweetsConfigProvider provides tweets configuration.
+type TweetsConfigProvider interface {
+	GetEditWindow() time.Duration
+	GetMaxLength() int
+	GetDuplicateWindow() time.Duration
+	GetBannedTerms() []string
+	GetRestoreWindow() time.Duration
+}
+
+// SchedulerConfigProvider provides configuration of scheduled tweets publisher.
+type SchedulerConfigProvider interface {
+	GetInterval() time.Duration
+	GetBatchSize() int
+}
+
+// PurgerConfigProvider provides configuration of deleted tweets purger.
+type PurgerConfigProvider interface {
+	GetInterval() time.Duration
+	GetBatchSize() int
+}
+
+// AnalyticsConfigProvider provides configuration of tweets analytics.
+type AnalyticsConfigProvider interface {
+	GetFlushInterval() time.Duration
+}
+
+// TimelinesConfigProvider provides configuration of precomputed home timelines.
+type TimelinesConfigProvider interface {
+	GetSize() int
+	GetFanoutLimit() int
+	GetExpirationTime() time.Duration
+}
+
+// StreamConfigProvider provides configuration of live event streams.
+type StreamConfigProvider interface {
+	GetHeartbeatInterval() time.Duration
+	GetBufferSize() int
+}
+
+// GatewayConfigProvider provides configuration of WebSocket gateway.
+type GatewayConfigProvider interface {
+	GetHeartbeatInterval() time.Duration
+	GetHeartbeatTimeout() time.Duration
+	GetBufferSize() int
+	GetShutdownTimeout() time.Duration
+	GetTypingInterval() time.Duration
+}
@@ -132,6 +132,145 @@ func (config *elasticsearchConfig) GetPort() string {
 	return config.port
 }
 
+type mediaConfig struct {
+	directory string
+	baseURL   string
+	maxSize   int64
+}
+
+func (config *mediaConfig) GetDirectory() string {
+	return config.directory
+}
+
+func (config *mediaConfig) GetBaseURL() string {
+	return config.baseURL
+}
+
+func (config *mediaConfig) GetMaxSize() int64 {
+	return config.maxSize
+}
+
+type tweetsConfig struct {
+	editWindow      time.Duration
+	maxLength       int
+	duplicateWindow time.Duration
+	bannedTerms     []string
+	restoreWindow   time.Duration
+}
+
+func (config *tweetsConfig) GetEditWindow() time.Duration {
+	return config.editWindow
+}
+
+func (config *tweetsConfig) GetMaxLength() int {
+	return config.maxLength
+}
+
+func (config *tweetsConfig) GetDuplicateWindow() time.Duration {
+	return config.duplicateWindow
+}
+
+func (config *tweetsConfig) GetBannedTerms() []string {
+	return config.bannedTerms
+}
+
+func (config *tweetsConfig) GetRestoreWindow() time.Duration {
+	return config.restoreWindow
+}
+
+type schedulerConfig struct {
+	interval  time.Duration
+	batchSize int
+}
+
+func (config *schedulerConfig) GetInterval() time.Duration {
+	return config.interval
+}
+
+func (config *schedulerConfig) GetBatchSize() int {
+	return config.batchSize
+}
+
+type purgerConfig struct {
+	interval  time.Duration
+	batchSize int
+}
+
+func (config *purgerConfig) GetInterval() time.Duration {
+	return config.interval
+}
+
+func (config *purgerConfig) GetBatchSize() int {
+	return config.batchSize
+}
+
+type analyticsConfig struct {
+	flushInterval time.Duration
+}
+
+func (config *analyticsConfig) GetFlushInterval() time.Duration {
+	return config.flushInterval
+}
+
+type timelinesConfig struct {
+	size           int
+	fanoutLimit    int
+	expirationTime time.Duration
+}
+
+func (config *timelinesConfig) GetSize() int {
+	return config.size
+}
+
+func (config *timelinesConfig) GetFanoutLimit() int {
+	return config.fanoutLimit
+}
+
+func (config *timelinesConfig) GetExpirationTime() time.Duration {
+	return config.expirationTime
+}
+
+type streamConfig struct {
+	heartbeatInterval time.Duration
+	bufferSize        int
+}
+
+func (config *streamConfig) GetHeartbeatInterval() time.Duration {
+	return config.heartbeatInterval
+}
+
+func (config *streamConfig) GetBufferSize() int {
+	return config.bufferSize
+}
+
+type gatewayConfig struct {
+	heartbeatInterval time.Duration
+	heartbeatTimeout  time.Duration
+	bufferSize        int
+	shutdownTimeout   time.Duration
+	typingInterval    time.Duration
+}
+
+func (config *gatewayConfig) GetHeartbeatInterval() time.Duration {
+	return config.heartbeatInterval
+}
+
+func (config *gatewayConfig) GetHeartbeatTimeout() time.Duration {
+	return config.heartbeatTimeout
+}
+
+func (config *gatewayConfig) GetBufferSize() int {
+	return config.bufferSize
+}
+
+func (config *gatewayConfig) GetShutdownTimeout() time.Duration {
+	return config.shutdownTimeout
+}
+
+func (config *gatewayConfig) GetTypingInterval() time.Duration {
+	return config.typingInterval
+}
+
 type generalConfig struct {
 	*viper.Viper
 }
@@ -256,3 +395,157 @@ func (config *generalConfig) getElasticsearchConfig() *elasticsearchConfig {
 		port:     port,
 	}
 }
+
+func (config *generalConfig) getMediaConfig() *mediaConfig {
+	directory := config.GetString("media.directory")
+	baseURL := config.GetString("media.base_url")
+	maxSize := int64(config.GetInt("media.max_size"))
+
+	if directory == "" || baseURL == "" || maxSize <= 0 {
+		log.WithFields(log.Fields{
+			"directory": directory,
+			"base_url":  baseURL,
+			"max_size":  maxSize,
+		}).Fatal("Config file doesn't contain valid media data.")
+	}
+
+	return &mediaConfig{
+		directory: directory,
+		baseURL:   baseURL,
+		maxSize:   maxSize,
+	}
+}
+
+func (config *generalConfig) getTweetsConfig() *tweetsConfig {
+	editWindow := config.GetDuration("tweets.edit_window")
+	maxLength := config.GetInt("tweets.max_length")
+	duplicateWindow := config.GetDuration("tweets.duplicate_window")
+	bannedTerms := config.GetStringSlice("tweets.banned_terms")
+	restoreWindow := config.GetDuration("tweets.restore_window")
+
+	if editWindow < 0 || maxLength <= 0 || duplicateWindow < 0 || restoreWindow < 0 {
+		log.WithFields(log.Fields{
+			"edit window":      editWindow,
+			"max length":       maxLength,
+			"duplicate window": duplicateWindow,
+			"restore window":   restoreWindow,
+		}).Fatal("Config file doesn't contain valid tweets data.")
+	}
+
+	return &tweetsConfig{
+		editWindow:      editWindow,
+		maxLength:       maxLength,
+		duplicateWindow: duplicateWindow,
+		bannedTerms:     bannedTerms,
+		restoreWindow:   restoreWindow,
+	}
+}
+
+func (config *generalConfig) getSchedulerConfig() *schedulerConfig {
+	interval := config.GetDuration("scheduler.interval")
+	batchSize := config.GetInt("scheduler.batch_size")
+
+	if interval <= 0 || batchSize <= 0 {
+		log.WithFields(log.Fields{
+			"interval":   interval,
+			"batch size": batchSize,
+		}).Fatal("Config file doesn't contain valid scheduler data.")
+	}
+
+	return &schedulerConfig{
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (config *generalConfig) getPurgerConfig() *purgerConfig {
+	interval := config.GetDuration("purger.interval")
+	batchSize := config.GetInt("purger.batch_size")
+
+	if interval <= 0 || batchSize <= 0 {
+		log.WithFields(log.Fields{
+			"interval":   interval,
+			"batch size": batchSize,
+		}).Fatal("Config file doesn't contain valid purger data.")
+	}
+
+	return &purgerConfig{
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (config *generalConfig) getAnalyticsConfig() *analyticsConfig {
+	flushInterval := config.GetDuration("analytics.flush_interval")
+
+	if flushInterval <= 0 {
+		log.WithField("flush interval", flushInterval).Fatal("Config file doesn't contain valid analytics data.")
+	}
+
+	return &analyticsConfig{
+		flushInterval: flushInterval,
+	}
+}
+
+func (config *generalConfig) getTimelinesConfig() *timelinesConfig {
+	size := config.GetInt("timelines.size")
+	fanoutLimit := config.GetInt("timelines.fanout_limit")
+	expirationTime := config.GetDuration("timelines.expiration_time")
+
+	if size <= 0 || fanoutLimit < 0 || expirationTime <= 0 {
+		log.WithFields(log.Fields{
+			"size":            size,
+			"fanout limit":    fanoutLimit,
+			"expiration time": expirationTime,
+		}).Fatal("Config file doesn't contain valid timelines data.")
+	}
+
+	return &timelinesConfig{
+		size:           size,
+		fanoutLimit:    fanoutLimit,
+		expirationTime: expirationTime,
+	}
+}
+
+func (config *generalConfig) getStreamConfig() *streamConfig {
+	heartbeatInterval := config.GetDuration("stream.heartbeat_interval")
+	bufferSize := config.GetInt("stream.buffer_size")
+
+	if heartbeatInterval <= 0 || bufferSize <= 0 {
+		log.WithFields(log.Fields{
+			"heartbeat interval": heartbeatInterval,
+			"buffer size":        bufferSize,
+		}).Fatal("Config file doesn't contain valid stream data.")
+	}
+
+	return &streamConfig{
+		heartbeatInterval: heartbeatInterval,
+		bufferSize:        bufferSize,
+	}
+}
+
+func (config *generalConfig) getGatewayConfig() *gatewayConfig {
+	heartbeatInterval := config.GetDuration("gateway.heartbeat_interval")
+	heartbeatTimeout := config.GetDuration("gateway.heartbeat_timeout")
+	bufferSize := config.GetInt("gateway.buffer_size")
+	shutdownTimeout := config.GetDuration("gateway.shutdown_timeout")
+	typingInterval := config.GetDuration("gateway.typing_interval")
+
+	if heartbeatInterval <= 0 || heartbeatTimeout <= heartbeatInterval || bufferSize <= 0 || shutdownTimeout <= 0 || typingInterval <= 0 {
+		log.WithFields(log.Fields{
+			"heartbeat interval": heartbeatInterval,
+			"heartbeat timeout":  heartbeatTimeout,
+			"buffer size":        bufferSize,
+			"shutdown timeout":   shutdownTimeout,
+			"typing interval":    typingInterval,
+		}).Fatal("Config file doesn't contain valid gateway data.")
+	}
+
+	return &gatewayConfig{
+		heartbeatInterval: heartbeatInterval,
+		heartbeatTimeout:  heartbeatTimeout,
+		bufferSize:        bufferSize,
+		shutdownTimeout:   shutdownTimeout,
+		typingInterval:    typingInterval,
+	}
+}
@@ -0,0 +1,70 @@
+// Package entities extracts hashtags, mentions and URLs from the content of
+// tweets.
+//
+// Positions of all entities are expressed in Unicode code points (not bytes
+// and not UTF-16 units). Start points at the first character of the entity
+// (including leading `#` or `@`) and End points right after its last
+// character, so content[Start:End] (on runes) is the text of the entity.
+package entities
+
+import "strings"
+
+// Hashtag is `#tag` found in the content. Text does not contain leading `#`.
+type Hashtag struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Mention is `@username` found in the content. Username does not contain
+// leading `@`.
+type Mention struct {
+	Username string `json:"username"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
+// URL is link found in the content. URL is exactly the text that was written.
+type URL struct {
+	URL   string `json:"url"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Entities are all entities found in the content sorted by their position.
+type Entities struct {
+	Hashtags []*Hashtag `json:"hashtags"`
+	Mentions []*Mention `json:"mentions"`
+	URLs     []*URL     `json:"urls"`
+}
+
+// HashtagsNames returns distinct lowercased texts of the hashtags.
+func (e *Entities) HashtagsNames() []string {
+	names := make([]string, 0, len(e.Hashtags))
+	seen := make(map[string]bool)
+
+	for _, hashtag := range e.Hashtags {
+		name := strings.ToLower(hashtag.Text)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Usernames returns distinct usernames of the mentions.
+func (e *Entities) Usernames() []string {
+	usernames := make([]string, 0, len(e.Mentions))
+	seen := make(map[string]bool)
+
+	for _, mention := range e.Mentions {
+		if !seen[mention.Username] {
+			seen[mention.Username] = true
+			usernames = append(usernames, mention.Username)
+		}
+	}
+
+	return usernames
+}
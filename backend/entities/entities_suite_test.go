@@ -0,0 +1,13 @@
+package entities
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEntities(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Entities")
+}
@@ -0,0 +1,149 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+)
+
+var urlPrefixes = []string{"https://", "http://", "www."}
+
+// Parse finds all entities in the content. URLs are found first and hashtags
+// or mentions which are part of the URL are ignored.
+func Parse(content string) *Entities {
+	entities := &Entities{
+		Hashtags: make([]*Hashtag, 0),
+		Mentions: make([]*Mention, 0),
+		URLs:     make([]*URL, 0),
+	}
+
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		if i > 0 && isWordRune(runes[i-1]) {
+			continue
+		}
+
+		if end := matchURL(runes, i); end > i {
+			entities.URLs = append(entities.URLs, &URL{string(runes[i:end]), i, end})
+			i = end - 1
+		} else if end := matchHashtag(runes, i); end > i {
+			entities.Hashtags = append(entities.Hashtags, &Hashtag{string(runes[i+1 : end]), i, end})
+			i = end - 1
+		} else if end := matchMention(runes, i); end > i {
+			entities.Mentions = append(entities.Mentions, &Mention{string(runes[i+1 : end]), i, end})
+			i = end - 1
+		}
+	}
+
+	return entities
+}
+
+// matchURL returns end of the URL starting at `start` or `start` if there is no URL.
+func matchURL(runes []rune, start int) int {
+	prefixLen := 0
+	for _, prefix := range urlPrefixes {
+		n := len([]rune(prefix))
+		if start+n <= len(runes) && strings.EqualFold(string(runes[start:start+n]), prefix) {
+			prefixLen = n
+			break
+		}
+	}
+	if prefixLen == 0 {
+		return start
+	}
+
+	end := start + prefixLen
+	for end < len(runes) && isURLRune(runes[end]) {
+		end++
+	}
+
+	// Trailing punctuation most likely belongs to the sentence and not to the
+	// URL. Closing parenthesis is kept only when it has its opening pair.
+	for end > start+prefixLen {
+		last := runes[end-1]
+		if strings.ContainsRune(".,:;!?'\"", last) {
+			end--
+		} else if last == ')' && countRune(runes[start:end], ')') > countRune(runes[start:end], '(') {
+			end--
+		} else {
+			break
+		}
+	}
+
+	// there has to be at least one character of the host
+	if end == start+prefixLen || !isWordRune(runes[start+prefixLen]) {
+		return start
+	}
+
+	return end
+}
+
+// matchHashtag returns end of the hashtag starting at `start` or `start` if there is no hashtag.
+// Hashtag cannot consist only of digits.
+func matchHashtag(runes []rune, start int) int {
+	if runes[start] != '#' && runes[start] != '＃' {
+		return start
+	}
+
+	end := start + 1
+	hasNonDigit := false
+	for end < len(runes) && isWordRune(runes[end]) {
+		hasNonDigit = hasNonDigit || !unicode.IsDigit(runes[end])
+		end++
+	}
+
+	if !hasNonDigit {
+		return start
+	}
+
+	return end
+}
+
+// matchMention returns end of the mention starting at `start` or `start` if there is no mention.
+// Mention followed by `@` is treated as e-mail address and ignored. The same
+// applies to mentions followed by combining mark since the username would be cut.
+func matchMention(runes []rune, start int) int {
+	if runes[start] != '@' && runes[start] != '＠' {
+		return start
+	}
+
+	end := start + 1
+	for end < len(runes) && isUsernameRune(runes[end]) {
+		end++
+	}
+
+	if end == start+1 || (end < len(runes) && (runes[end] == '@' || runes[end] == '＠' || isWordRune(runes[end]))) {
+		return start
+	}
+
+	return end
+}
+
+// isWordRune reports whether rune can be part of a word. Combining marks are
+// part of the word so accented letters written with them are not split, but
+// variation selectors (used eg. in emoji) are not.
+func isWordRune(r rune) bool {
+	if unicode.Is(unicode.Variation_Selector, r) {
+		return false
+	}
+
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc)
+}
+
+func isUsernameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isURLRune(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsControl(r) && !strings.ContainsRune("<>\"", r)
+}
+
+func countRune(runes []rune, r rune) int {
+	count := 0
+	for _, x := range runes {
+		if x == r {
+			count++
+		}
+	}
+
+	return count
+}
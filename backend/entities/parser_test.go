@@ -0,0 +1,176 @@
+package entities
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parser", func() {
+	Describe("Hashtags", func() {
+		It("should find hashtags with positions", func() {
+			entities := Parse("#hello world #chirp")
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{
+				{"hello", 0, 6},
+				{"chirp", 13, 19},
+			}))
+		})
+
+		It("should stop hashtag on punctuation", func() {
+			entities := Parse("(#first), #second! #third.")
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{
+				{"first", 1, 7},
+				{"second", 10, 17},
+				{"third", 19, 25},
+			}))
+		})
+
+		It("should not find hashtags inside words or made only of digits", func() {
+			entities := Parse("abc#def #123 # ## #_1 #1a")
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{
+				{"_1", 18, 21},
+				{"1a", 22, 25},
+			}))
+		})
+
+		It("should find hashtags with non-latin letters", func() {
+			entities := Parse("#zażółć #日本語 #ĉu")
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{
+				{"zażółć", 0, 7},
+				{"日本語", 8, 12},
+				{"ĉu", 13, 16},
+			}))
+		})
+
+		It("should find fullwidth hashtags", func() {
+			entities := Parse("＃東京")
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{{"東京", 0, 3}}))
+		})
+
+		It("should keep combining characters in hashtags", func() {
+			// "e" followed by U+0301 COMBINING ACUTE ACCENT counts as 2 code points
+			entities := Parse("#cafe\u0301 #naïve")
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{
+				{"café", 0, 6},
+				{"naïve", 7, 13},
+			}))
+		})
+
+		It("should not start hashtag after combining character", func() {
+			entities := Parse("e\u0301#tag")
+			Expect(entities.Hashtags).To(BeEmpty())
+		})
+
+		It("should count emoji as code points", func() {
+			// 👍🏽 is 2 code points, 👨‍👩‍👧 (family) is 5 code points
+			entities := Parse("🎉#party🎉 👍🏽#go 👨‍👩‍👧 #fam")
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{
+				{"party", 1, 7},
+				{"go", 11, 14},
+				{"fam", 21, 25},
+			}))
+		})
+
+		It("should not treat keycap emoji or variation selectors as hashtags", func() {
+			entities := Parse("#\ufe0f\u20e3 #go\ufe0f")
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{{"go", 4, 7}}))
+		})
+	})
+
+	Describe("Mentions", func() {
+		It("should find mentions with positions", func() {
+			entities := Parse("@ala hello @bob_1")
+			Expect(entities.Mentions).To(Equal([]*Mention{
+				{"ala", 0, 4},
+				{"bob_1", 11, 17},
+			}))
+		})
+
+		It("should stop mention on punctuation", func() {
+			entities := Parse("(@ala), @bob! @toor.")
+			Expect(entities.Mentions).To(Equal([]*Mention{
+				{"ala", 1, 5},
+				{"bob", 8, 12},
+				{"toor", 14, 19},
+			}))
+		})
+
+		It("should not find e-mail addresses and lone `@`", func() {
+			entities := Parse("ala@email.com @ @@bob @ala@host")
+			Expect(entities.Mentions).To(Equal([]*Mention{{"bob", 17, 21}}))
+		})
+
+		It("should not cut username on combining character", func() {
+			entities := Parse("@jose\u0301 @ＡＢＣ")
+			Expect(entities.Mentions).To(Equal([]*Mention{{"ＡＢＣ", 7, 11}}))
+		})
+
+		It("should count emoji as code points", func() {
+			entities := Parse("👋👋@ala")
+			Expect(entities.Mentions).To(Equal([]*Mention{{"ala", 2, 6}}))
+		})
+	})
+
+	Describe("URLs", func() {
+		It("should find URLs with positions", func() {
+			entities := Parse("see https://chirp.com/a?b=c and http://x.pl or www.go.dev")
+			Expect(entities.URLs).To(Equal([]*URL{
+				{"https://chirp.com/a?b=c", 4, 27},
+				{"http://x.pl", 32, 43},
+				{"www.go.dev", 47, 57},
+			}))
+		})
+
+		It("should strip trailing punctuation", func() {
+			entities := Parse("http://a.com/x. (http://b.com) \"http://c.com\"!")
+			Expect(entities.URLs).To(Equal([]*URL{
+				{"http://a.com/x", 0, 14},
+				{"http://b.com", 17, 29},
+				{"http://c.com", 32, 44},
+			}))
+		})
+
+		It("should keep balanced parentheses", func() {
+			entities := Parse("http://wiki.org/Go_(language)")
+			Expect(entities.URLs).To(Equal([]*URL{{"http://wiki.org/Go_(language)", 0, 29}}))
+		})
+
+		It("should not find hashtags and mentions inside URLs", func() {
+			entities := Parse("http://a.com/#top http://u@b.com #real")
+			Expect(entities.URLs).To(Equal([]*URL{
+				{"http://a.com/#top", 0, 17},
+				{"http://u@b.com", 18, 32},
+			}))
+			Expect(entities.Hashtags).To(Equal([]*Hashtag{{"real", 33, 38}}))
+			Expect(entities.Mentions).To(BeEmpty())
+		})
+
+		It("should not find URLs without host or inside words", func() {
+			entities := Parse("http:// https://. xhttp://a.com")
+			Expect(entities.URLs).To(BeEmpty())
+		})
+
+		It("should find URLs with emoji and case insensitive scheme", func() {
+			entities := Parse("🔗HTTPS://a.com/🎉")
+			Expect(entities.URLs).To(Equal([]*URL{{"HTTPS://a.com/🎉", 1, 16}}))
+		})
+	})
+
+	Describe("Entities", func() {
+		It("should return empty lists for content without entities", func() {
+			entities := Parse("")
+			Expect(entities.Hashtags).To(BeEmpty())
+			Expect(entities.Mentions).To(BeEmpty())
+			Expect(entities.URLs).To(BeEmpty())
+		})
+
+		It("should return distinct lowercased hashtags names", func() {
+			entities := Parse("#Go #go #GO #db")
+			Expect(entities.HashtagsNames()).To(Equal([]string{"go", "db"}))
+		})
+
+		It("should return distinct usernames", func() {
+			entities := Parse("@ala @Ala @ala")
+			Expect(entities.Usernames()).To(Equal([]string{"ala", "Ala"}))
+		})
+	})
+})
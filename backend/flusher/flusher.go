@@ -0,0 +1,45 @@
+package flusher
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/async"
+	"github.com/VirrageS/chirp/backend/config"
+)
+
+// Saver saves buffered tweets stats in the database and returns how many of
+// them were saved.
+type Saver interface {
+	FlushTweetStats() (int, error)
+}
+
+// Flusher periodically saves buffered tweets stats in the background. Saver
+// has to make sure that the same counts are never saved twice, so it is safe
+// to run flusher on each replica of the server.
+type Flusher struct {
+	*async.Periodic
+
+	saver Saver
+}
+
+// New creates new instance of `Flusher` which uses given saver.
+func New(saver Saver, config config.AnalyticsConfigProvider) *Flusher {
+	f := &Flusher{
+		saver: saver,
+	}
+	f.Periodic = async.NewPeriodic(config.GetFlushInterval(), f.flush)
+	return f
+}
+
+// Stop stops flushing and waits until current flush is finished. Stats
+// buffered since the last flush are saved before returning.
+func (f *Flusher) Stop() {
+	f.Periodic.Stop()
+	f.flush()
+}
+
+func (f *Flusher) flush() {
+	if _, err := f.saver.FlushTweetStats(); err != nil {
+		log.WithError(err).Error("Failed to flush tweets stats.")
+	}
+}
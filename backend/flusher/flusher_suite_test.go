@@ -0,0 +1,13 @@
+package flusher
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFlusher(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Flusher")
+}
@@ -0,0 +1,80 @@
+package flusher
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeConfig struct{}
+
+func (*fakeConfig) GetFlushInterval() time.Duration {
+	return 10 * time.Millisecond
+}
+
+// fakeSaver counts how many times stats were flushed.
+type fakeSaver struct {
+	sync.Mutex
+	calls int
+	err   error
+}
+
+func (s *fakeSaver) FlushTweetStats() (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.calls++
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	return 1, nil
+}
+
+func (s *fakeSaver) getCalls() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.calls
+}
+
+var _ = Describe("Flusher", func() {
+	var (
+		saver   *fakeSaver
+		flusher *Flusher
+	)
+
+	BeforeEach(func() {
+		saver = &fakeSaver{}
+		flusher = New(saver, &fakeConfig{})
+	})
+
+	It("should flush stats periodically", func() {
+		flusher.Start()
+		defer flusher.Stop()
+
+		Eventually(saver.getCalls).Should(BeNumerically(">=", 2))
+	})
+
+	It("should keep flushing after saver failed", func() {
+		saver.err = errors.New("error")
+
+		flusher.Start()
+		defer flusher.Stop()
+
+		Eventually(saver.getCalls).Should(BeNumerically(">=", 2))
+	})
+
+	It("should flush once more when stopped and not after that", func() {
+		flusher.Start()
+		flusher.Stop()
+
+		calls := saver.getCalls()
+		Expect(calls).To(BeNumerically(">=", 1))
+
+		time.Sleep(50 * time.Millisecond)
+		Expect(saver.getCalls()).To(Equal(calls))
+	})
+})
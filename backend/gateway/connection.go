@@ -0,0 +1,200 @@
+package gateway
+
+import (
+	"encoding/json"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/websocket"
+
+	"github.com/VirrageS/chirp/backend/stream"
+)
+
+// Types of messages sent by the clients.
+const (
+	heartbeatMessage = "heartbeat"
+	typingMessage    = "typing"
+)
+
+// Types of replies to the messages of the clients.
+const (
+	ackMessage   = "ack"
+	errorMessage = "error"
+)
+
+// clientMessage is a message sent by the client.
+type clientMessage struct {
+	// ID is chosen by the client and sent back in the reply.
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	RecipientID int64  `json:"recipient_id"`
+}
+
+// serverMessage is either a reply to the message of the client or an event.
+type serverMessage struct {
+	ID    string      `json:"id,omitempty"`
+	Type  string      `json:"type"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// connection is a single WebSocket connection. Messages are read in one
+// goroutine and written in another since connection supports only one
+// concurrent reader and one concurrent writer.
+type connection struct {
+	gateway      *Gateway
+	conn         *websocket.Conn
+	userID       int64
+	subscription *stream.Subscription
+	replies      chan *serverMessage
+	// done is closed when reading stops. If it stops because of the client
+	// which does not read replies, closeCode is set.
+	done      chan struct{}
+	closeCode int
+	// typedAt is time of the last typing indicator relayed to each recipient.
+	// It is used only by the reading goroutine.
+	typedAt map[int64]time.Time
+}
+
+// read handles messages of the client until the connection is closed or the
+// client stops responding. Any message (including pongs) counts as heartbeat.
+func (c *connection) read() {
+	defer close(c.done)
+
+	timeout := c.gateway.config.GetHeartbeatTimeout()
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(timeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(timeout))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			// clients often drop connections without closing them properly
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.WithField("userID", c.userID).WithError(err).Error("read: connection closed unexpectedly")
+			}
+			return
+		}
+		c.conn.SetReadDeadline(time.Now().Add(timeout))
+
+		select {
+		case c.replies <- c.handle(data):
+		default:
+			// client sends messages without reading replies
+			c.closeCode = websocket.ClosePolicyViolation
+			return
+		}
+	}
+}
+
+// handle handles single message of the client and returns reply to it.
+func (c *connection) handle(data []byte) *serverMessage {
+	var message clientMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return &serverMessage{Type: errorMessage, Error: "Invalid message."}
+	}
+
+	var err error
+	switch message.Type {
+	case heartbeatMessage:
+	case typingMessage:
+		if message.RecipientID == 0 {
+			return &serverMessage{ID: message.ID, Type: errorMessage, Error: "Field recipient_id is required."}
+		}
+		if !c.throttleTyping(message.RecipientID) {
+			err = c.gateway.backend.SendTyping(c.userID, message.RecipientID)
+		}
+	default:
+		return &serverMessage{ID: message.ID, Type: errorMessage, Error: "Unknown message type."}
+	}
+
+	if err != nil {
+		return &serverMessage{ID: message.ID, Type: errorMessage, Error: err.Error()}
+	}
+
+	return &serverMessage{ID: message.ID, Type: ackMessage}
+}
+
+// throttleTyping checks if typing indicator to the recipient should be dropped
+// because the previous one was relayed less than typing interval ago. Clients
+// usually send the indicator on every key press so dropped indicators are
+// still acknowledged.
+func (c *connection) throttleTyping(recipientID int64) bool {
+	now := time.Now()
+	interval := c.gateway.config.GetTypingInterval()
+	if typedAt, ok := c.typedAt[recipientID]; ok && now.Sub(typedAt) < interval {
+		return true
+	}
+
+	// forget recipients the user stopped typing to
+	for id, typedAt := range c.typedAt {
+		if now.Sub(typedAt) >= interval {
+			delete(c.typedAt, id)
+		}
+	}
+
+	c.typedAt[recipientID] = now
+	return false
+}
+
+// write sends events and replies to the client and pings it periodically. It
+// returns when the connection should be closed.
+func (c *connection) write() {
+	ticker := time.NewTicker(c.gateway.config.GetHeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-c.subscription.Events():
+			if !ok {
+				// client does not keep up with the events, it should reconnect
+				c.close(websocket.CloseTryAgainLater, "Too many events.")
+				return
+			}
+
+			if !c.send(&serverMessage{Type: event.Type, Data: event.Data}) {
+				return
+			}
+		case reply := <-c.replies:
+			if !c.send(reply) {
+				return
+			}
+		case <-ticker.C:
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout))
+			if err != nil {
+				return
+			}
+		case <-c.done:
+			if c.closeCode != 0 {
+				c.close(c.closeCode, "Too many messages.")
+			}
+			return
+		case <-c.gateway.shutdown:
+			c.close(websocket.CloseGoingAway, "Server is shutting down.")
+
+			// wait for the client to close the connection
+			select {
+			case <-c.done:
+			case <-time.After(c.gateway.config.GetShutdownTimeout()):
+			}
+			return
+		}
+	}
+}
+
+func (c *connection) send(message *serverMessage) bool {
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := c.conn.WriteJSON(message); err != nil {
+		log.WithField("userID", c.userID).WithError(err).Error("send: failed to write message")
+		return false
+	}
+
+	return true
+}
+
+func (c *connection) close(code int, text string) {
+	message := websocket.FormatCloseMessage(code, text)
+	c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeTimeout))
+}
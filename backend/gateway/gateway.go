@@ -0,0 +1,126 @@
+package gateway
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/gorilla/websocket"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/stream"
+)
+
+const (
+	// Maximal size of the message sent by the client.
+	maxMessageSize = 4096
+
+	// Time allowed to write a single message to the client.
+	writeTimeout = 10 * time.Second
+)
+
+// Backend is the part of the service used by the gateway.
+type Backend interface {
+	SubscribeInbox(userID int64) (*stream.Subscription, error)
+	SendTyping(userID, recipientID int64) error
+}
+
+// Gateway serves WebSocket connections over which clients receive events
+// addressed to them (notifications, typing indicators) and send their own
+// messages which are acknowledged.
+type Gateway struct {
+	backend  Backend
+	config   config.GatewayConfigProvider
+	upgrader websocket.Upgrader
+
+	mutex    sync.Mutex
+	closing  bool
+	shutdown chan struct{}
+	// connections counts connections which are being served
+	connections sync.WaitGroup
+}
+
+// New creates new gateway which uses `backend` to subscribe to events and to
+// handle messages of the clients.
+func New(backend Backend, config config.GatewayConfigProvider) *Gateway {
+	return &Gateway{
+		backend: backend,
+		config:  config,
+		upgrader: websocket.Upgrader{
+			// clients are authorized with token, not cookies, so requests
+			// from other origins can not be forged
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		shutdown: make(chan struct{}),
+	}
+}
+
+// Serve upgrades the request of the user to WebSocket connection and serves
+// it until it is closed. Error is returned only if the request could not be
+// upgraded and no response has been written yet.
+func (gateway *Gateway) Serve(w http.ResponseWriter, r *http.Request, userID int64) error {
+	gateway.mutex.Lock()
+	if gateway.closing {
+		gateway.mutex.Unlock()
+		return errors.ShuttingDownError
+	}
+	gateway.connections.Add(1)
+	gateway.mutex.Unlock()
+
+	defer gateway.connections.Done()
+
+	subscription, err := gateway.backend.SubscribeInbox(userID)
+	if err != nil {
+		return err
+	}
+	defer subscription.Close()
+
+	conn, err := gateway.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// upgrader has already responded with an error
+		log.WithField("userID", userID).WithError(err).Error("Serve: failed to upgrade connection")
+		return nil
+	}
+	defer conn.Close()
+
+	c := &connection{
+		gateway:      gateway,
+		conn:         conn,
+		userID:       userID,
+		subscription: subscription,
+		replies:      make(chan *serverMessage, gateway.config.GetBufferSize()),
+		done:         make(chan struct{}),
+		typedAt:      make(map[int64]time.Time),
+	}
+
+	go c.read()
+	c.write()
+	return nil
+}
+
+// Shutdown closes all connections and refuses new ones. It waits until the
+// clients close the connections, but not longer than the shutdown timeout.
+func (gateway *Gateway) Shutdown() {
+	gateway.mutex.Lock()
+	if gateway.closing {
+		gateway.mutex.Unlock()
+		return
+	}
+	gateway.closing = true
+	close(gateway.shutdown)
+	gateway.mutex.Unlock()
+
+	closed := make(chan struct{})
+	go func() {
+		gateway.connections.Wait()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(gateway.config.GetShutdownTimeout()):
+		log.Warn("Shutdown: some gateway connections were not closed in time")
+	}
+}
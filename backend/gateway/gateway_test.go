@@ -0,0 +1,301 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/stream"
+)
+
+type fakeConfig struct {
+	heartbeatInterval time.Duration
+	heartbeatTimeout  time.Duration
+	bufferSize        int
+	shutdownTimeout   time.Duration
+	typingInterval    time.Duration
+}
+
+func (config *fakeConfig) GetHeartbeatInterval() time.Duration {
+	return config.heartbeatInterval
+}
+
+func (config *fakeConfig) GetHeartbeatTimeout() time.Duration {
+	return config.heartbeatTimeout
+}
+
+func (config *fakeConfig) GetBufferSize() int {
+	return config.bufferSize
+}
+
+func (config *fakeConfig) GetShutdownTimeout() time.Duration {
+	return config.shutdownTimeout
+}
+
+func (config *fakeConfig) GetTypingInterval() time.Duration {
+	return config.typingInterval
+}
+
+// fakeBackend relays typing indicators through the hub. User with ID 404 does
+// not exist.
+type fakeBackend struct {
+	hub           *stream.Hub
+	mutex         sync.Mutex
+	subscriptions map[int64]*stream.Subscription
+}
+
+func (backend *fakeBackend) SubscribeInbox(userID int64) (*stream.Subscription, error) {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	subscription := backend.hub.Subscribe(stream.InboxTopic(userID))
+	backend.subscriptions[userID] = subscription
+	return subscription, nil
+}
+
+func (backend *fakeBackend) SendTyping(userID, recipientID int64) error {
+	if recipientID == 404 {
+		return errors.NoResultsError
+	}
+
+	backend.hub.Publish(stream.InboxTopic(recipientID), &stream.Event{
+		Type: stream.Typing,
+		Data: &stream.TypingData{UserID: userID},
+	})
+	return nil
+}
+
+func (backend *fakeBackend) subscription(userID int64) *stream.Subscription {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
+	return backend.subscriptions[userID]
+}
+
+var _ = Describe("Gateway", func() {
+	var (
+		backend *fakeBackend
+		gateway *Gateway
+		server  *httptest.Server
+	)
+
+	connect := func(userID int64) (*websocket.Conn, *http.Response, error) {
+		url := fmt.Sprintf("ws%s?user_id=%d", strings.TrimPrefix(server.URL, "http"), userID)
+		return websocket.DefaultDialer.Dial(url, nil)
+	}
+
+	mustConnect := func(userID int64) *websocket.Conn {
+		conn, _, err := connect(userID)
+		Expect(err).NotTo(HaveOccurred())
+
+		// subscription is created before the connection is upgraded
+		Expect(backend.subscription(userID)).NotTo(BeNil())
+		return conn
+	}
+
+	receive := func(conn *websocket.Conn) map[string]interface{} {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+
+		var message map[string]interface{}
+		Expect(conn.ReadJSON(&message)).To(Succeed())
+		return message
+	}
+
+	BeforeEach(func() {
+		backend = &fakeBackend{
+			hub:           stream.NewHub(stream.NewLocalBroker(), 2),
+			subscriptions: make(map[int64]*stream.Subscription),
+		}
+		gateway = New(backend, &fakeConfig{
+			heartbeatInterval: 50 * time.Millisecond,
+			heartbeatTimeout:  200 * time.Millisecond,
+			bufferSize:        2,
+			shutdownTimeout:   time.Second,
+			typingInterval:    100 * time.Millisecond,
+		})
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, _ := strconv.ParseInt(r.URL.Query().Get("user_id"), 10, 64)
+			if err := gateway.Serve(w, r, userID); err != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should acknowledge messages of the client", func() {
+		conn := mustConnect(1)
+		defer conn.Close()
+
+		Expect(conn.WriteJSON(map[string]interface{}{"id": "1", "type": "heartbeat"})).To(Succeed())
+		Expect(receive(conn)).To(Equal(map[string]interface{}{"id": "1", "type": "ack"}))
+	})
+
+	It("should reply with error to invalid messages", func() {
+		conn := mustConnect(1)
+		defer conn.Close()
+
+		Expect(conn.WriteMessage(websocket.TextMessage, []byte("{"))).To(Succeed())
+		Expect(receive(conn)).To(Equal(map[string]interface{}{"type": "error", "error": "Invalid message."}))
+
+		Expect(conn.WriteJSON(map[string]interface{}{"id": "2", "type": "unknown"})).To(Succeed())
+		Expect(receive(conn)).To(Equal(map[string]interface{}{"id": "2", "type": "error", "error": "Unknown message type."}))
+
+		Expect(conn.WriteJSON(map[string]interface{}{"id": "3", "type": "typing"})).To(Succeed())
+		Expect(receive(conn)).To(Equal(map[string]interface{}{"id": "3", "type": "error", "error": "Field recipient_id is required."}))
+
+		Expect(conn.WriteJSON(map[string]interface{}{"id": "4", "type": "typing", "recipient_id": 404})).To(Succeed())
+		Expect(receive(conn)).To(Equal(map[string]interface{}{"id": "4", "type": "error", "error": errors.NoResultsError.Error()}))
+	})
+
+	It("should relay typing indicators to the recipient", func() {
+		sender := mustConnect(1)
+		defer sender.Close()
+		recipient := mustConnect(2)
+		defer recipient.Close()
+
+		Expect(sender.WriteJSON(map[string]interface{}{"id": "1", "type": "typing", "recipient_id": 2})).To(Succeed())
+		Expect(receive(sender)).To(Equal(map[string]interface{}{"id": "1", "type": "ack"}))
+		Expect(receive(recipient)).To(Equal(map[string]interface{}{
+			"type": "typing",
+			"data": map[string]interface{}{"user_id": float64(1)},
+		}))
+	})
+
+	It("should drop typing indicators sent to the same recipient too often", func() {
+		sender := mustConnect(1)
+		defer sender.Close()
+		recipient := mustConnect(2)
+		defer recipient.Close()
+		other := mustConnect(3)
+		defer other.Close()
+
+		typing := map[string]interface{}{
+			"type": "typing",
+			"data": map[string]interface{}{"user_id": float64(1)},
+		}
+
+		for i, recipientID := range []int64{2, 2, 3} {
+			id := strconv.Itoa(i + 1)
+			Expect(sender.WriteJSON(map[string]interface{}{"id": id, "type": "typing", "recipient_id": recipientID})).To(Succeed())
+			Expect(receive(sender)).To(Equal(map[string]interface{}{"id": id, "type": "ack"}))
+		}
+		Expect(receive(other)).To(Equal(typing))
+
+		time.Sleep(100 * time.Millisecond)
+		Expect(sender.WriteJSON(map[string]interface{}{"id": "4", "type": "typing", "recipient_id": 2})).To(Succeed())
+		Expect(receive(sender)).To(Equal(map[string]interface{}{"id": "4", "type": "ack"}))
+
+		// only the first and the last indicators are relayed
+		Expect(receive(recipient)).To(Equal(typing))
+		Expect(receive(recipient)).To(Equal(typing))
+		recipient.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		_, _, err := recipient.ReadMessage()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should send events addressed to the user", func() {
+		conn := mustConnect(1)
+		defer conn.Close()
+
+		backend.hub.Publish(stream.InboxTopic(1), &stream.Event{
+			Type: stream.Notification,
+			Data: &stream.NotificationData{Kind: stream.FollowNotification, UserID: 2},
+		})
+
+		Expect(receive(conn)).To(Equal(map[string]interface{}{
+			"type": "notification",
+			"data": map[string]interface{}{"kind": "follow", "user_id": float64(2)},
+		}))
+	})
+
+	It("should ping the client and keep the connection while it responds", func() {
+		conn := mustConnect(1)
+		defer conn.Close()
+
+		pings := make(chan string, 5)
+		conn.SetPingHandler(func(data string) error {
+			pings <- data
+			return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		})
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		// heartbeat timeout passes several times
+		Eventually(pings).Should(HaveLen(5))
+
+		Expect(conn.WriteJSON(map[string]interface{}{"id": "1", "type": "heartbeat"})).To(Succeed())
+	})
+
+	It("should close connection of the client which stopped responding", func() {
+		conn := mustConnect(1)
+		defer conn.Close()
+
+		// pings are not answered while the client does not read
+		time.Sleep(300 * time.Millisecond)
+
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				Expect(websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway)).To(BeFalse())
+				break
+			}
+		}
+	})
+
+	It("should close connection when the client can not keep up with events", func() {
+		conn := mustConnect(1)
+		defer conn.Close()
+
+		backend.subscription(1).Close()
+
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, _, err := conn.ReadMessage()
+		Expect(websocket.IsCloseError(err, websocket.CloseTryAgainLater)).To(BeTrue())
+	})
+
+	It("should close connections and refuse new ones on shutdown", func() {
+		conn := mustConnect(1)
+		defer conn.Close()
+
+		closed := make(chan error, 1)
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					closed <- err
+					return
+				}
+			}
+		}()
+
+		gateway.Shutdown()
+
+		var err error
+		Eventually(closed).Should(Receive(&err))
+		Expect(websocket.IsCloseError(err, websocket.CloseGoingAway)).To(BeTrue())
+
+		_, response, err := connect(2)
+		Expect(err).To(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+
+		// shutdown can be called more than once
+		gateway.Shutdown()
+	})
+})
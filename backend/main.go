@@ -24,5 +24,7 @@ func init() {
 
 func main() {
 	s := server.New()
-	s.Run(":8080")
+	if err := s.Run(":8080"); err != nil {
+		logrus.WithError(err).Fatal("Server stopped unexpectedly.")
+	}
 }
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Granularities of the analytics timeline.
+const (
+	GranularityHour = "hour"
+	GranularityDay  = "day"
+)
+
+// TweetStats are events of the tweet which are not stored anywhere else,
+// counted during single hour.
+type TweetStats struct {
+	TweetID       int64
+	Hour          time.Time
+	Impressions   int64
+	ProfileClicks int64
+}
+
+// AnalyticsPoint holds counts of the tweet events within single period of
+// time (hour or day) which starts at `Time`.
+type AnalyticsPoint struct {
+	Time          time.Time `json:"time"`
+	Impressions   int64     `json:"impressions"`
+	Likes         int64     `json:"likes"`
+	Retweets      int64     `json:"retweets"`
+	ProfileClicks int64     `json:"profile_clicks"`
+}
+
+// TweetAnalytics is a summary of the tweet events available to its author.
+type TweetAnalytics struct {
+	TweetID       int64             `json:"tweet_id"`
+	Impressions   int64             `json:"impressions"`
+	Likes         int64             `json:"likes"`
+	Retweets      int64             `json:"retweets"`
+	ProfileClicks int64             `json:"profile_clicks"`
+	Granularity   string            `json:"granularity"`
+	Timeline      []*AnalyticsPoint `json:"timeline"`
+}
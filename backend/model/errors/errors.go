@@ -8,8 +8,33 @@ var NoResultsError = errors.New("Not found.")
 var UserAlreadyExistsError = errors.New("User with given username or email already exists.")
 
 var ForbiddenError = errors.New("User is not allowed to modify this resource.")
+var EditWindowExpiredError = errors.New("Tweet can no longer be edited.")
+var RestoreWindowExpiredError = errors.New("Tweet can no longer be restored.")
+var PublishTimeInPastError = errors.New("Scheduled tweet has to be published in the future.")
+var EmptyContentError = errors.New("Tweet content can not be empty.")
+var ContentTooLongError = errors.New("Tweet content is too long.")
+var BannedContentError = errors.New("Tweet content contains banned terms.")
+var DuplicateContentError = errors.New("The same tweet has been posted recently.")
+var InvalidVisibilityError = errors.New("Tweet visibility has to be either public or followers.")
+var InvalidGranularityError = errors.New("Analytics granularity has to be either hour or day.")
 var InvalidCredentialsError = errors.New("Invalid email or password.")
 
 var NotExistingUserAuthenticatingError = errors.New("User authenticating with auth token of a user that does not exist.")
 
 var NoUserAgentHeaderError = errors.New("User-Agent header is required in request for API authorization.")
+
+var TooManyMediaError = errors.New("Tweet can have at most 4 media attachments.")
+var InvalidMediaError = errors.New("Media does not exist, belongs to someone else or is already attached.")
+var MediaTooLargeError = errors.New("Uploaded media is too large.")
+var UnsupportedMediaTypeError = errors.New("Only JPEG, PNG and GIF images are supported.")
+
+var InvalidPollError = errors.New("Poll has to have 2 to 4 options of at most 25 characters and close in the future.")
+var InvalidPollOptionError = errors.New("Poll does not have such option.")
+var PollClosedError = errors.New("Poll is already closed.")
+var AlreadyVotedError = errors.New("User has already voted in this poll.")
+var ScheduledPollError = errors.New("Scheduled tweets can not have polls.")
+
+var InvalidListNameError = errors.New("List name has to have 1 to 25 characters.")
+var InvalidMutedWordError = errors.New("Muted word has to have 1 to 50 characters.")
+
+var ShuttingDownError = errors.New("Server is shutting down.")
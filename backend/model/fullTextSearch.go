@@ -3,4 +3,14 @@ package model
 type FullTextSearchResponse struct {
 	Users  []*PublicUser `json:"users"`
 	Tweets []*Tweet      `json:"tweets"`
+	// NextCursor is empty when there are no more users nor tweets.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// SearchCursor points at the last user and the last tweet of the search
+// results page. Users and tweets are paged independently - nil cursor of
+// either of them means there are no more results of this kind.
+type SearchCursor struct {
+	Users  *Cursor
+	Tweets *Cursor
 }
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// List is a named group of users curated by its owner. Timeline of the list
+// consists of tweets and retweets of its members.
+type List struct {
+	ID    int64       `json:"id"`
+	Owner *PublicUser `json:"owner"`
+	Name  string      `json:"name"`
+	// Private lists can be seen only by their owners.
+	Private         bool      `json:"private"`
+	MemberCount     int64     `json:"member_count"`
+	SubscriberCount int64     `json:"subscriber_count"`
+	Subscribed      bool      `json:"subscribed"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type NewList struct {
+	OwnerID int64  `json:"-"`
+	Name    string `json:"name" binding:"required"`
+	Private bool   `json:"private"`
+}
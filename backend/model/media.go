@@ -0,0 +1,24 @@
+package model
+
+// Media is an image uploaded by the user which can be attached to the tweet.
+type Media struct {
+	ID       int64  `json:"id"`
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+
+	OwnerID int64  `json:"-"`
+	TweetID int64  `json:"-"`
+	Key     string `json:"-"`
+	Size    int64  `json:"-"`
+}
+
+// NewMedia is uploaded media which is not saved yet.
+type NewMedia struct {
+	OwnerID  int64
+	MimeType string
+	Width    int
+	Height   int
+	Data     []byte
+}
@@ -0,0 +1,6 @@
+package model
+
+// NewMutedWord is a word or a phrase which should be muted.
+type NewMutedWord struct {
+	Word string `json:"word" binding:"required"`
+}
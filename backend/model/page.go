@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Cursor points at the last item of the page. Items are ordered by time and
+// ID (newest first) so the next page starts right after the cursor.
+type Cursor struct {
+	Time time.Time
+	ID   int64
+}
+
+// TweetsPage is a single page of tweets with cursor pointing to the next page.
+type TweetsPage struct {
+	Tweets []*Tweet `json:"tweets"`
+	// NextCursor is empty when there are no more tweets.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewTweetsCount tells how many tweets are newer than the newest tweet read
+// by the client.
+type NewTweetsCount struct {
+	Count int `json:"count"`
+}
+
+// UsersPage is a single page of users with cursor pointing to the next page.
+type UsersPage struct {
+	Users []*PublicUser `json:"users"`
+	// NextCursor is empty when there are no more users.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
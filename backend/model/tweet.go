@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/VirrageS/chirp/backend/entities"
+)
 
 type Tweet struct {
 	ID           int64       `json:"id"`
@@ -8,9 +12,44 @@ type Tweet struct {
 	LikeCount    int64       `json:"like_count"`
 	RetweetCount int64       `json:"retweet_count"`
 	CreatedAt    time.Time   `json:"created_at"`
+	EditedAt     *time.Time  `json:"edited_at,omitempty"`
 	Content      string      `json:"content"`
 	Liked        bool        `json:"liked"`
 	Retweeted    bool        `json:"retweeted"`
+	Bookmarked   bool        `json:"bookmarked"`
+	RetweetedBy  *PublicUser `json:"retweeted_by,omitempty"`
+	InReplyToID  int64       `json:"in_reply_to_id,omitempty"`
+	RootID       int64       `json:"root_id,omitempty"`
+	ReplyCount   int64       `json:"reply_count"`
+	Mentions     []*Mention  `json:"mentions"`
+
+	Entities *entities.Entities `json:"entities"`
+	Media    []*Media           `json:"media"`
+
+	QuotedTweetID int64  `json:"quoted_tweet_id,omitempty"`
+	QuotedTweet   *Tweet `json:"quoted_tweet,omitempty"`
+
+	Poll *Poll `json:"poll,omitempty"`
+
+	Visibility string `json:"visibility"`
+
+	// Unavailable is set on placeholders of tweets which no longer exist or
+	// which can not be seen by the requesting user.
+	Unavailable bool `json:"unavailable,omitempty"`
+}
+
+const (
+	// VisibilityPublic tweets can be seen by everyone.
+	VisibilityPublic = "public"
+	// VisibilityFollowers tweets can be seen only by the author and users
+	// who follow the author.
+	VisibilityFollowers = "followers"
+)
+
+// Mention is a user mentioned in the content of the tweet.
+type Mention struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
 }
 
 type NewTweetContent struct {
@@ -18,6 +57,90 @@ type NewTweetContent struct {
 }
 
 type NewTweet struct {
-	AuthorID int64  `json:"-"`
-	Content  string `json:"content" binding:"required"`
+	AuthorID      int64    `json:"-"`
+	Content       string   `json:"content" binding:"required"`
+	InReplyToID   int64    `json:"in_reply_to_id"`
+	QuotedTweetID int64    `json:"quoted_tweet_id"`
+	MediaIDs      []int64  `json:"media_ids"`
+	Poll          *NewPoll `json:"poll"`
+	// Visibility is one of `Visibility*` constants, empty means public.
+	Visibility string `json:"visibility"`
+}
+
+// Poll is a poll attached to the tweet.
+type Poll struct {
+	Options  []*PollOption `json:"options"`
+	ClosesAt time.Time     `json:"closes_at"`
+	Closed   bool          `json:"closed"`
+	// Vote is index of the option voted by the requesting user or nil if
+	// the user has not voted yet.
+	Vote *int `json:"vote"`
+}
+
+type PollOption struct {
+	Text      string `json:"text"`
+	VoteCount int64  `json:"vote_count"`
+}
+
+type NewPoll struct {
+	Options  []string  `json:"options"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type PollVote struct {
+	// Option is index of the voted option.
+	Option *int `json:"option" binding:"required"`
+}
+
+// NewScheduledTweet is a tweet which should be published at given time.
+type NewScheduledTweet struct {
+	NewTweet
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
+// ScheduledTweet is a tweet which waits to be published.
+type ScheduledTweet struct {
+	ID            int64     `json:"id"`
+	AuthorID      int64     `json:"-"`
+	Content       string    `json:"content"`
+	InReplyToID   int64     `json:"in_reply_to_id,omitempty"`
+	QuotedTweetID int64     `json:"quoted_tweet_id,omitempty"`
+	MediaIDs      []int64   `json:"media_ids"`
+	Visibility    string    `json:"visibility"`
+	PublishAt     time.Time `json:"publish_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	// Failure is the reason why the tweet could not be published. Such
+	// tweets are kept until they are canceled.
+	Failure string `json:"failure,omitempty"`
+}
+
+// TweetRevision is one of the previous versions of the edited tweet.
+type TweetRevision struct {
+	Content string `json:"content"`
+	// CreatedAt is the time when this version was published.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Retweet tells that the tweet has been retweeted by the user.
+type Retweet struct {
+	TweetID     int64
+	UserID      int64
+	RetweetedAt time.Time
+}
+
+// Conversation represents tweet together with tweets it replies to
+// (from the root of the thread) and the tree of replies to it.
+type Conversation struct {
+	Ancestors []*Tweet `json:"ancestors"`
+	Tweet     *Tweet   `json:"tweet"`
+	Replies   []*Reply `json:"replies"`
+	// NextCursor points to the next page of replies. It is empty when there
+	// are no more replies.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Reply is a single node in the tree of replies.
+type Reply struct {
+	*Tweet
+	Replies []*Reply `json:"replies"`
 }
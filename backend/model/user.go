@@ -31,6 +31,9 @@ type PublicUser struct {
 	FollowerCount int64  `json:"follower_count"`
 	FolloweeCount int64  `json:"followee_count"`
 	Following     bool   `json:"following"`
+	// PinnedTweetID is 0 when user has not pinned any tweet.
+	PinnedTweetID int64  `json:"-"`
+	PinnedTweet   *Tweet `json:"pinned_tweet,omitempty"`
 }
 
 type UserGoogle struct {
@@ -0,0 +1,74 @@
+package policy
+
+import "unicode"
+
+const (
+	zeroWidthJoiner = '\u200D'
+
+	emojiModifierFirst = '\U0001F3FB'
+	emojiModifierLast  = '\U0001F3FF'
+
+	regionalIndicatorFirst = '\U0001F1E6'
+	regionalIndicatorLast  = '\U0001F1FF'
+
+	tagFirst = '\U000E0020'
+	tagLast  = '\U000E007F'
+)
+
+// graphemeLength returns number of characters in the string as perceived by
+// the user. It is a simplified version of grapheme clusters segmentation
+// (Unicode Standard Annex #29) which handles combining marks, variation
+// selectors, emoji modifiers, emoji joined with zero width joiner, flags
+// and CRLF. It is good enough to count emoji as single characters which is
+// what users expect.
+func graphemeLength(s string) int {
+	var (
+		length      int
+		prev        = rune(-1)
+		joinNext    bool
+		regionalRun int
+	)
+
+	for _, r := range s {
+		extends := false
+
+		switch {
+		case prev == -1:
+		case joinNext:
+			extends = true
+		case r == zeroWidthJoiner:
+			extends = true
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+			// combining marks, including variation selectors
+			extends = true
+		case r >= emojiModifierFirst && r <= emojiModifierLast:
+			extends = true
+		case r >= tagFirst && r <= tagLast:
+			extends = true
+		case prev == '\r' && r == '\n':
+			extends = true
+		case isRegionalIndicator(r) && isRegionalIndicator(prev) && regionalRun%2 == 1:
+			// flags are pairs of regional indicators
+			extends = true
+		}
+
+		if !extends {
+			length++
+		}
+
+		if isRegionalIndicator(r) {
+			regionalRun++
+		} else {
+			regionalRun = 0
+		}
+
+		joinNext = r == zeroWidthJoiner
+		prev = r
+	}
+
+	return length
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicatorFirst && r <= regionalIndicatorLast
+}
@@ -0,0 +1,183 @@
+package policy
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+// Post is a content submitted by the user which has to be checked before
+// it is stored.
+type Post struct {
+	AuthorID int64
+	Content  string
+}
+
+// Rule checks single aspect of the post. Rule can normalize the content of
+// the post (eg. trim it) so rules which come after it see normalized content.
+type Rule interface {
+	Check(post *Post) error
+}
+
+// RuleFunc is an adapter which allows to use ordinary function as a Rule.
+type RuleFunc func(post *Post) error
+
+// Check calls f(post).
+func (f RuleFunc) Check(post *Post) error {
+	return f(post)
+}
+
+// Pipeline runs rules one after another and stops on the first error.
+type Pipeline struct {
+	rules []Rule
+}
+
+// NewPipeline creates pipeline which checks posts with given rules in order.
+func NewPipeline(rules ...Rule) *Pipeline {
+	return &Pipeline{
+		rules: rules,
+	}
+}
+
+// Check checks post with all rules of the pipeline. The post can be modified
+// by rules so its content should be used only if no error is returned.
+func (p *Pipeline) Check(post *Post) error {
+	for _, rule := range p.rules {
+		if err := rule.Check(post); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DuplicatesFinder checks if the author has recently posted the same content.
+type DuplicatesFinder interface {
+	HasRecentDuplicate(authorID int64, content string, window time.Duration) (bool, error)
+}
+
+// New creates pipeline which should be used to check new tweets. Cheap
+// rules go first so the database is queried only for otherwise valid tweets.
+func New(tweetsConfig config.TweetsConfigProvider, finder DuplicatesFinder) *Pipeline {
+	return NewPipeline(
+		TrimWhitespace(),
+		MaxLength(tweetsConfig.GetMaxLength()),
+		BannedTerms(tweetsConfig.GetBannedTerms()),
+		NoDuplicates(finder, tweetsConfig.GetDuplicateWindow()),
+	)
+}
+
+// NewForEdits creates pipeline which should be used to check new content of
+// edited tweets. Edits are not checked for duplicates since the tweet would
+// be a duplicate of itself.
+func NewForEdits(tweetsConfig config.TweetsConfigProvider) *Pipeline {
+	return NewPipeline(
+		TrimWhitespace(),
+		MaxLength(tweetsConfig.GetMaxLength()),
+		BannedTerms(tweetsConfig.GetBannedTerms()),
+	)
+}
+
+// TrimWhitespace removes leading and trailing whitespace from the content and
+// rejects posts which are empty after that.
+func TrimWhitespace() Rule {
+	return RuleFunc(func(post *Post) error {
+		post.Content = strings.TrimSpace(post.Content)
+		if post.Content == "" {
+			return errors.EmptyContentError
+		}
+
+		return nil
+	})
+}
+
+// MaxLength rejects posts which have more than `maxLength` characters as
+// perceived by the user (see `graphemeLength`).
+func MaxLength(maxLength int) Rule {
+	return RuleFunc(func(post *Post) error {
+		if graphemeLength(post.Content) > maxLength {
+			return errors.ContentTooLongError
+		}
+
+		return nil
+	})
+}
+
+// BannedTerms rejects posts which contain any of the terms. Terms are matched
+// case-insensitively and only as whole words so eg. "ass" does not match
+// "class".
+func BannedTerms(terms []string) Rule {
+	lowerTerms := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term != "" {
+			lowerTerms = append(lowerTerms, term)
+		}
+	}
+
+	return RuleFunc(func(post *Post) error {
+		content := strings.ToLower(post.Content)
+		for _, term := range lowerTerms {
+			if ContainsWord(content, term) {
+				return errors.BannedContentError
+			}
+		}
+
+		return nil
+	})
+}
+
+// NoDuplicates rejects posts with exactly the same content as one of the
+// posts of the same author from last `window`. Non-positive window disables
+// the rule.
+func NoDuplicates(finder DuplicatesFinder, window time.Duration) Rule {
+	return RuleFunc(func(post *Post) error {
+		if window <= 0 {
+			return nil
+		}
+
+		duplicate, err := finder.HasRecentDuplicate(post.AuthorID, post.Content, window)
+		if err != nil {
+			return err
+		}
+
+		if duplicate {
+			return errors.DuplicateContentError
+		}
+
+		return nil
+	})
+}
+
+// ContainsWord checks if `word` occurs in `s` not surrounded by letters or
+// digits. Both `s` and `word` are expected to be lowercased by the caller.
+func ContainsWord(s, word string) bool {
+	for offset := 0; offset < len(s); {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return false
+		}
+
+		start := offset + i
+		end := start + len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(s[start:])
+		offset = start + size
+	}
+
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,155 @@
+package policy
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+// fakeFinder treats posts from `posted` as recent posts of every author.
+type fakeFinder struct {
+	posted []string
+	err    error
+	calls  int
+}
+
+func (f *fakeFinder) HasRecentDuplicate(authorID int64, content string, window time.Duration) (bool, error) {
+	f.calls++
+	if f.err != nil {
+		return false, f.err
+	}
+
+	for _, posted := range f.posted {
+		if posted == content {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+var _ = Describe("Policy", func() {
+	Describe("Pipeline", func() {
+		It("should run rules in order and stop on the first error", func() {
+			calls := make([]string, 0)
+			pipeline := NewPipeline(
+				RuleFunc(func(post *Post) error {
+					calls = append(calls, "first")
+					return nil
+				}),
+				RuleFunc(func(post *Post) error {
+					calls = append(calls, "second")
+					return errors.BannedContentError
+				}),
+				RuleFunc(func(post *Post) error {
+					calls = append(calls, "third")
+					return nil
+				}),
+			)
+
+			err := pipeline.Check(&Post{Content: "content"})
+			Expect(err).To(Equal(errors.BannedContentError))
+			Expect(calls).To(Equal([]string{"first", "second"}))
+		})
+
+		It("should pass content normalized by previous rules", func() {
+			finder := &fakeFinder{posted: []string{"content"}}
+			pipeline := NewPipeline(TrimWhitespace(), NoDuplicates(finder, time.Hour))
+
+			err := pipeline.Check(&Post{Content: "  content\n"})
+			Expect(err).To(Equal(errors.DuplicateContentError))
+		})
+	})
+
+	Describe("TrimWhitespace", func() {
+		It("should trim content", func() {
+			post := &Post{Content: " \t content with  spaces \n"}
+			Expect(TrimWhitespace().Check(post)).To(Succeed())
+			Expect(post.Content).To(Equal("content with  spaces"))
+		})
+
+		It("should reject empty content", func() {
+			for _, content := range []string{"", "   ", "\n\t"} {
+				Expect(TrimWhitespace().Check(&Post{Content: content})).To(Equal(errors.EmptyContentError))
+			}
+		})
+	})
+
+	Describe("MaxLength", func() {
+		It("should accept content up to the limit", func() {
+			Expect(MaxLength(5).Check(&Post{Content: "abcde"})).To(Succeed())
+			Expect(MaxLength(5).Check(&Post{Content: "abcdef"})).To(Equal(errors.ContentTooLongError))
+		})
+
+		It("should count emoji as single characters", func() {
+			family := "\U0001F469\u200D\U0001F469\u200D\U0001F467"
+			content := strings.Repeat(family, 3) + "\U0001F1F5\U0001F1F1\U0001F44D\U0001F3FD"
+			Expect(MaxLength(5).Check(&Post{Content: content})).To(Succeed())
+			Expect(MaxLength(4).Check(&Post{Content: content})).To(Equal(errors.ContentTooLongError))
+		})
+	})
+
+	Describe("BannedTerms", func() {
+		rule := BannedTerms([]string{"spam", " Buy Now ", ""})
+
+		It("should reject content with banned terms ignoring case", func() {
+			Expect(rule.Check(&Post{Content: "this is SPAM!"})).To(Equal(errors.BannedContentError))
+			Expect(rule.Check(&Post{Content: "please buy now"})).To(Equal(errors.BannedContentError))
+		})
+
+		It("should match only whole words", func() {
+			Expect(rule.Check(&Post{Content: "spammer and spamspam"})).To(Succeed())
+			Expect(rule.Check(&Post{Content: "spammer and spam"})).To(Equal(errors.BannedContentError))
+		})
+	})
+
+	Describe("NoDuplicates", func() {
+		It("should reject content which was recently posted", func() {
+			finder := &fakeFinder{posted: []string{"hello"}}
+			rule := NoDuplicates(finder, time.Hour)
+
+			Expect(rule.Check(&Post{Content: "hello"})).To(Equal(errors.DuplicateContentError))
+			Expect(rule.Check(&Post{Content: "hello again"})).To(Succeed())
+		})
+
+		It("should return error of the finder", func() {
+			finder := &fakeFinder{err: errors.UnexpectedError}
+			Expect(NoDuplicates(finder, time.Hour).Check(&Post{Content: "hello"})).To(Equal(errors.UnexpectedError))
+		})
+
+		It("should not query finder when disabled", func() {
+			finder := &fakeFinder{posted: []string{"hello"}}
+			Expect(NoDuplicates(finder, 0).Check(&Post{Content: "hello"})).To(Succeed())
+			Expect(finder.calls).To(Equal(0))
+		})
+	})
+
+	Describe("graphemeLength", func() {
+		It("should count characters as perceived by the user", func() {
+			testCases := []struct {
+				content string
+				length  int
+			}{
+				{"", 0},
+				{"chirp", 5},
+				{"zażółć", 6},
+				{"e\u0301", 1},              // e with combining acute accent
+				{"\u2764\uFE0F", 1},         // heart with variation selector
+				{"\U0001F44D\U0001F3FD", 1}, // emoji with skin tone modifier
+				{"\U0001F469\u200D\U0001F469\u200D\U0001F467", 1}, // family joined with ZWJ
+				{"\U0001F1F5\U0001F1F1\U0001F1E9\U0001F1EA", 2},   // two flags
+				{"\U0001F1F5\U0001F1F1\U0001F1E9", 2},             // flag and lone regional indicator
+				{"\U0001F3F4\U000E0067\U000E0062\U000E007F", 1},   // flag with tag sequence
+				{"a\r\nb", 3},
+			}
+
+			for _, testCase := range testCases {
+				Expect(graphemeLength(testCase.content)).To(Equal(testCase.length), testCase.content)
+			}
+		})
+	})
+})
@@ -0,0 +1,49 @@
+package purger
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/async"
+	"github.com/VirrageS/chirp/backend/config"
+)
+
+// Remover removes for good at most `limit` deleted tweets which can no longer
+// be restored and returns how many of them were removed.
+type Remover interface {
+	PurgeDeletedTweets(limit int) (int, error)
+}
+
+// Purger periodically removes deleted tweets in the background. Remover has
+// to handle the same tweet being purged concurrently, so it is safe
+// to run purger on each replica of the server.
+type Purger struct {
+	*async.Periodic
+
+	remover   Remover
+	batchSize int
+}
+
+// New creates new instance of `Purger` which uses given remover.
+func New(remover Remover, config config.PurgerConfigProvider) *Purger {
+	p := &Purger{
+		remover:   remover,
+		batchSize: config.GetBatchSize(),
+	}
+	p.Periodic = async.NewPeriodic(config.GetInterval(), p.purge)
+	return p
+}
+
+// purge removes batches of tweets until there are no more tweets to remove.
+func (p *Purger) purge() {
+	for {
+		purged, err := p.remover.PurgeDeletedTweets(p.batchSize)
+		if err != nil {
+			log.WithError(err).Error("Failed to purge deleted tweets.")
+			return
+		}
+
+		if purged < p.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package purger
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPurger(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Purger")
+}
@@ -0,0 +1,90 @@
+package purger
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeConfig struct{}
+
+func (*fakeConfig) GetInterval() time.Duration {
+	return 10 * time.Millisecond
+}
+
+func (*fakeConfig) GetBatchSize() int {
+	return 2
+}
+
+// fakeRemover removes `deleted` tweets in batches.
+type fakeRemover struct {
+	sync.Mutex
+	deleted int
+	purged  int
+	calls   int
+	err     error
+}
+
+func (r *fakeRemover) PurgeDeletedTweets(limit int) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.calls++
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	purged := r.deleted
+	if purged > limit {
+		purged = limit
+	}
+
+	r.deleted -= purged
+	r.purged += purged
+	return purged, nil
+}
+
+func (r *fakeRemover) getPurged() int {
+	r.Lock()
+	defer r.Unlock()
+	return r.purged
+}
+
+func (r *fakeRemover) getCalls() int {
+	r.Lock()
+	defer r.Unlock()
+	return r.calls
+}
+
+var _ = Describe("Purger", func() {
+	var (
+		remover *fakeRemover
+		purger  *Purger
+	)
+
+	BeforeEach(func() {
+		remover = &fakeRemover{}
+		purger = New(remover, &fakeConfig{})
+	})
+
+	It("should purge all deleted tweets in batches", func() {
+		remover.deleted = 5
+
+		purger.Start()
+		defer purger.Stop()
+
+		Eventually(remover.getPurged).Should(Equal(5))
+	})
+
+	It("should keep purging after remover failed", func() {
+		remover.err = errors.New("error")
+
+		purger.Start()
+		defer purger.Stop()
+
+		Eventually(remover.getCalls).Should(BeNumerically(">=", 2))
+	})
+})
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/async"
+	"github.com/VirrageS/chirp/backend/config"
+)
+
+// Publisher publishes at most `limit` scheduled tweets which are due and
+// returns how many of them were published.
+type Publisher interface {
+	PublishScheduledTweets(limit int) (int, error)
+}
+
+// Scheduler periodically publishes scheduled tweets in the background.
+// Publisher has to make sure that no tweet is published twice, so it is safe
+// to run scheduler on each replica of the server.
+type Scheduler struct {
+	*async.Periodic
+
+	publisher Publisher
+	batchSize int
+}
+
+// New creates new instance of `Scheduler` which uses given publisher.
+func New(publisher Publisher, config config.SchedulerConfigProvider) *Scheduler {
+	s := &Scheduler{
+		publisher: publisher,
+		batchSize: config.GetBatchSize(),
+	}
+	s.Periodic = async.NewPeriodic(config.GetInterval(), s.publish)
+	return s
+}
+
+// publish publishes batches of tweets until there are no more due tweets.
+func (s *Scheduler) publish() {
+	for {
+		published, err := s.publisher.PublishScheduledTweets(s.batchSize)
+		if err != nil {
+			log.WithError(err).Error("Failed to publish scheduled tweets.")
+			return
+		}
+
+		if published < s.batchSize {
+			return
+		}
+	}
+}
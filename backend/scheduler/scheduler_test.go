@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type fakeConfig struct{}
+
+func (*fakeConfig) GetInterval() time.Duration {
+	return 10 * time.Millisecond
+}
+
+func (*fakeConfig) GetBatchSize() int {
+	return 2
+}
+
+// fakePublisher publishes `due` tweets in batches.
+type fakePublisher struct {
+	sync.Mutex
+	due       int
+	published int
+	calls     int
+	err       error
+}
+
+func (p *fakePublisher) PublishScheduledTweets(limit int) (int, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.calls++
+	if p.err != nil {
+		return 0, p.err
+	}
+
+	published := p.due
+	if published > limit {
+		published = limit
+	}
+
+	p.due -= published
+	p.published += published
+	return published, nil
+}
+
+func (p *fakePublisher) getPublished() int {
+	p.Lock()
+	defer p.Unlock()
+	return p.published
+}
+
+func (p *fakePublisher) getCalls() int {
+	p.Lock()
+	defer p.Unlock()
+	return p.calls
+}
+
+var _ = Describe("Scheduler", func() {
+	var (
+		publisher *fakePublisher
+		scheduler *Scheduler
+	)
+
+	BeforeEach(func() {
+		publisher = &fakePublisher{}
+		scheduler = New(publisher, &fakeConfig{})
+	})
+
+	It("should publish all due tweets in batches", func() {
+		publisher.due = 5
+
+		scheduler.Start()
+		defer scheduler.Stop()
+
+		Eventually(publisher.getPublished).Should(Equal(5))
+	})
+
+	It("should keep publishing after publisher failed", func() {
+		publisher.err = errors.New("error")
+
+		scheduler.Start()
+		defer scheduler.Stop()
+
+		Eventually(publisher.getCalls).Should(BeNumerically(">=", 2))
+	})
+})
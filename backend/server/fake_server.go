@@ -5,9 +5,11 @@ import (
 
 	"github.com/VirrageS/chirp/backend/api"
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/gateway"
 	"github.com/VirrageS/chirp/backend/password"
 	"github.com/VirrageS/chirp/backend/service"
 	"github.com/VirrageS/chirp/backend/storage"
+	"github.com/VirrageS/chirp/backend/stream"
 	"github.com/VirrageS/chirp/backend/token"
 )
 
@@ -25,12 +27,15 @@ func NewFakeServer() *FakeServer {
 		panic("Failed to get config.")
 	}
 
-	fakeStorage := storage.NewFakeStorage(conf.Postgres)
+	fakeStorage := storage.NewFakeStorage(conf.Postgres, conf.Timelines)
 	passwordManager := password.NewBcryptManager(conf.Password)
-	services := service.New(fakeStorage.Storage, passwordManager)
+	hub := stream.NewHub(stream.NewLocalBroker(), conf.Stream.GetBufferSize())
+	services := service.New(fakeStorage.Storage, passwordManager, conf.Media, conf.Tweets, hub)
+
+	gateway := gateway.New(services, conf.Gateway)
 
 	tokenManager := token.NewManager(conf.Token)
-	apis := api.New(services, tokenManager, conf.AuthorizationGoogle)
+	apis := api.New(services, tokenManager, conf.AuthorizationGoogle, conf.Media, conf.Stream, gateway)
 
 	return &FakeServer{
 		Server:       setupRouter(apis, tokenManager),
@@ -1,33 +1,130 @@
 package server
 
 import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
 	"github.com/VirrageS/chirp/backend/api"
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/flusher"
+	"github.com/VirrageS/chirp/backend/gateway"
 	"github.com/VirrageS/chirp/backend/middleware"
 	"github.com/VirrageS/chirp/backend/password"
+	"github.com/VirrageS/chirp/backend/purger"
+	"github.com/VirrageS/chirp/backend/scheduler"
 	"github.com/VirrageS/chirp/backend/service"
 	"github.com/VirrageS/chirp/backend/storage"
+	"github.com/VirrageS/chirp/backend/stream"
 	"github.com/VirrageS/chirp/backend/token"
 )
 
+// Server serves the API and runs background workers until the process is
+// asked to terminate.
+type Server struct {
+	router          *gin.Engine
+	storage         storage.Accessor
+	hub             *stream.Hub
+	gateway         *gateway.Gateway
+	scheduler       *scheduler.Scheduler
+	purger          *purger.Purger
+	flusher         *flusher.Flusher
+	shutdownTimeout time.Duration
+}
+
 // New creates a new server.
-func New() *gin.Engine {
+func New() *Server {
 	conf := config.New()
 	if conf == nil {
 		panic("Failed to get config.")
 	}
 
-	storage := storage.New(conf.Postgres, conf.Redis, conf.Elasticsearch)
+	storage := storage.New(conf.Postgres, conf.Redis, conf.Elasticsearch, conf.Media, conf.Timelines)
 	passwordManager := password.NewBcryptManager(conf.Password)
-	services := service.New(storage, passwordManager)
+	// events are sent through Redis so all servers deliver them to their clients
+	broker := stream.NewRedisBroker(conf.Redis)
+	if broker == nil {
+		panic("failed to connect to Redis instance")
+	}
+	hub := stream.NewHub(broker, conf.Stream.GetBufferSize())
+	services := service.New(storage, passwordManager, conf.Media, conf.Tweets, hub)
+
+	gateway := gateway.New(services, conf.Gateway)
 
 	tokenManager := token.NewManager(conf.Token)
-	apis := api.New(services, tokenManager, conf.AuthorizationGoogle)
+	apis := api.New(services, tokenManager, conf.AuthorizationGoogle, conf.Media, conf.Stream, gateway)
+
+	router := setupRouter(apis, tokenManager)
+
+	return &Server{
+		router:  router,
+		storage: storage,
+		hub:     hub,
+		gateway: gateway,
+		// publishes scheduled tweets for the whole lifetime of the server
+		scheduler: scheduler.New(services, conf.Scheduler),
+		// removes for good deleted tweets which can no longer be restored
+		purger: purger.New(services, conf.Purger),
+		// saves impressions and profile clicks counted in Redis to the database
+		flusher: flusher.New(services, conf.Analytics),
+		// requests in flight get as much time to finish as gateway clients
+		shutdownTimeout: conf.Gateway.GetShutdownTimeout(),
+	}
+}
+
+// Run starts background workers and serves requests on `addr` until the
+// process receives SIGINT or SIGTERM. Then it waits for requests in flight
+// and stops the workers before returning.
+func (s *Server) Run(addr string) error {
+	httpServer := &http.Server{Addr: addr, Handler: s.router}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	s.scheduler.Start()
+	s.purger.Start()
+	s.flusher.Start()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		s.stopWorkers()
+		return err
+	case <-signals:
+	}
+
+	// clients are told to reconnect to other server before this one stops
+	s.gateway.Shutdown()
+	// event streams never finish on their own so they are ended here
+	s.hub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	err := httpServer.Shutdown(ctx)
+
+	s.stopWorkers()
+	return err
+}
 
-	return setupRouter(apis, tokenManager)
+// stopWorkers waits until background workers finish their current batches
+// and timelines are updated with published tweets. Flusher is stopped last so
+// it saves stats counted during the shutdown.
+func (s *Server) stopWorkers() {
+	s.scheduler.Stop()
+	s.purger.Stop()
+	s.storage.Close()
+	s.flusher.Stop()
 }
 
 func setupRouter(api api.APIProvider, tokenManager token.Manager) *gin.Engine {
@@ -46,19 +143,78 @@ func setupRouter(api api.APIProvider, tokenManager token.Manager) *gin.Engine {
 		tweets.POST("", contentTypeChecker, api.PostTweet)
 		tweets.GET("/:id", api.GetTweet)
 		tweets.DELETE("/:id", api.DeleteTweet)
+		tweets.PATCH("/:id", contentTypeChecker, api.EditTweet)
+		tweets.POST("/:id/restore", api.RestoreTweet)
+		tweets.GET("/:id/revisions", api.GetTweetRevisions)
+		tweets.GET("/:id/conversation", api.GetConversation)
 		tweets.POST("/:id/like", api.LikeTweet)
 		tweets.POST("/:id/unlike", api.UnlikeTweet)
+		tweets.GET("/:id/likes", api.TweetLikers)
+		tweets.GET("/:id/analytics", api.TweetAnalytics)
+		tweets.POST("/:id/retweet", api.RetweetTweet)
+		tweets.POST("/:id/unretweet", api.UnretweetTweet)
+		tweets.POST("/:id/poll/vote", contentTypeChecker, api.VotePoll)
+		tweets.POST("/:id/bookmark", api.BookmarkTweet)
+		tweets.DELETE("/:id/bookmark", api.UnbookmarkTweet)
+		tweets.POST("/:id/pin", api.PinTweet)
+		tweets.DELETE("/:id/pin", api.UnpinTweet)
+
+		bookmarks := authorizedRoutes.Group("bookmarks")
+		bookmarks.GET("", api.Bookmarks)
+
+		scheduledTweets := authorizedRoutes.Group("scheduled_tweets")
+		scheduledTweets.POST("", contentTypeChecker, api.ScheduleTweet)
+		scheduledTweets.GET("", api.GetScheduledTweets)
+		scheduledTweets.DELETE("/:id", api.CancelScheduledTweet)
+
+		// uploaded media are served only to users who can see them,
+		// `media.base_url` in config should point here
+		media := authorizedRoutes.Group("media")
+		media.POST("", api.UploadMedia)
+		media.GET("/:key", api.GetMedia)
+
+		hashtags := authorizedRoutes.Group("hashtags")
+		hashtags.GET("/:tag/tweets", api.HashtagTweets)
 
 		feed := authorizedRoutes.Group("feed")
 		feed.GET("", api.Feed)
+		feed.GET("/new_count", api.FeedNewCount)
+
+		lists := authorizedRoutes.Group("lists")
+		lists.POST("", contentTypeChecker, api.CreateList)
+		lists.GET("", api.Lists)
+		lists.GET("/:id", api.GetList)
+		lists.DELETE("/:id", api.DeleteList)
+		lists.GET("/:id/members", api.ListMembers)
+		lists.POST("/:id/members/:user_id", api.AddListMember)
+		lists.DELETE("/:id/members/:user_id", api.RemoveListMember)
+		lists.GET("/:id/timeline", api.ListTimeline)
+		lists.POST("/:id/subscribe", api.SubscribeList)
+		lists.POST("/:id/unsubscribe", api.UnsubscribeList)
+
+		mutes := authorizedRoutes.Group("mutes")
+		mutes.GET("/users", api.MutedUsers)
+		mutes.GET("/words", api.MutedWords)
+		mutes.POST("/words", contentTypeChecker, api.MuteWord)
+		mutes.DELETE("/words/:word", api.UnmuteWord)
+
+		stream := authorizedRoutes.Group("stream")
+		stream.GET("/feed", api.StreamFeed)
+
+		authorizedRoutes.GET("gateway", api.Gateway)
 
 		users := authorizedRoutes.Group("users")
 		users.GET("/:id", api.GetUser)
 		users.POST(":id/follow", api.FollowUser)
 		users.POST(":id/unfollow", api.UnfollowUser)
+		users.POST(":id/mute", api.MuteUser)
+		users.POST(":id/unmute", api.UnmuteUser)
 		users.GET(":id/followers", api.UserFollowers)
 		users.GET(":id/followees", api.UserFollowees)
 		users.GET(":id/tweets", api.UserTweets)
+		users.GET(":id/mentions", api.UserMentions)
+		users.GET(":id/likes", api.UserLikes)
+		users.GET(":id/lists", api.UserLists)
 
 		search := authorizedRoutes.Group("search")
 		search.GET("", api.Search)
@@ -1,24 +1,76 @@
 package service
 
-import model "github.com/VirrageS/chirp/backend/model"
+import (
+	model "github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/stream"
+)
 
 // TODO: Maybe split into 2 services: tweet and user service?
 type ServiceProvider interface {
-	GetTweetsOfUserWithID(userID, requestingUserID int64) ([]*model.Tweet, error)
+	GetTweetsOfUserWithID(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
 	GetTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
+	TweetAnalytics(tweetID, requestingUserID int64, granularity string) (*model.TweetAnalytics, error)
+	FlushTweetStats() (int, error)
 	PostTweet(newTweet *model.NewTweet, requestingUserID int64) (*model.Tweet, error)
 	DeleteTweet(tweetID, requestingUserID int64) error
+	RestoreTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
+	PurgeDeletedTweets(limit int) (int, error)
+	VotePoll(tweetID int64, option int, requestingUserID int64) (*model.Tweet, error)
+	ScheduleTweet(newTweet *model.NewScheduledTweet, requestingUserID int64) (*model.ScheduledTweet, error)
+	GetScheduledTweets(requestingUserID int64) ([]*model.ScheduledTweet, error)
+	CancelScheduledTweet(scheduledTweetID, requestingUserID int64) error
+	PublishScheduledTweets(limit int) (int, error)
+	EditTweet(tweetID int64, content string, requestingUserID int64) (*model.Tweet, error)
+	GetTweetRevisions(tweetID, requestingUserID int64) ([]*model.TweetRevision, error)
+	GetConversation(tweetID, requestingUserID int64, cursor *model.Cursor, limit int) (*model.Conversation, *model.Cursor, error)
 	LikeTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
 	UnlikeTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
+	RetweetTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
+	UnretweetTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
+	BookmarkTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
+	UnbookmarkTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
+	Bookmarks(requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	TweetLikers(tweetID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
+	PinTweet(tweetID, requestingUserID int64) (*model.PublicUser, error)
+	UnpinTweet(tweetID, requestingUserID int64) (*model.PublicUser, error)
+	UploadMedia(data []byte, requestingUserID int64) (*model.Media, error)
+	GetMedia(key string, requestingUserID int64) (*model.Media, []byte, error)
+	HashtagTweets(hashtag string, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
 
 	GetUser(userID, requestingUserID int64) (*model.PublicUser, error)
+	RecordProfileClick(tweetID, userID, requestingUserID int64) error
 	FollowUser(userID, requestingUserID int64) (*model.PublicUser, error)
 	UnfollowUser(userID, requestingUserID int64) (*model.PublicUser, error)
-	UserFollowers(userID, requestingUserID int64) ([]*model.PublicUser, error)
-	UserFollowees(userID, requestingUserID int64) ([]*model.PublicUser, error)
-	Feed(userID int64) ([]*model.Tweet, error)
+	UserFollowers(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
+	UserFollowees(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
+	UserMentions(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	UserLikes(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	Feed(userID, sinceID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	FeedNewCount(userID, sinceID int64) (*model.NewTweetsCount, error)
+	SubscribeFeed(userID int64) (*stream.Subscription, error)
+	SubscribeInbox(userID int64) (*stream.Subscription, error)
+	SendTyping(userID, recipientID int64) error
 
-	FullTextSearch(queryString string, requestingUserID int64) (*model.FullTextSearchResponse, error)
+	CreateList(newList *model.NewList, requestingUserID int64) (*model.List, error)
+	GetList(listID, requestingUserID int64) (*model.List, error)
+	DeleteList(listID, requestingUserID int64) error
+	Lists(requestingUserID int64) ([]*model.List, error)
+	UserLists(userID, requestingUserID int64) ([]*model.List, error)
+	ListMembers(listID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
+	AddListMember(listID, userID, requestingUserID int64) (*model.List, error)
+	RemoveListMember(listID, userID, requestingUserID int64) (*model.List, error)
+	ListTimeline(listID, requestingUserID, sinceID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	SubscribeList(listID, requestingUserID int64) (*model.List, error)
+	UnsubscribeList(listID, requestingUserID int64) (*model.List, error)
+
+	MuteUser(userID, requestingUserID int64) error
+	UnmuteUser(userID, requestingUserID int64) error
+	MutedUsers(requestingUserID int64) ([]*model.PublicUser, error)
+	MuteWord(word string, requestingUserID int64) error
+	UnmuteWord(word string, requestingUserID int64) error
+	MutedWords(requestingUserID int64) ([]string, error)
+
+	FullTextSearch(queryString string, requestingUserID int64, cursor *model.SearchCursor, limit int) (*model.FullTextSearchResponse, *model.SearchCursor, error)
 
 	RegisterUser(newUserForm *model.NewUserForm) (*model.PublicUser, error)
 	LoginUser(loginForm *model.LoginForm) (*model.PublicUser, error)
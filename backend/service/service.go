@@ -1,39 +1,124 @@
 package service
 
 import (
+	"bytes"
+	"image"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"net/http"
 	"sort"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/VirrageS/chirp/backend/config"
 	"github.com/VirrageS/chirp/backend/model"
 	"github.com/VirrageS/chirp/backend/model/errors"
 	appErrors "github.com/VirrageS/chirp/backend/model/errors"
 	"github.com/VirrageS/chirp/backend/password"
+	"github.com/VirrageS/chirp/backend/policy"
 	"github.com/VirrageS/chirp/backend/storage"
-	"github.com/VirrageS/chirp/backend/utils"
+	"github.com/VirrageS/chirp/backend/stream"
 )
 
+const (
+	// Depth of the replies tree returned in conversation. Deeper replies have
+	// to be fetched with conversation of one of the replies.
+	conversationDepth = 3
+
+	// Number of replies fetched for each of the nested replies in conversation.
+	conversationNestedRepliesLimit = 3
+
+	// Maximal number of media attached to a single tweet.
+	maxTweetMedia = 4
+
+	// Limits of number of options in the poll and length of each of them.
+	minPollOptions      = 2
+	maxPollOptions      = 4
+	maxPollOptionLength = 25
+
+	// Maximal length of the name of the list.
+	maxListNameLength = 25
+
+	// Maximal length of the muted word or phrase.
+	maxMutedWordLength = 50
+)
+
+// MIME types of media which can be uploaded.
+var allowedMediaTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
 // Struct that implements APIProvider
 type Service struct {
 	storage         storage.Accessor
 	passwordManager password.Manager
+	mediaConfig     config.MediaConfigProvider
+	tweetsConfig    config.TweetsConfigProvider
+	postPolicy      *policy.Pipeline
+	editPolicy      *policy.Pipeline
+	hub             *stream.Hub
 }
 
 // Constructs a Service that uses provided objects
-func New(storage storage.Accessor, passwordManager password.Manager) ServiceProvider {
+func New(
+	storage storage.Accessor,
+	passwordManager password.Manager,
+	mediaConfig config.MediaConfigProvider,
+	tweetsConfig config.TweetsConfigProvider,
+	hub *stream.Hub,
+) ServiceProvider {
 	return &Service{
 		storage:         storage,
 		passwordManager: passwordManager,
+		mediaConfig:     mediaConfig,
+		tweetsConfig:    tweetsConfig,
+		postPolicy:      policy.New(tweetsConfig, storage),
+		editPolicy:      policy.NewForEdits(tweetsConfig),
+		hub:             hub,
 	}
 }
 
-func (service *Service) GetTweetsOfUserWithID(userID, requestingUserID int64) ([]*model.Tweet, error) {
-	tweets, err := service.storage.GetUsersTweets(userID, requestingUserID)
+// GetTweetsOfUserWithID returns single page of tweets of the user, newest
+// first. Pinned tweet is always shown at the top of the first page.
+func (service *Service) GetTweetsOfUserWithID(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	user, err := service.storage.GetUserByID(userID, requestingUserID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	tweets, nextCursor, err := service.storage.GetTweetsByAuthorIDs([]int64{userID}, requestingUserID, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user.PinnedTweetID == 0 {
+		return tweets, nextCursor, nil
 	}
 
-	return tweets, nil
+	// pinned tweet is not shown again on its own page
+	pageTweets := make([]*model.Tweet, 0, len(tweets)+1)
+	if cursor == nil {
+		pinnedTweet, err := service.storage.GetTweet(user.PinnedTweetID, requestingUserID)
+		if err == nil {
+			pageTweets = append(pageTweets, pinnedTweet)
+		} else if err != errors.NoResultsError {
+			return nil, nil, err
+		}
+	}
+
+	for _, tweet := range tweets {
+		if tweet.ID != user.PinnedTweetID {
+			pageTweets = append(pageTweets, tweet)
+		}
+	}
+
+	return pageTweets, nextCursor, nil
 }
 
 func (service *Service) GetTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
@@ -43,19 +128,244 @@ func (service *Service) GetTweet(tweetID, requestingUserID int64) (*model.Tweet,
 		return nil, err
 	}
 
+	service.recordImpressions(tweet)
 	return tweet, nil
 }
 
+// TweetAnalytics returns analytics of the tweet. Analytics are available only
+// to the author of the tweet.
+func (service *Service) TweetAnalytics(tweetID, requestingUserID int64, granularity string) (*model.TweetAnalytics, error) {
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tweet.Author.ID != requestingUserID {
+		return nil, errors.ForbiddenError
+	}
+
+	switch granularity {
+	case "":
+		granularity = model.GranularityDay
+	case model.GranularityHour, model.GranularityDay:
+	default:
+		return nil, errors.InvalidGranularityError
+	}
+
+	return service.storage.GetTweetAnalytics(tweetID, granularity)
+}
+
+// FlushTweetStats saves counted impressions and profile clicks in the database.
+func (service *Service) FlushTweetStats() (int, error) {
+	return service.storage.FlushTweetStats()
+}
+
+// recordImpressions counts impressions of tweets served to the user. Counts
+// are only buffered and failing to count them does not fail the request.
+func (service *Service) recordImpressions(tweets ...*model.Tweet) {
+	tweetsIDs := make([]int64, 0, len(tweets))
+	for _, tweet := range tweets {
+		tweetsIDs = append(tweetsIDs, tweet.ID)
+	}
+
+	service.storage.RecordImpressions(tweetsIDs)
+}
+
 func (service *Service) PostTweet(tweet *model.NewTweet, requestingUserID int64) (*model.Tweet, error) {
-	// TODO: reject if content is empty or when user submitted the same tweet more than once
-	newTweet, err := service.storage.InsertTweet(tweet, requestingUserID)
+	err := service.validateNewTweet(tweet, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	newTweet, err := service.storage.InsertTweet(tweet)
 	if err != nil {
 		return nil, err
 	}
 
+	service.publishTweets(newTweet)
 	return newTweet, nil
 }
 
+// validateNewTweet checks if tweets referenced by the new tweet exist, if the
+// media can be attached to it and if its content follows the content policy.
+// Content of the tweet is normalized by the policy and missing visibility is
+// set to public.
+func (service *Service) validateNewTweet(tweet *model.NewTweet, requestingUserID int64) error {
+	switch tweet.Visibility {
+	case "":
+		tweet.Visibility = model.VisibilityPublic
+	case model.VisibilityPublic, model.VisibilityFollowers:
+	default:
+		return errors.InvalidVisibilityError
+	}
+
+	if err := service.validateReferencedTweets(tweet, requestingUserID); err != nil {
+		return err
+	}
+
+	if len(tweet.MediaIDs) > maxTweetMedia {
+		return errors.TooManyMediaError
+	}
+
+	if len(tweet.MediaIDs) > 0 {
+		media, err := service.storage.GetMediaByIDs(tweet.MediaIDs)
+		if err != nil {
+			return err
+		}
+
+		// duplicated or not existing IDs result in less media than IDs
+		if len(media) != len(tweet.MediaIDs) {
+			return errors.InvalidMediaError
+		}
+
+		for _, m := range media {
+			if m.OwnerID != requestingUserID || m.TweetID != 0 {
+				return errors.InvalidMediaError
+			}
+		}
+	}
+
+	if tweet.Poll != nil {
+		err := validateNewPoll(tweet.Poll)
+		if err != nil {
+			return err
+		}
+	}
+
+	// policy goes last since checking for duplicates queries the database
+	return service.checkContentPolicy(tweet, requestingUserID)
+}
+
+// validateScheduledTweet checks the scheduled tweet again right before it is
+// published since referenced tweets could be deleted and the content policy
+// could change in the meantime. Media is not checked because media used by
+// other tweet in the meantime is skipped when attaching.
+func (service *Service) validateScheduledTweet(tweet *model.NewTweet) error {
+	if err := service.validateReferencedTweets(tweet, tweet.AuthorID); err != nil {
+		return err
+	}
+
+	return service.checkContentPolicy(tweet, tweet.AuthorID)
+}
+
+// validateReferencedTweets checks if the replied and quoted tweets exist and
+// can be seen by the user.
+func (service *Service) validateReferencedTweets(tweet *model.NewTweet, requestingUserID int64) error {
+	if tweet.InReplyToID != 0 {
+		_, err := service.storage.GetTweet(tweet.InReplyToID, requestingUserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if tweet.QuotedTweetID != 0 {
+		_, err := service.storage.GetTweet(tweet.QuotedTweetID, requestingUserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkContentPolicy checks content of the tweet and replaces it with the
+// normalized one.
+func (service *Service) checkContentPolicy(tweet *model.NewTweet, requestingUserID int64) error {
+	post := &policy.Post{AuthorID: requestingUserID, Content: tweet.Content}
+	err := service.postPolicy.Check(post)
+	if err != nil {
+		return err
+	}
+
+	tweet.Content = post.Content
+	return nil
+}
+
+func validateNewPoll(poll *model.NewPoll) error {
+	if len(poll.Options) < minPollOptions || len(poll.Options) > maxPollOptions {
+		return errors.InvalidPollError
+	}
+
+	for i, option := range poll.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || utf8.RuneCountInString(option) > maxPollOptionLength {
+			return errors.InvalidPollError
+		}
+
+		poll.Options[i] = option
+	}
+
+	if !poll.ClosesAt.After(time.Now()) {
+		return errors.InvalidPollError
+	}
+
+	return nil
+}
+
+func (service *Service) ScheduleTweet(tweet *model.NewScheduledTweet, requestingUserID int64) (*model.ScheduledTweet, error) {
+	if !tweet.PublishAt.After(time.Now()) {
+		return nil, errors.PublishTimeInPastError
+	}
+
+	if tweet.Poll != nil {
+		return nil, errors.ScheduledPollError
+	}
+
+	// Referenced tweets and media are checked only now. Media is attached
+	// when the tweet is published so it is skipped if it was used by other
+	// tweet in the meantime.
+	err := service.validateNewTweet(&tweet.NewTweet, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.storage.InsertScheduledTweet(tweet)
+}
+
+func (service *Service) GetScheduledTweets(requestingUserID int64) ([]*model.ScheduledTweet, error) {
+	return service.storage.GetScheduledTweets(requestingUserID)
+}
+
+func (service *Service) CancelScheduledTweet(scheduledTweetID, requestingUserID int64) error {
+	return service.storage.DeleteScheduledTweet(scheduledTweetID, requestingUserID)
+}
+
+func (service *Service) PublishScheduledTweets(limit int) (int, error) {
+	tweets, err := service.storage.PublishScheduledTweets(time.Now(), limit, service.validateScheduledTweet)
+	service.publishTweets(tweets...)
+	return len(tweets), err
+}
+
+func (service *Service) VotePoll(tweetID int64, option int, requestingUserID int64) (*model.Tweet, error) {
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tweet.Poll == nil {
+		return nil, errors.NoResultsError
+	}
+
+	if tweet.Poll.Closed {
+		return nil, errors.PollClosedError
+	}
+
+	if option < 0 || option >= len(tweet.Poll.Options) {
+		return nil, errors.InvalidPollOptionError
+	}
+
+	voted, err := service.storage.VotePoll(tweetID, requestingUserID, option)
+	if err != nil {
+		return nil, err
+	}
+
+	if !voted {
+		return nil, errors.AlreadyVotedError
+	}
+
+	return service.storage.GetTweet(tweetID, requestingUserID)
+}
+
 func (service *Service) DeleteTweet(tweetID, requestingUserID int64) error {
 	// TODO: Maybe fetch Tweet not TweetWithAuthor
 	databaseTweet, err := service.storage.GetTweet(tweetID, requestingUserID)
@@ -68,16 +378,162 @@ func (service *Service) DeleteTweet(tweetID, requestingUserID int64) error {
 		return errors.ForbiddenError
 	}
 
-	err = service.storage.DeleteTweet(tweetID, requestingUserID)
+	err = service.storage.DeleteTweet(tweetID)
 	if err != nil {
 		return err
 	}
 
+	service.hub.Publish(stream.UserTopic(requestingUserID), &stream.Event{
+		Type: stream.TweetDeleted,
+		Data: &stream.TweetDeletedData{TweetID: tweetID},
+	})
 	return nil
 }
 
+func (service *Service) RestoreTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
+	tweet, err := service.storage.GetDeletedTweet(tweetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tweet.Author.ID != requestingUserID {
+		return nil, errors.ForbiddenError
+	}
+
+	restored, err := service.storage.RestoreTweet(tweet, service.tweetsConfig.GetRestoreWindow())
+	if err != nil {
+		return nil, err
+	}
+
+	if !restored {
+		return nil, errors.RestoreWindowExpiredError
+	}
+
+	return service.storage.GetTweet(tweetID, requestingUserID)
+}
+
+// PurgeDeletedTweets removes for good tweets which can no longer be restored.
+func (service *Service) PurgeDeletedTweets(limit int) (int, error) {
+	return service.storage.PurgeDeletedTweets(service.tweetsConfig.GetRestoreWindow(), limit)
+}
+
+func (service *Service) EditTweet(tweetID int64, content string, requestingUserID int64) (*model.Tweet, error) {
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tweet.Author.ID != requestingUserID {
+		return nil, errors.ForbiddenError
+	}
+
+	if time.Since(tweet.CreatedAt) > service.tweetsConfig.GetEditWindow() {
+		return nil, errors.EditWindowExpiredError
+	}
+
+	post := &policy.Post{AuthorID: requestingUserID, Content: content}
+	err = service.editPolicy.Check(post)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.storage.EditTweet(tweetID, post.Content, requestingUserID)
+}
+
+func (service *Service) GetTweetRevisions(tweetID, requestingUserID int64) ([]*model.TweetRevision, error) {
+	// check if tweet exists
+	_, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.storage.GetTweetRevisions(tweetID)
+}
+
+// GetConversation returns the tweet together with its ancestors and single
+// page of replies to it (oldest first). Only the first replies of nested
+// replies are returned.
+func (service *Service) GetConversation(tweetID, requestingUserID int64, cursor *model.Cursor, limit int) (*model.Conversation, *model.Cursor, error) {
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ancestors := make([]*model.Tweet, 0)
+	for parentID := tweet.InReplyToID; parentID != 0; {
+		parent, err := service.storage.GetTweet(parentID, requestingUserID)
+		if err == errors.NoResultsError {
+			// parent was deleted so the thread is broken at this point
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		ancestors = append([]*model.Tweet{parent}, ancestors...)
+		parentID = parent.InReplyToID
+	}
+
+	// the tweet and its ancestors are shown even if they are muted so the
+	// thread stays readable, only muted replies are hidden
+	filter, err := service.getMuteFilter(requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replies, nextCursor, err := service.getRepliesTree(tweetID, requestingUserID, filter, cursor, limit, conversationDepth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conversation := &model.Conversation{
+		Ancestors: ancestors,
+		Tweet:     tweet,
+		Replies:   replies,
+	}
+
+	return conversation, nextCursor, nil
+}
+
+// getRepliesTree returns single page of replies to the tweet together with
+// the first replies to them up to the `depth`. Returned cursor points to the
+// next page of the top level replies.
+func (service *Service) getRepliesTree(tweetID, requestingUserID int64, filter *muteFilter, cursor *model.Cursor, limit, depth int) ([]*model.Reply, *model.Cursor, error) {
+	replies := make([]*model.Reply, 0)
+	if depth == 0 {
+		return replies, nil, nil
+	}
+
+	tweets, nextCursor, err := service.storage.GetReplies(tweetID, requestingUserID, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// replies to muted replies are hidden together with them
+	for _, tweet := range filter.filterTweets(tweets) {
+		reply := &model.Reply{Tweet: tweet}
+		if tweet.ReplyCount > 0 {
+			reply.Replies, _, err = service.getRepliesTree(tweet.ID, requestingUserID, filter, nil, conversationNestedRepliesLimit, depth-1)
+			if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			reply.Replies = make([]*model.Reply, 0)
+		}
+
+		replies = append(replies, reply)
+	}
+
+	return replies, nextCursor, nil
+}
+
 func (service *Service) LikeTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
-	err := service.storage.LikeTweet(tweetID, requestingUserID)
+	// make sure that tweet exists and can be seen by the user
+	likedTweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.storage.LikeTweet(tweetID, requestingUserID)
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +543,10 @@ func (service *Service) LikeTweet(tweetID, requestingUserID int64) (*model.Tweet
 		return nil, err
 	}
 
+	service.publishLikeCount(tweet)
+	if !likedTweet.Liked {
+		service.notify(tweet.Author.ID, requestingUserID, stream.LikeNotification, tweetID)
+	}
 	return tweet, nil
 }
 
@@ -101,98 +561,905 @@ func (service *Service) UnlikeTweet(tweetID, requestingUserID int64) (*model.Twe
 		return nil, err
 	}
 
+	service.publishLikeCount(tweet)
 	return tweet, nil
 }
 
-func (service *Service) Feed(requestingUserID int64) ([]*model.Tweet, error) {
-	usersFollowedIDs, err := service.storage.GetFolloweesIDs(requestingUserID)
+func (service *Service) BookmarkTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
+	// make sure that tweet exists, otherwise we would fail on foreign key
+	_, err := service.storage.GetTweet(tweetID, requestingUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	tweets, err := service.storage.GetTweetsByAuthorIDs(usersFollowedIDs, requestingUserID)
+	err = service.storage.BookmarkTweet(tweetID, requestingUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	sort.Sort(utils.TweetsByCreationDateDesc(tweets))
+	return service.storage.GetTweet(tweetID, requestingUserID)
+}
+
+func (service *Service) UnbookmarkTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
+	err := service.storage.UnbookmarkTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
 
-	return tweets, nil
+	return service.storage.GetTweet(tweetID, requestingUserID)
 }
 
-func (service *Service) GetUser(userID, requestingUserID int64) (*model.PublicUser, error) {
-	user, err := service.storage.GetUserByID(userID, requestingUserID)
+// Bookmarks returns tweets bookmarked by the requesting user. Bookmarks are
+// private so there is no way to get bookmarks of other users.
+func (service *Service) Bookmarks(requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	return service.storage.GetBookmarks(requestingUserID, cursor, limit)
+}
 
+// TweetLikers returns users who liked the tweet, most recent likes first.
+func (service *Service) TweetLikers(tweetID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	// likers of tweets which can not be seen are not shown either
+	_, err := service.storage.GetTweet(tweetID, requestingUserID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return user, nil
+	return service.storage.GetLikers(tweetID, requestingUserID, cursor, limit)
 }
 
-func (service *Service) FollowUser(userID, requestingUserID int64) (*model.PublicUser, error) {
-	err := service.storage.FollowUser(userID, requestingUserID)
+func (service *Service) RetweetTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
+	// make sure that tweet exists, otherwise we would fail on foreign key
+	retweetedTweet, err := service.storage.GetTweet(tweetID, requestingUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := service.storage.GetUserByID(userID, requestingUserID)
+	err = service.storage.RetweetTweet(tweetID, requestingUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !retweetedTweet.Retweeted {
+		service.notify(tweet.Author.ID, requestingUserID, stream.RetweetNotification, tweetID)
+	}
+	return tweet, nil
 }
 
-func (service *Service) UnfollowUser(userID, requestingUserID int64) (*model.PublicUser, error) {
-	err := service.storage.UnfollowUser(userID, requestingUserID)
+func (service *Service) UnretweetTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
+	err := service.storage.UnretweetTweet(tweetID, requestingUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := service.storage.GetUserByID(userID, requestingUserID)
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	return tweet, nil
 }
 
-func (service *Service) UserFollowers(userID, requestingUserID int64) ([]*model.PublicUser, error) {
-	followers, err := service.storage.GetFollowers(userID, requestingUserID)
+func (service *Service) UploadMedia(data []byte, requestingUserID int64) (*model.Media, error) {
+	if int64(len(data)) > service.mediaConfig.GetMaxSize() {
+		return nil, errors.MediaTooLargeError
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !allowedMediaTypes[mimeType] {
+		return nil, errors.UnsupportedMediaTypeError
+	}
+
+	// checks if the image is not broken and gets its dimensions
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
 	if err != nil {
-		return nil, err
+		return nil, errors.UnsupportedMediaTypeError
+	}
+
+	newMedia := &model.NewMedia{
+		OwnerID:  requestingUserID,
+		MimeType: mimeType,
+		Width:    imageConfig.Width,
+		Height:   imageConfig.Height,
+		Data:     data,
 	}
 
-	return followers, nil
+	return service.storage.InsertMedia(newMedia)
 }
 
-func (service *Service) UserFollowees(userID, requestingUserID int64) ([]*model.PublicUser, error) {
-	followers, err := service.storage.GetFollowees(userID, requestingUserID)
+// GetMedia returns media with given blob key and its data. Media attached to
+// the tweet can be seen by users who can see the tweet and media which is not
+// attached yet only by its owner. Media which can not be seen is
+// indistinguishable from not existing.
+func (service *Service) GetMedia(key string, requestingUserID int64) (*model.Media, []byte, error) {
+	media, err := service.storage.GetMediaByKey(key)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if media.TweetID == 0 {
+		if media.OwnerID != requestingUserID {
+			return nil, nil, errors.NoResultsError
+		}
+	} else {
+		visible, err := service.storage.IsTweetVisible(media.TweetID, requestingUserID)
+		if err != nil {
+			return nil, nil, err
+		} else if !visible {
+			return nil, nil, errors.NoResultsError
+		}
+	}
+
+	data, err := service.storage.GetMediaBlob(media)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return followers, nil
+	return media, data, nil
+}
+
+func (service *Service) HashtagTweets(hashtag string, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	// hashtags are stored without leading '#' and in lowercase
+	hashtag = strings.ToLower(strings.TrimPrefix(hashtag, "#"))
+
+	return service.storage.GetTweetsByHashtag(hashtag, requestingUserID, cursor, limit)
 }
 
-func (service *Service) FullTextSearch(queryString string, requestingUserID int64) (*model.FullTextSearchResponse, error) {
-	tweets, err := service.storage.GetTweetsUsingQueryString(queryString, requestingUserID)
+// Feed returns single page of tweets and retweets of the requesting user and
+// users followed by the user, ordered from the newest tweet or retweet. If
+// `sinceID` is not zero, only items newer than the tweet with `sinceID` are
+// returned. Muted tweets are filtered out so the page can be shorter than
+// `limit` even if there are more items.
+func (service *Service) Feed(requestingUserID, sinceID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	tweets, nextCursor, err := service.storage.GetHomeTimeline(requestingUserID, sinceID, cursor, limit)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	filter, err := service.getMuteFilter(requestingUserID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	users, err := service.storage.GetUsersUsingQueryString(queryString, requestingUserID)
+	tweets = filter.filterTweets(tweets)
+
+	service.recordImpressions(tweets...)
+	return tweets, nextCursor, nil
+}
+
+// FeedNewCount returns number of items in the feed which are newer than the
+// tweet with `sinceID`. Muted items are not counted so if the user muted
+// anything, the new items are read (without their counters, authors and quoted
+// tweets) instead of only counted. Quotes of muted tweets are counted then.
+func (service *Service) FeedNewCount(requestingUserID, sinceID int64) (*model.NewTweetsCount, error) {
+	filter, err := service.getMuteFilter(requestingUserID)
 	if err != nil {
 		return nil, err
 	}
 
-	result := &model.FullTextSearchResponse{
-		Users:  users,
-		Tweets: tweets,
+	if filter.empty() {
+		count, err := service.storage.CountHomeTimelineSince(requestingUserID, sinceID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.NewTweetsCount{Count: count}, nil
+	}
+
+	tweets, err := service.storage.GetHomeTimelineSince(requestingUserID, sinceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.NewTweetsCount{Count: len(filter.filterTweets(tweets))}, nil
+}
+
+// SubscribeFeed subscribes to live updates of tweets of the requesting user
+// and users followed by the user, except the muted ones. Muted tweets of
+// other users (eg. with muted words) are not delivered either. Users followed
+// and users or words muted after subscribing are not taken into account
+// until the user subscribes again.
+func (service *Service) SubscribeFeed(requestingUserID int64) (*stream.Subscription, error) {
+	followeesIDs, err := service.storage.GetFolloweesIDs(requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := service.getMuteFilter(requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := []string{stream.UserTopic(requestingUserID)}
+	for _, followeeID := range followeesIDs {
+		if !filter.mutedUsersIDs[followeeID] {
+			topics = append(topics, stream.UserTopic(followeeID))
+		}
+	}
+
+	return service.hub.SubscribeFiltered(filter.accepts, topics...), nil
+}
+
+// SubscribeInbox subscribes to events addressed to the requesting user.
+func (service *Service) SubscribeInbox(requestingUserID int64) (*stream.Subscription, error) {
+	return service.hub.Subscribe(stream.InboxTopic(requestingUserID)), nil
+}
+
+// SendTyping tells the recipient that the requesting user is typing a message
+// to the recipient. Indicators are silently dropped if the recipient muted
+// the user so the user can not tell about being muted.
+func (service *Service) SendTyping(requestingUserID, recipientID int64) error {
+	if recipientID == requestingUserID {
+		return errors.ForbiddenError
+	}
+
+	// make sure that the recipient exists
+	_, err := service.storage.GetUserByID(recipientID, requestingUserID)
+	if err != nil {
+		return err
+	}
+
+	muted, err := service.isMuted(recipientID, requestingUserID)
+	if err != nil {
+		return err
+	} else if muted {
+		return nil
+	}
+
+	service.hub.Publish(stream.InboxTopic(recipientID), &stream.Event{
+		Type: stream.Typing,
+		Data: &stream.TypingData{UserID: requestingUserID},
+	})
+	return nil
+}
+
+// publishTweets sends new tweets to streams of their authors and followers
+// and notifies mentioned users and authors of replied tweets.
+func (service *Service) publishTweets(tweets ...*model.Tweet) {
+	for _, tweet := range tweets {
+		service.hub.Publish(stream.UserTopic(tweet.Author.ID), &stream.Event{
+			Type: stream.TweetCreated,
+			Data: tweet,
+		})
+
+		for _, mention := range tweet.Mentions {
+			if service.canSee(mention.UserID, tweet) {
+				service.notify(mention.UserID, tweet.Author.ID, stream.MentionNotification, tweet.ID)
+			}
+		}
+
+		if tweet.InReplyToID != 0 {
+			repliedTweet, err := service.storage.GetTweet(tweet.InReplyToID, tweet.Author.ID)
+			if err == nil && service.canSee(repliedTweet.Author.ID, tweet) {
+				service.notify(repliedTweet.Author.ID, tweet.Author.ID, stream.ReplyNotification, tweet.ID)
+			}
+		}
+	}
+}
+
+// canSee checks if the user can see the tweet so notifications do not reveal
+// tweets visible only to followers.
+func (service *Service) canSee(userID int64, tweet *model.Tweet) bool {
+	if tweet.Visibility != model.VisibilityFollowers || userID == tweet.Author.ID {
+		return true
+	}
+
+	following, err := service.storage.IsFollowing(userID, tweet.Author.ID)
+	return err == nil && following
+}
+
+// notify sends notification caused by the user to the recipient. Users are
+// not notified about their own actions nor actions of users they muted.
+func (service *Service) notify(recipientID, userID int64, kind string, tweetID int64) {
+	if recipientID == userID {
+		return
+	}
+
+	if muted, err := service.isMuted(recipientID, userID); err != nil || muted {
+		return
+	}
+
+	service.hub.Publish(stream.InboxTopic(recipientID), &stream.Event{
+		Type: stream.Notification,
+		Data: &stream.NotificationData{Kind: kind, UserID: userID, TweetID: tweetID},
+	})
+}
+
+// publishLikeCount sends current like count of the tweet to streams of its
+// author and followers.
+func (service *Service) publishLikeCount(tweet *model.Tweet) {
+	service.hub.Publish(stream.UserTopic(tweet.Author.ID), &stream.Event{
+		Type: stream.LikeCountChanged,
+		Data: &stream.LikeCountChangedData{TweetID: tweet.ID, LikeCount: tweet.LikeCount},
+	})
+}
+
+func (service *Service) GetUser(userID, requestingUserID int64) (*model.PublicUser, error) {
+	user, err := service.storage.GetUserByID(userID, requestingUserID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if user.PinnedTweetID != 0 {
+		user.PinnedTweet, err = service.storage.GetTweet(user.PinnedTweetID, requestingUserID)
+		if err == errors.NoResultsError {
+			// tweet has been deleted in the meantime
+			user.PinnedTweet = nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// RecordProfileClick counts click on the profile of the user from the tweet.
+// Clicks from tweets of other users are ignored.
+func (service *Service) RecordProfileClick(tweetID, userID, requestingUserID int64) error {
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return err
+	}
+
+	if tweet.Author.ID != userID {
+		return nil
+	}
+
+	return service.storage.RecordProfileClick(tweetID)
+}
+
+func (service *Service) PinTweet(tweetID, requestingUserID int64) (*model.PublicUser, error) {
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tweet.Author.ID != requestingUserID {
+		return nil, errors.ForbiddenError
+	}
+
+	err = service.storage.PinTweet(requestingUserID, tweetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.GetUser(requestingUserID, requestingUserID)
+}
+
+func (service *Service) UnpinTweet(tweetID, requestingUserID int64) (*model.PublicUser, error) {
+	tweet, err := service.storage.GetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tweet.Author.ID != requestingUserID {
+		return nil, errors.ForbiddenError
+	}
+
+	err = service.storage.UnpinTweet(requestingUserID, tweetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.GetUser(requestingUserID, requestingUserID)
+}
+
+func (service *Service) FollowUser(userID, requestingUserID int64) (*model.PublicUser, error) {
+	following, err := service.storage.IsFollowing(requestingUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.storage.FollowUser(userID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !following {
+		service.notify(userID, requestingUserID, stream.FollowNotification, 0)
+	}
+	return user, nil
+}
+
+func (service *Service) UnfollowUser(userID, requestingUserID int64) (*model.PublicUser, error) {
+	err := service.storage.UnfollowUser(userID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (service *Service) UserFollowers(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	// check if user exists
+	_, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.storage.GetFollowers(userID, requestingUserID, cursor, limit)
+}
+
+func (service *Service) UserFollowees(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	// check if user exists
+	_, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.storage.GetFollowees(userID, requestingUserID, cursor, limit)
+}
+
+func (service *Service) UserMentions(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	// check if user exists
+	_, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.storage.GetMentions(userID, requestingUserID, cursor, limit)
+}
+
+// UserLikes returns tweets liked by the user, most recently liked first.
+func (service *Service) UserLikes(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	// check if user exists
+	_, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.storage.GetLikedTweets(userID, requestingUserID, cursor, limit)
+}
+
+// CreateList creates new list owned by the requesting user.
+func (service *Service) CreateList(newList *model.NewList, requestingUserID int64) (*model.List, error) {
+	newList.Name = strings.TrimSpace(newList.Name)
+	if newList.Name == "" || utf8.RuneCountInString(newList.Name) > maxListNameLength {
+		return nil, errors.InvalidListNameError
+	}
+
+	newList.OwnerID = requestingUserID
+	return service.storage.InsertList(newList)
+}
+
+// GetList returns the list. Private lists can be seen only by their owners,
+// other users get `NoResultsError` as if they did not exist.
+func (service *Service) GetList(listID, requestingUserID int64) (*model.List, error) {
+	list, err := service.storage.GetList(listID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Private && list.Owner.ID != requestingUserID {
+		return nil, errors.NoResultsError
+	}
+
+	return list, nil
+}
+
+func (service *Service) DeleteList(listID, requestingUserID int64) error {
+	_, err := service.getOwnedList(listID, requestingUserID)
+	if err != nil {
+		return err
+	}
+
+	return service.storage.DeleteList(listID)
+}
+
+// Lists returns lists owned by the requesting user followed by lists
+// subscribed by the user.
+func (service *Service) Lists(requestingUserID int64) ([]*model.List, error) {
+	ownedLists, err := service.storage.GetListsByOwnerID(requestingUserID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribedLists, err := service.storage.GetSubscribedLists(requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(ownedLists, subscribedLists...), nil
+}
+
+// UserLists returns lists owned by the user which can be seen by the
+// requesting user.
+func (service *Service) UserLists(userID, requestingUserID int64) ([]*model.List, error) {
+	// check if user exists
+	_, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	lists, err := service.storage.GetListsByOwnerID(userID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	visibleLists := make([]*model.List, 0, len(lists))
+	for _, list := range lists {
+		if !list.Private || userID == requestingUserID {
+			visibleLists = append(visibleLists, list)
+		}
+	}
+
+	return visibleLists, nil
+}
+
+// ListMembers returns members of the list, most recently added first.
+func (service *Service) ListMembers(listID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	_, err := service.GetList(listID, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return service.storage.GetListMembers(listID, requestingUserID, cursor, limit)
+}
+
+// AddListMember adds the user to the list of the requesting user.
+func (service *Service) AddListMember(listID, userID, requestingUserID int64) (*model.List, error) {
+	_, err := service.getOwnedList(listID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// make sure that user exists, otherwise we would fail on foreign key
+	_, err = service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.storage.AddListMember(listID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.storage.GetList(listID, requestingUserID)
+}
+
+// RemoveListMember removes the user from the list of the requesting user.
+func (service *Service) RemoveListMember(listID, userID, requestingUserID int64) (*model.List, error) {
+	_, err := service.getOwnedList(listID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.storage.RemoveListMember(listID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.storage.GetList(listID, requestingUserID)
+}
+
+// ListTimeline works like `Feed` but returns tweets and retweets of members
+// of the list. Muted tweets are filtered out like in `Feed`.
+func (service *Service) ListTimeline(listID, requestingUserID, sinceID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	_, err := service.GetList(listID, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	membersIDs, err := service.storage.GetListMembersIDs(listID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tweets, nextCursor, err := service.storage.GetTimeline(membersIDs, requestingUserID, sinceID, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter, err := service.getMuteFilter(requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tweets = filter.filterTweets(tweets)
+
+	service.recordImpressions(tweets...)
+	return tweets, nextCursor, nil
+}
+
+// SubscribeList subscribes the requesting user to the public list of other
+// user.
+func (service *Service) SubscribeList(listID, requestingUserID int64) (*model.List, error) {
+	list, err := service.GetList(listID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Owner.ID == requestingUserID {
+		return nil, errors.ForbiddenError
+	}
+
+	err = service.storage.SubscribeList(listID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.storage.GetList(listID, requestingUserID)
+}
+
+func (service *Service) UnsubscribeList(listID, requestingUserID int64) (*model.List, error) {
+	_, err := service.GetList(listID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.storage.UnsubscribeList(listID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.storage.GetList(listID, requestingUserID)
+}
+
+// getOwnedList returns the list if it is owned by the requesting user.
+func (service *Service) getOwnedList(listID, requestingUserID int64) (*model.List, error) {
+	list, err := service.GetList(listID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Owner.ID != requestingUserID {
+		return nil, errors.ForbiddenError
+	}
+
+	return list, nil
+}
+
+// MuteUser mutes the user for the requesting user. Muted user is not notified
+// about it.
+func (service *Service) MuteUser(userID, requestingUserID int64) error {
+	// check if user exists
+	_, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return err
+	}
+
+	return service.storage.MuteUser(requestingUserID, userID)
+}
+
+func (service *Service) UnmuteUser(userID, requestingUserID int64) error {
+	// check if user exists
+	_, err := service.storage.GetUserByID(userID, requestingUserID)
+	if err != nil {
+		return err
+	}
+
+	return service.storage.UnmuteUser(requestingUserID, userID)
+}
+
+// MutedUsers returns users muted by the requesting user, most recently muted
+// first.
+func (service *Service) MutedUsers(requestingUserID int64) ([]*model.PublicUser, error) {
+	return service.storage.GetMutedUsers(requestingUserID)
+}
+
+// MuteWord mutes the word (or phrase) for the requesting user. Words are
+// stored in lowercase with single spaces between the words of the phrase.
+func (service *Service) MuteWord(word string, requestingUserID int64) error {
+	word, err := normalizeMutedWord(word)
+	if err != nil {
+		return err
+	}
+
+	return service.storage.MuteWord(requestingUserID, word)
+}
+
+func (service *Service) UnmuteWord(word string, requestingUserID int64) error {
+	word, err := normalizeMutedWord(word)
+	if err != nil {
+		return err
+	}
+
+	return service.storage.UnmuteWord(requestingUserID, word)
+}
+
+// MutedWords returns words muted by the requesting user in alphabetical order.
+func (service *Service) MutedWords(requestingUserID int64) ([]string, error) {
+	words, err := service.storage.GetMutedWords(requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(words)
+	return words, nil
+}
+
+func normalizeMutedWord(word string) (string, error) {
+	word = strings.ToLower(strings.Join(strings.Fields(word), " "))
+
+	// word without letters and digits would never be matched
+	hasLetters := strings.IndexFunc(word, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) >= 0
+	if !hasLetters || utf8.RuneCountInString(word) > maxMutedWordLength {
+		return "", errors.InvalidMutedWordError
+	}
+
+	return word, nil
+}
+
+// muteFilter tells which tweets should not be shown to the user because of
+// users or words muted by the user.
+type muteFilter struct {
+	userID        int64
+	mutedUsersIDs map[int64]bool
+	mutedWords    []string
+}
+
+func (service *Service) getMuteFilter(requestingUserID int64) (*muteFilter, error) {
+	mutedUsersIDs, err := service.storage.GetMutedUsersIDs(requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	mutedWords, err := service.storage.GetMutedWords(requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &muteFilter{
+		userID:        requestingUserID,
+		mutedUsersIDs: make(map[int64]bool, len(mutedUsersIDs)),
+		mutedWords:    mutedWords,
+	}
+
+	for _, mutedUserID := range mutedUsersIDs {
+		filter.mutedUsersIDs[mutedUserID] = true
+	}
+
+	return filter, nil
+}
+
+// isMuted checks if the user muted the other user.
+func (service *Service) isMuted(userID, mutedUserID int64) (bool, error) {
+	mutedUsersIDs, err := service.storage.GetMutedUsersIDs(userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range mutedUsersIDs {
+		if id == mutedUserID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// mutes checks if the tweet is muted. Tweet is muted when it was written or
+// retweeted by muted user, contains muted word or quotes muted tweet. Words
+// are matched like banned terms so muting "go" mutes "#go" too. Tweets written
+// or retweeted by the user are never muted.
+func (filter *muteFilter) mutes(tweet *model.Tweet) bool {
+	if tweet.Unavailable {
+		return false
+	}
+
+	if tweet.Author.ID == filter.userID || (tweet.RetweetedBy != nil && tweet.RetweetedBy.ID == filter.userID) {
+		return false
+	}
+
+	if filter.mutedUsersIDs[tweet.Author.ID] || (tweet.RetweetedBy != nil && filter.mutedUsersIDs[tweet.RetweetedBy.ID]) {
+		return true
+	}
+
+	content := strings.ToLower(tweet.Content)
+	for _, mutedWord := range filter.mutedWords {
+		if policy.ContainsWord(content, mutedWord) {
+			return true
+		}
+	}
+
+	return tweet.QuotedTweet != nil && filter.mutes(tweet.QuotedTweet)
+}
+
+// empty checks if the user muted nothing so no tweet is muted.
+func (filter *muteFilter) empty() bool {
+	return len(filter.mutedUsersIDs) == 0 && len(filter.mutedWords) == 0
+}
+
+// accepts checks if the stream event can be sent to the user. Only events
+// about created tweets are filtered. Their data is already decoded by the
+// hub so the filter is cheap to call for each subscriber.
+func (filter *muteFilter) accepts(event *stream.Event) bool {
+	if event.Type != stream.TweetCreated {
+		return true
+	}
+
+	tweet, ok := event.Data.(*model.Tweet)
+	return !ok || tweet.Author == nil || !filter.mutes(tweet)
+}
+
+func (filter *muteFilter) filterTweets(tweets []*model.Tweet) []*model.Tweet {
+	filteredTweets := make([]*model.Tweet, 0, len(tweets))
+	for _, tweet := range tweets {
+		if !filter.mutes(tweet) {
+			filteredTweets = append(filteredTweets, tweet)
+		}
+	}
+
+	return filteredTweets
+}
+
+func (filter *muteFilter) filterUsers(users []*model.PublicUser) []*model.PublicUser {
+	filteredUsers := make([]*model.PublicUser, 0, len(users))
+	for _, user := range users {
+		if !filter.mutedUsersIDs[user.ID] {
+			filteredUsers = append(filteredUsers, user)
+		}
+	}
+
+	return filteredUsers
+}
+
+// FullTextSearch returns single page of users and single page of tweets which
+// match the `queryString`, newest first. Users and tweets are paged
+// independently so one of the lists can be exhausted before the other. Muted
+// users and tweets are filtered out of the pages.
+func (service *Service) FullTextSearch(queryString string, requestingUserID int64, cursor *model.SearchCursor, limit int) (*model.FullTextSearchResponse, *model.SearchCursor, error) {
+	var (
+		result     = &model.FullTextSearchResponse{Users: []*model.PublicUser{}, Tweets: []*model.Tweet{}}
+		nextCursor model.SearchCursor
+	)
+
+	filter, err := service.getMuteFilter(requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cursor == nil || cursor.Tweets != nil {
+		var tweetsCursor *model.Cursor
+		if cursor != nil {
+			tweetsCursor = cursor.Tweets
+		}
+
+		result.Tweets, nextCursor.Tweets, err = service.storage.GetTweetsUsingQueryString(queryString, requestingUserID, tweetsCursor, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result.Tweets = filter.filterTweets(result.Tweets)
+	}
+
+	if cursor == nil || cursor.Users != nil {
+		var usersCursor *model.Cursor
+		if cursor != nil {
+			usersCursor = cursor.Users
+		}
+
+		result.Users, nextCursor.Users, err = service.storage.GetUsersUsingQueryString(queryString, requestingUserID, usersCursor, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result.Users = filter.filterUsers(result.Users)
+	}
+
+	service.recordImpressions(result.Tweets...)
+
+	if nextCursor.Tweets == nil && nextCursor.Users == nil {
+		return result, nil, nil
 	}
 
-	return result, nil
+	return result, &nextCursor, nil
 }
 
 func (service *Service) RegisterUser(newUserForm *model.NewUserForm) (*model.PublicUser, error) {
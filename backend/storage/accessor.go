@@ -7,14 +7,38 @@ import (
 )
 
 type tweetsDataAccessor interface {
-	GetUsersTweets(userID, requestingUserID int64) ([]*model.Tweet, error)
-	GetTweetsByAuthorIDs(authorsIDs []int64, requestingUserID int64) ([]*model.Tweet, error)
+	GetTweetsByAuthorIDs(authorsIDs []int64, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
 	GetTweet(tweetID, requestingUserID int64) (*model.Tweet, error)
-	InsertTweet(tweet *model.NewTweet, requestingUserID int64) (*model.Tweet, error)
-	DeleteTweet(tweetID, requestingUserID int64) error
+	IsTweetVisible(tweetID, requestingUserID int64) (bool, error)
+	InsertTweet(tweet *model.NewTweet) (*model.Tweet, error)
+	EditTweet(tweetID int64, content string, requestingUserID int64) (*model.Tweet, error)
+	GetTweetRevisions(tweetID int64) ([]*model.TweetRevision, error)
+	DeleteTweet(tweetID int64) error
+	GetDeletedTweet(tweetID int64) (*model.Tweet, error)
+	RestoreTweet(tweet *model.Tweet, window time.Duration) (bool, error)
+	PurgeDeletedTweets(window time.Duration, limit int) (int, error)
+	InsertScheduledTweet(tweet *model.NewScheduledTweet) (*model.ScheduledTweet, error)
+	GetScheduledTweets(authorID int64) ([]*model.ScheduledTweet, error)
+	DeleteScheduledTweet(scheduledTweetID, authorID int64) error
+	PublishScheduledTweets(until time.Time, limit int, validate func(*model.NewTweet) error) ([]*model.Tweet, error)
+	GetReplies(tweetID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	GetMentions(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	GetTweetsByHashtag(hashtag string, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
 	LikeTweet(tweetID, userID int64) error
 	UnlikeTweet(tweetID, userID int64) error
-	GetTweetsUsingQueryString(querystring string, requestingUserID int64) ([]*model.Tweet, error)
+	RetweetTweet(tweetID, userID int64) error
+	UnretweetTweet(tweetID, userID int64) error
+	BookmarkTweet(tweetID, userID int64) error
+	UnbookmarkTweet(tweetID, userID int64) error
+	GetBookmarks(userID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	GetLikers(tweetID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
+	GetLikedTweets(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	GetHomeTimeline(userID, sinceID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	GetTimeline(authorsIDs []int64, requestingUserID, sinceID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	CountHomeTimelineSince(userID, sinceID int64) (int, error)
+	GetHomeTimelineSince(userID, sinceID int64) ([]*model.Tweet, error)
+	GetTweetsUsingQueryString(querystring string, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error)
+	HasRecentDuplicate(authorID int64, content string, window time.Duration) (bool, error)
 }
 
 type usersDataAccessor interface {
@@ -22,12 +46,61 @@ type usersDataAccessor interface {
 	GetUserByEmail(email string) (*model.User, error)
 	InsertUser(user *model.NewUserForm) (*model.PublicUser, error)
 	UpdateUserLastLoginTime(userID int64, lastLoginTime *time.Time) error
+	PinTweet(userID, tweetID int64) error
+	UnpinTweet(userID, tweetID int64) error
 	FollowUser(followeeID, followerID int64) error
 	UnfollowUser(followeeID, followerID int64) error
-	GetFollowers(userID, requestingUserID int64) ([]*model.PublicUser, error)
-	GetFollowees(userID, requestingUserID int64) ([]*model.PublicUser, error)
+	GetFollowers(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
+	GetFollowees(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
 	GetFolloweesIDs(userID int64) ([]int64, error)
-	GetUsersUsingQueryString(querystring string, requestingUserID int64) ([]*model.PublicUser, error)
+	IsFollowing(followerID, followeeID int64) (bool, error)
+	GetUsersUsingQueryString(querystring string, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
+	GetUsersByIDs(usersIDs []int64, requestingUserID int64) ([]*model.PublicUser, error)
+}
+
+type mediaDataAccessor interface {
+	InsertMedia(newMedia *model.NewMedia) (*model.Media, error)
+	GetMediaByIDs(mediaIDs []int64) ([]*model.Media, error)
+	GetMediaByKey(key string) (*model.Media, error)
+	GetMediaBlob(media *model.Media) ([]byte, error)
+	GetTweetMedia(tweetID int64) ([]*model.Media, error)
+	DeleteMediaBlobs(media []*model.Media) error
+}
+
+type pollsDataAccessor interface {
+	GetPoll(tweetID, requestingUserID int64) (*model.Poll, error)
+	VotePoll(tweetID, userID int64, option int) (bool, error)
+}
+
+type listsDataAccessor interface {
+	InsertList(newList *model.NewList) (*model.List, error)
+	GetList(listID, requestingUserID int64) (*model.List, error)
+	DeleteList(listID int64) error
+	GetListsByOwnerID(ownerID, requestingUserID int64) ([]*model.List, error)
+	GetSubscribedLists(userID int64) ([]*model.List, error)
+	AddListMember(listID, userID int64) error
+	RemoveListMember(listID, userID int64) error
+	GetListMembers(listID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error)
+	GetListMembersIDs(listID int64) ([]int64, error)
+	SubscribeList(listID, userID int64) error
+	UnsubscribeList(listID, userID int64) error
+}
+
+type mutesDataAccessor interface {
+	MuteUser(userID, mutedUserID int64) error
+	UnmuteUser(userID, mutedUserID int64) error
+	GetMutedUsers(userID int64) ([]*model.PublicUser, error)
+	GetMutedUsersIDs(userID int64) ([]int64, error)
+	MuteWord(userID int64, word string) error
+	UnmuteWord(userID int64, word string) error
+	GetMutedWords(userID int64) ([]string, error)
+}
+
+type analyticsDataAccessor interface {
+	RecordImpressions(tweetsIDs []int64) error
+	RecordProfileClick(tweetID int64) error
+	FlushTweetStats() (int, error)
+	GetTweetAnalytics(tweetID int64, granularity string) (*model.TweetAnalytics, error)
 }
 
 // Accessor is interface which defines all functions used on database/cache/fts
@@ -36,4 +109,11 @@ type usersDataAccessor interface {
 type Accessor interface {
 	usersDataAccessor
 	tweetsDataAccessor
+	mediaDataAccessor
+	pollsDataAccessor
+	listsDataAccessor
+	mutesDataAccessor
+	analyticsDataAccessor
+
+	Close()
 }
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/storage/counter"
+	"github.com/VirrageS/chirp/backend/storage/database"
+)
+
+const (
+	impressionsMetric   = "impressions"
+	profileClicksMetric = "profile_clicks"
+)
+
+// analyticsStorage is struct which implements analyticsDataAccessor using given DAO and counters buffer
+type analyticsStorage struct {
+	analyticsDAO database.AnalyticsDAO
+	counters     counter.Buffer
+}
+
+// newAnalyticsStorage constructs analyticsStorage that uses given analyticsDAO and counters Buffer
+func newAnalyticsStorage(analyticsDAO database.AnalyticsDAO, counters counter.Buffer) analyticsDataAccessor {
+	return &analyticsStorage{
+		analyticsDAO: analyticsDAO,
+		counters:     counters,
+	}
+}
+
+// RecordImpressions counts single impression of each of the tweets. Counts
+// are only buffered, they are saved in the database by FlushTweetStats.
+func (s *analyticsStorage) RecordImpressions(tweetsIDs []int64) error {
+	if len(tweetsIDs) == 0 {
+		return nil
+	}
+
+	hour := currentHour()
+	names := make([]string, 0, len(tweetsIDs))
+	for _, tweetID := range tweetsIDs {
+		names = append(names, counterName(tweetID, impressionsMetric, hour))
+	}
+
+	if err := s.counters.Incr(names...); err != nil {
+		return errors.UnexpectedError
+	}
+
+	return nil
+}
+
+// RecordProfileClick counts single click on the author's profile from the
+// tweet. Counts are only buffered, like impressions.
+func (s *analyticsStorage) RecordProfileClick(tweetID int64) error {
+	if err := s.counters.Incr(counterName(tweetID, profileClicksMetric, currentHour())); err != nil {
+		return errors.UnexpectedError
+	}
+
+	return nil
+}
+
+// FlushTweetStats saves buffered counts in the database and returns number
+// of saved stats. When saving fails counts are put back into the buffer.
+func (s *analyticsStorage) FlushTweetStats() (int, error) {
+	counts, err := s.counters.Drain()
+	if err != nil {
+		return 0, errors.UnexpectedError
+	}
+
+	type statsKey struct {
+		tweetID int64
+		hour    int64
+	}
+
+	statsByKey := make(map[statsKey]*model.TweetStats)
+	for name, count := range counts {
+		var (
+			tweetID int64
+			metric  string
+			hour    int64
+		)
+
+		if _, err := fmt.Sscanf(name, "%d:%d:%s", &tweetID, &hour, &metric); err != nil {
+			log.WithField("name", name).WithError(err).Error("FlushTweetStats: invalid counter name")
+			continue
+		}
+
+		key := statsKey{tweetID, hour}
+		stats, ok := statsByKey[key]
+		if !ok {
+			stats = &model.TweetStats{TweetID: tweetID, Hour: time.Unix(hour, 0).UTC()}
+			statsByKey[key] = stats
+		}
+
+		switch metric {
+		case impressionsMetric:
+			stats.Impressions += count
+		case profileClicksMetric:
+			stats.ProfileClicks += count
+		}
+	}
+
+	if len(statsByKey) == 0 {
+		return 0, nil
+	}
+
+	stats := make([]*model.TweetStats, 0, len(statsByKey))
+	for _, tweetStats := range statsByKey {
+		stats = append(stats, tweetStats)
+	}
+
+	if err := s.analyticsDAO.AddTweetStats(stats); err != nil {
+		s.counters.Add(counts)
+		return 0, errors.UnexpectedError
+	}
+
+	return len(stats), nil
+}
+
+// GetTweetAnalytics returns saved analytics of the tweet. Counts which are
+// still buffered are not included.
+func (s *analyticsStorage) GetTweetAnalytics(tweetID int64, granularity string) (*model.TweetAnalytics, error) {
+	timeline, err := s.analyticsDAO.GetTweetTimeline(tweetID, granularity)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	analytics := &model.TweetAnalytics{
+		TweetID:     tweetID,
+		Granularity: granularity,
+		Timeline:    timeline,
+	}
+	for _, point := range timeline {
+		analytics.Impressions += point.Impressions
+		analytics.Likes += point.Likes
+		analytics.Retweets += point.Retweets
+		analytics.ProfileClicks += point.ProfileClicks
+	}
+
+	return analytics, nil
+}
+
+// counterName encodes counter of the tweet metric within given hour.
+func counterName(tweetID int64, metric string, hour time.Time) string {
+	return fmt.Sprintf("%d:%d:%s", tweetID, hour.Unix(), metric)
+}
+
+func currentHour() time.Time {
+	return time.Now().UTC().Truncate(time.Hour)
+}
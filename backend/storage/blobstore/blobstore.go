@@ -0,0 +1,15 @@
+package blobstore
+
+// BlobStore is interface which defines all functions used to keep uploaded
+// files. All implementations (local file system, S3 etc.) should implement
+// these methods.
+type BlobStore interface {
+	// Put saves data under given key. Existing data is overwritten.
+	Put(key string, data []byte) error
+	// Get returns data saved under given key.
+	Get(key string) ([]byte, error)
+	// Delete removes data saved under given key. Deleting not existing key is not an error.
+	Delete(key string) error
+	// URL returns URL under which data saved with given key is served.
+	URL(key string) string
+}
@@ -0,0 +1,51 @@
+package blobstore
+
+import (
+	"os"
+	"sync"
+)
+
+type fakeBlobStore struct {
+	mutex sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewFakeBlobStore creates new instance of fake blob store which keeps all
+// data in memory.
+func NewFakeBlobStore() BlobStore {
+	return &fakeBlobStore{
+		blobs: make(map[string][]byte),
+	}
+}
+
+func (store *fakeBlobStore) Put(key string, data []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.blobs[key] = data
+	return nil
+}
+
+func (store *fakeBlobStore) Get(key string) ([]byte, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	data, ok := store.blobs[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	return data, nil
+}
+
+func (store *fakeBlobStore) Delete(key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.blobs, key)
+	return nil
+}
+
+func (store *fakeBlobStore) URL(key string) string {
+	return "/media/" + key
+}
@@ -0,0 +1,72 @@
+package blobstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/config"
+)
+
+type localBlobStore struct {
+	directory string
+	baseURL   string
+}
+
+// NewLocalBlobStore constructs BlobStore which keeps files in the directory
+// from config. Files have to be served under base URL from config (which
+// should point to the media handler).
+func NewLocalBlobStore(config config.MediaConfigProvider) BlobStore {
+	directory := config.GetDirectory()
+	if err := os.MkdirAll(directory, 0750); err != nil {
+		log.WithField("directory", directory).WithError(err).Error("Error creating blob store directory.")
+		return nil
+	}
+
+	return &localBlobStore{
+		directory: directory,
+		baseURL:   strings.TrimSuffix(config.GetBaseURL(), "/"),
+	}
+}
+
+func (store *localBlobStore) Put(key string, data []byte) error {
+	err := ioutil.WriteFile(store.path(key), data, 0640)
+	if err != nil {
+		log.WithField("key", key).WithError(err).Error("Put: failed to write blob.")
+		return err
+	}
+
+	return nil
+}
+
+func (store *localBlobStore) Get(key string) ([]byte, error) {
+	data, err := ioutil.ReadFile(store.path(key))
+	if err != nil {
+		log.WithField("key", key).WithError(err).Error("Get: failed to read blob.")
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (store *localBlobStore) Delete(key string) error {
+	err := os.Remove(store.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		log.WithField("key", key).WithError(err).Error("Delete: failed to remove blob.")
+		return err
+	}
+
+	return nil
+}
+
+func (store *localBlobStore) URL(key string) string {
+	return store.baseURL + "/" + key
+}
+
+// path returns path of the file for given key. Key cannot escape the directory.
+func (store *localBlobStore) path(key string) string {
+	return filepath.Join(store.directory, filepath.Base(key))
+}
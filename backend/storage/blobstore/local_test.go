@@ -0,0 +1,84 @@
+package blobstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type testMediaConfig struct {
+	directory string
+}
+
+func (config *testMediaConfig) GetDirectory() string {
+	return config.directory
+}
+
+func (config *testMediaConfig) GetBaseURL() string {
+	return "http://localhost/media/"
+}
+
+func (config *testMediaConfig) GetMaxSize() int64 {
+	return 1024
+}
+
+var _ = Describe("LocalBlobStore", func() {
+	var (
+		root  string
+		store BlobStore
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		root, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		store = NewLocalBlobStore(&testMediaConfig{filepath.Join(root, "media")})
+		Expect(store).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(root)
+	})
+
+	It("should put, get and delete blob", func() {
+		path := filepath.Join(root, "media", "key.png")
+
+		Expect(store.Put("key.png", []byte("data"))).To(Succeed())
+		data, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal([]byte("data")))
+
+		data, err = store.Get("key.png")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal([]byte("data")))
+
+		Expect(store.Delete("key.png")).To(Succeed())
+		_, err = os.Stat(path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		_, err = store.Get("key.png")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not fail when deleting not existing blob", func() {
+		Expect(store.Delete("unknown")).To(Succeed())
+	})
+
+	It("should not allow to escape the directory", func() {
+		Expect(store.Put("../escaped", []byte("data"))).To(Succeed())
+
+		_, err := os.Stat(filepath.Join(root, "escaped"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		_, err = os.Stat(filepath.Join(root, "media", "escaped"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return URL under base URL", func() {
+		Expect(store.URL("key.png")).To(Equal("http://localhost/media/key.png"))
+	})
+})
@@ -42,6 +42,7 @@ type Accessor interface {
 	Decr(keys ...Key) error
 
 	// SAdd adds array of new `values` for set stored at `key`.
+	// Set is created even if `values` are empty.
 	SAdd(key Key, values Values) error
 
 	// SMembers gets all values from set stored at `key`.
@@ -12,6 +12,11 @@ import (
 	"github.com/VirrageS/chirp/backend/config"
 )
 
+// emptySetMember is added to sets created without values since Redis does not
+// keep empty sets. Values are never marshaled to empty string (muted words
+// and other strings kept in sets can not be empty) so it is safe to skip it.
+const emptySetMember = ""
+
 type redisCache struct {
 	client *redis.Client
 	config config.CacheConfigProvider
@@ -177,6 +182,11 @@ func (cache *redisCache) SAdd(key Key, values Values) error {
 		return err
 	}
 
+	// empty set is cached as well, so it is not read from the database each time
+	if len(members) == 0 {
+		members = append(members, emptySetMember)
+	}
+
 	return cache.client.SAdd(cache.convertKeyToHash(key), members...).Err()
 }
 
@@ -199,6 +209,13 @@ func (cache *redisCache) SMembers(key Key, values Values) (bool, error) {
 		return false, err
 	}
 
+	for i, result := range results {
+		if result == emptySetMember {
+			results = append(results[:i], results[i+1:]...)
+			break
+		}
+	}
+
 	err = cache.unmarshalValues(results, values)
 	if err != nil {
 		log.WithField("values", values).WithError(err).Error("SMembers: failed to unmarshal value")
@@ -246,6 +263,8 @@ func (cache *redisCache) unmarshalValue(result string, value Value) error {
 	case *int:
 		val, err = strconv.ParseInt(result, 10, 0)
 		*value = int(val)
+	case *string:
+		*value = result
 	default:
 		err = msgpack.Unmarshal([]byte(result), value)
 	}
@@ -261,7 +280,7 @@ func (cache *redisCache) marshalValue(value Value) (interface{}, error) {
 	)
 
 	switch value := value.(type) {
-	case int64, int32, int16, int8, int:
+	case int64, int32, int16, int8, int, string:
 		data = value
 	default:
 		data, err = msgpack.Marshal(value)
@@ -285,6 +304,16 @@ func (cache *redisCache) marshalValues(values Values) ([]interface{}, error) {
 
 			members = append(members, data)
 		}
+	case []string:
+		members = make([]interface{}, 0, len(v))
+		for _, value := range v {
+			data, err := cache.marshalValue(value)
+			if err != nil {
+				return nil, err
+			}
+
+			members = append(members, data)
+		}
 	default:
 		panic("marshalValues: invalid `values` type")
 	}
@@ -302,6 +331,10 @@ func (cache *redisCache) unmarshalValues(results []string, values Values) error
 			var item int64
 			err = cache.unmarshalValue(result, &item)
 			*v = append(*v, item)
+		case *[]string:
+			var item string
+			err = cache.unmarshalValue(result, &item)
+			*v = append(*v, item)
 		default:
 			panic("unmarshalValues: unsupported values")
 		}
@@ -310,6 +310,38 @@ var _ = Describe("RedisCache", func() {
 			Expect(members).To(ConsistOf(expectedValues))
 		})
 
+		It("should get string set members", func() {
+			var members []string
+
+			err := redisCache.SAdd(key, []string{"word", "#hashtag", "word"})
+			Expect(err).NotTo(HaveOccurred())
+
+			exists, err := redisCache.SMembers(key, &members)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+			Expect(members).To(ConsistOf("word", "#hashtag"))
+		})
+
+		It("should get empty set", func() {
+			var members []int64
+
+			err := redisCache.SAdd(key, []int64{})
+			Expect(err).NotTo(HaveOccurred())
+
+			exists, err := redisCache.SMembers(key, &members)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+			Expect(members).To(BeEmpty())
+
+			err = redisCache.SAdd(key, []int64{1})
+			Expect(err).NotTo(HaveOccurred())
+
+			exists, err = redisCache.SMembers(key, &members)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+			Expect(members).To(Equal([]int64{1}))
+		})
+
 		It("should not get set members when key is not set", func() {
 			var members []int64
 
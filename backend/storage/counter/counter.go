@@ -0,0 +1,15 @@
+package counter
+
+// Buffer is interface which defines all functions used to count events
+// (eg. impressions) without writing to the database on each of them. Counts
+// are accumulated in the buffer and periodically drained and saved.
+type Buffer interface {
+	// Incr increments by 1 counter for each of the `names`.
+	Incr(names ...string) error
+	// Drain removes all counters from the buffer and returns their values.
+	// Counters incremented during draining are kept for the next drain.
+	Drain() (map[string]int64, error)
+	// Add adds `counts` back to the buffer. It should be used when drained
+	// counts could not be saved so they are not lost.
+	Add(counts map[string]int64) error
+}
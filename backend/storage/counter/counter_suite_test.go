@@ -0,0 +1,13 @@
+package counter
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCounter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Counter")
+}
@@ -0,0 +1,47 @@
+package counter
+
+import "sync"
+
+type fakeBuffer struct {
+	mutex  sync.Mutex
+	counts map[string]int64
+}
+
+// NewFakeBuffer creates new instance of fake buffer which keeps all counters
+// in memory.
+func NewFakeBuffer() Buffer {
+	return &fakeBuffer{
+		counts: make(map[string]int64),
+	}
+}
+
+func (buffer *fakeBuffer) Incr(names ...string) error {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+
+	for _, name := range names {
+		buffer.counts[name]++
+	}
+
+	return nil
+}
+
+func (buffer *fakeBuffer) Drain() (map[string]int64, error) {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+
+	counts := buffer.counts
+	buffer.counts = make(map[string]int64)
+	return counts, nil
+}
+
+func (buffer *fakeBuffer) Add(counts map[string]int64) error {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+
+	for name, count := range counts {
+		buffer.counts[name] += count
+	}
+
+	return nil
+}
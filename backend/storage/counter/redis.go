@@ -0,0 +1,116 @@
+package counter
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/Sirupsen/logrus"
+	"gopkg.in/redis.v5"
+
+	"github.com/VirrageS/chirp/backend/config"
+)
+
+type redisBuffer struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisBuffer constructs buffer which keeps counters in Redis hash stored
+// at `key`.
+func NewRedisBuffer(config config.RedisConfigProvider, key string) Buffer {
+	address := fmt.Sprintf("%s:%s", config.GetHost(), config.GetPort())
+
+	client := redis.NewClient(&redis.Options{
+		Addr:       address,
+		Password:   config.GetPassword(),
+		DB:         config.GetDB(),
+		MaxRetries: 3,
+	})
+
+	if _, err := client.Ping().Result(); err != nil {
+		log.WithError(err).Error("Error connecting to counters instance.")
+		return nil
+	}
+
+	return &redisBuffer{
+		client: client,
+		key:    key,
+	}
+}
+
+// Incr increments counters stored as fields of the hash.
+// Operations are pipelined so there is only one message sent to Redis instance.
+func (buffer *redisBuffer) Incr(names ...string) error {
+	return buffer.add(names, func(string) int64 { return 1 })
+}
+
+// Drain renames the hash so new increments go to the new hash and then reads
+// and removes renamed one in a single transaction, so the same counts are
+// never drained twice, even by different servers. If reading fails, counts
+// are left in the renamed hash and are drained by the next call before the
+// hash is renamed again, so no increment is lost.
+func (buffer *redisBuffer) Drain() (map[string]int64, error) {
+	drainingKey := buffer.key + ":draining"
+
+	// Hash is not renamed when the renamed one was not drained yet. New
+	// increments stay in the hash until the next call.
+	err := buffer.client.RenameNX(buffer.key, drainingKey).Err()
+	if err != nil && err.Error() != "ERR no such key" {
+		log.WithField("key", buffer.key).WithError(err).Error("Drain: failed to rename counters")
+		return nil, err
+	}
+
+	var getFields *redis.StringStringMapCmd
+	_, err = buffer.client.TxPipelined(func(pipe *redis.Pipeline) error {
+		getFields = pipe.HGetAll(drainingKey)
+		pipe.Del(drainingKey)
+		return nil
+	})
+	if err != nil {
+		log.WithField("key", drainingKey).WithError(err).Error("Drain: failed to get counters")
+		return nil, err
+	}
+
+	fields := getFields.Val()
+	counts := make(map[string]int64, len(fields))
+	for name, value := range fields {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.WithField("value", value).WithError(err).Error("Drain: invalid counter value")
+			continue
+		}
+
+		counts[name] = count
+	}
+
+	return counts, nil
+}
+
+// Add increments counters by given counts.
+// Operations are pipelined so there is only one message sent to Redis instance.
+func (buffer *redisBuffer) Add(counts map[string]int64) error {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+
+	return buffer.add(names, func(name string) int64 { return counts[name] })
+}
+
+func (buffer *redisBuffer) add(names []string, count func(name string) int64) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	pipe := buffer.client.Pipeline()
+	for _, name := range names {
+		pipe.HIncrBy(buffer.key, name, count(name))
+	}
+
+	if _, err := pipe.Exec(); err != nil {
+		log.WithField("names", names).WithError(err).Error("Add: failed to increment counters")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package counter
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+)
+
+var _ = Describe("RedisBuffer", func() {
+	var (
+		conf   *config.Configuration = config.New()
+		buffer Buffer                = NewRedisBuffer(conf.Redis, "test.counters")
+	)
+
+	AfterEach(func() {
+		buffer.Drain()
+	})
+
+	It("should drain incremented counters", func() {
+		Expect(buffer.Incr("first", "second", "first")).To(Succeed())
+
+		counts, err := buffer.Drain()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(Equal(map[string]int64{"first": 2, "second": 1}))
+	})
+
+	It("should return no counters when buffer is empty", func() {
+		counts, err := buffer.Drain()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(BeEmpty())
+	})
+
+	It("should keep counters added back after drain", func() {
+		Expect(buffer.Incr("first")).To(Succeed())
+
+		counts, err := buffer.Drain()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(buffer.Incr("first")).To(Succeed())
+		Expect(buffer.Add(counts)).To(Succeed())
+
+		counts, err = buffer.Drain()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(Equal(map[string]int64{"first": 2}))
+	})
+
+	It("should drain counters left by failed drain first", func() {
+		// counters renamed by drain which failed before reading them
+		client := buffer.(*redisBuffer).client
+		Expect(client.HIncrBy("test.counters:draining", "first", 2).Err()).To(Succeed())
+		Expect(buffer.Incr("first")).To(Succeed())
+
+		counts, err := buffer.Drain()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(Equal(map[string]int64{"first": 2}))
+
+		counts, err = buffer.Drain()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(Equal(map[string]int64{"first": 1}))
+	})
+})
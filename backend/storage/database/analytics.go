@@ -0,0 +1,89 @@
+package database
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+// AnalyticsDAO (Analytics Data Access Object) is interface which provides operations on TweetStats database table.
+type AnalyticsDAO interface {
+	AddTweetStats(stats []*model.TweetStats) error
+	GetTweetTimeline(tweetID int64, granularity string) ([]*model.AnalyticsPoint, error)
+}
+
+type analyticsDB struct {
+	*Connection
+}
+
+// NewAnalyticsDAO creates new struct which implements AnalyticsDAO functions.
+func NewAnalyticsDAO(conn *Connection) AnalyticsDAO {
+	return &analyticsDB{conn}
+}
+
+// AddTweetStats adds counts to already saved stats. All stats are saved in
+// single transaction. Stats of tweets which no longer exist are skipped.
+func (db *analyticsDB) AddTweetStats(stats []*model.TweetStats) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.WithError(err).Error("AddTweetStats begin transaction error.")
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, tweetStats := range stats {
+		_, err := tx.Exec(
+			`INSERT INTO tweet_stats (tweet_id, hour, impressions, profile_clicks)
+				SELECT id, $2, $3, $4 FROM tweets WHERE id = $1
+				ON CONFLICT (tweet_id, hour) DO UPDATE SET
+					impressions = tweet_stats.impressions + EXCLUDED.impressions,
+					profile_clicks = tweet_stats.profile_clicks + EXCLUDED.profile_clicks`,
+			tweetStats.TweetID, tweetStats.Hour.UTC(), tweetStats.Impressions, tweetStats.ProfileClicks,
+		)
+		if err != nil {
+			log.WithField("stats", tweetStats).WithError(err).Error("AddTweetStats query error.")
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.WithError(err).Error("AddTweetStats commit error.")
+		return err
+	}
+
+	return nil
+}
+
+// GetTweetTimeline returns counts of the tweet events grouped by `granularity`
+// (hour or day), oldest first. Periods without any events are skipped.
+func (db *analyticsDB) GetTweetTimeline(tweetID int64, granularity string) ([]*model.AnalyticsPoint, error) {
+	rows, err := db.Query(
+		`SELECT time, SUM(impressions)::BIGINT, SUM(likes)::BIGINT, SUM(retweets)::BIGINT, SUM(profile_clicks)::BIGINT
+			FROM (
+				SELECT date_trunc($2, hour) AS time, impressions, 0 AS likes, 0 AS retweets, profile_clicks
+					FROM tweet_stats WHERE tweet_id = $1
+				UNION ALL
+				SELECT date_trunc($2, liked_at), 0, 1, 0, 0 FROM likes WHERE tweet_id = $1
+				UNION ALL
+				SELECT date_trunc($2, retweeted_at), 0, 0, 1, 0 FROM retweets WHERE tweet_id = $1
+			) AS events
+			GROUP BY time ORDER BY time`,
+		tweetID, granularity,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID":     tweetID,
+			"granularity": granularity,
+		}).WithError(err).Error("GetTweetTimeline query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	timeline, err := readMultipleAnalyticsPoints(rows)
+	if err != nil {
+		log.WithError(err).Error("GetTweetTimeline rows scan/iteration error.")
+		return nil, err
+	}
+
+	return timeline, nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Analytics", func() {
+	var (
+		conf         *config.Configuration = config.New()
+		db                                 = NewPostgresDatabase(conf.Postgres)
+		usersDAO                           = NewUserDAO(db)
+		tweetsDAO                          = NewTweetDAO(db)
+		likesDAO                           = NewLikesDAO(db)
+		analyticsDAO                       = NewAnalyticsDAO(db)
+
+		user  *model.PublicUser
+		tweet *model.Tweet
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		user, err = usersDAO.InsertUser(&model.NewUserForm{
+			Username: "user",
+			Password: "password",
+			Email:    "user@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err = tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "tweet"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM tweets; DELETE FROM likes; DELETE FROM tweet_stats;`)
+	})
+
+	It("should add stats to already saved ones and group them with likes", func() {
+		hour := time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC)
+
+		err := analyticsDAO.AddTweetStats([]*model.TweetStats{
+			{TweetID: tweet.ID, Hour: hour, Impressions: 2, ProfileClicks: 1},
+			{TweetID: tweet.ID, Hour: hour.Add(time.Hour), Impressions: 3},
+			{TweetID: tweet.ID + 1000, Hour: hour, Impressions: 5}, // not existing tweet
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = analyticsDAO.AddTweetStats([]*model.TweetStats{
+			{TweetID: tweet.ID, Hour: hour, Impressions: 1},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = likesDAO.LikeTweet(tweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(`UPDATE likes SET liked_at = $1 WHERE tweet_id = $2`, hour.Add(10*time.Minute), tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		timeline, err := analyticsDAO.GetTweetTimeline(tweet.ID, model.GranularityHour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timeline).To(HaveLen(2))
+		Expect(timeline[0].Time.Equal(hour)).To(BeTrue())
+		Expect(timeline[0].Impressions).To(BeEquivalentTo(3))
+		Expect(timeline[0].Likes).To(BeEquivalentTo(1))
+		Expect(timeline[0].Retweets).To(BeZero())
+		Expect(timeline[0].ProfileClicks).To(BeEquivalentTo(1))
+		Expect(timeline[1].Impressions).To(BeEquivalentTo(3))
+
+		timeline, err = analyticsDAO.GetTweetTimeline(tweet.ID, model.GranularityDay)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timeline).To(HaveLen(1))
+		Expect(timeline[0].Impressions).To(BeEquivalentTo(6))
+		Expect(timeline[0].Likes).To(BeEquivalentTo(1))
+		Expect(timeline[0].ProfileClicks).To(BeEquivalentTo(1))
+	})
+})
@@ -0,0 +1,118 @@
+package database
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+// BookmarksDAO (Bookmarks Data Access Object) is interface which provides operations on Bookmarks database table.
+type BookmarksDAO interface {
+	BookmarkTweet(tweetID, userID int64) (bool, error)
+	UnbookmarkTweet(tweetID, userID int64) (bool, error)
+	IsBookmarked(tweetID, userID int64) (bool, error)
+	GetBookmarkedTweetsIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+}
+
+type bookmarksDB struct {
+	*Connection
+}
+
+// NewBookmarksDAO creates new struct which implements BookmarksDAO functions.
+func NewBookmarksDAO(conn *Connection) BookmarksDAO {
+	return &bookmarksDB{conn}
+}
+
+func (db *bookmarksDB) BookmarkTweet(tweetID, userID int64) (bool, error) {
+	result, err := db.Exec(
+		`INSERT INTO bookmarks (tweet_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (tweet_id, user_id) DO NOTHING`,
+		tweetID, userID,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"userID":  userID,
+		}).WithError(err).Error("BookmarkTweet query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *bookmarksDB) UnbookmarkTweet(tweetID, userID int64) (bool, error) {
+	result, err := db.Exec(`DELETE FROM bookmarks WHERE tweet_id = $1 AND user_id = $2`, tweetID, userID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"userID":  userID,
+		}).WithError(err).Error("UnbookmarkTweet query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *bookmarksDB) IsBookmarked(tweetID, userID int64) (bool, error) {
+	var isBookmarked bool
+
+	err := db.QueryRow(
+		`SELECT exists (SELECT TRUE FROM bookmarks WHERE tweet_id = $1 AND user_id = $2)`,
+		tweetID, userID,
+	).Scan(&isBookmarked)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"userID":  userID,
+		}).WithError(err).Error("IsBookmarked query error.")
+		return false, err
+	}
+
+	return isBookmarked, nil
+}
+
+// GetBookmarkedTweetsIDs returns IDs of at most `limit` tweets bookmarked by
+// the user, most recently bookmarked first, which come after the `cursor`
+// (nil means the first page). Returned cursor is nil if there are no more
+// bookmarks.
+func (db *bookmarksDB) GetBookmarkedTweetsIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT bookmarks.tweet_id, bookmarks.bookmarked_at FROM bookmarks
+			JOIN tweets ON tweets.id = bookmarks.tweet_id
+			WHERE bookmarks.user_id = $1 AND tweets.deleted_at IS NULL
+				AND ($2::TIMESTAMP IS NULL OR (bookmarks.bookmarked_at, bookmarks.tweet_id) < ($2, $3))
+			ORDER BY bookmarks.bookmarked_at DESC, bookmarks.tweet_id DESC
+			LIMIT $4`,
+		userID, cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID": userID,
+			"cursor": cursor,
+			"limit":  limit,
+		}).WithError(err).Error("GetBookmarkedTweetsIDs query error.")
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	tweetsIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetBookmarkedTweetsIDs rows scan/iteration error.")
+		return nil, nil, err
+	}
+
+	return tweetsIDs, nextCursor, nil
+}
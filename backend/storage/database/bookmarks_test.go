@@ -0,0 +1,88 @@
+package database
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Bookmarks", func() {
+	var (
+		conf         *config.Configuration = config.New()
+		db                                 = NewPostgresDatabase(conf.Postgres)
+		usersDAO                           = NewUserDAO(db)
+		tweetsDAO                          = NewTweetDAO(db)
+		bookmarksDAO                       = NewBookmarksDAO(db)
+
+		user   *model.PublicUser
+		tweets []*model.Tweet
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		user, err = usersDAO.InsertUser(&model.NewUserForm{
+			Username: "user",
+			Password: "password",
+			Email:    "user@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweets = make([]*model.Tweet, 0)
+		for i := 0; i < 5; i++ {
+			tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "tweet"})
+			Expect(err).NotTo(HaveOccurred())
+
+			bookmarked, err := bookmarksDAO.BookmarkTweet(tweet.ID, user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bookmarked).To(BeTrue())
+
+			tweets = append(tweets, tweet)
+		}
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM tweets; DELETE FROM bookmarks;`)
+	})
+
+	It("should not bookmark tweet twice", func() {
+		bookmarked, err := bookmarksDAO.BookmarkTweet(tweets[0].ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bookmarked).To(BeFalse())
+	})
+
+	It("should return all bookmarks page by page", func() {
+		var (
+			cursor    *model.Cursor
+			tweetsIDs = make([]int64, 0)
+		)
+
+		for {
+			pageIDs, nextCursor, err := bookmarksDAO.GetBookmarkedTweetsIDs(user.ID, cursor, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(pageIDs)).To(BeNumerically("<=", 2))
+
+			tweetsIDs = append(tweetsIDs, pageIDs...)
+			if nextCursor == nil {
+				break
+			}
+
+			cursor = nextCursor
+		}
+
+		Expect(tweetsIDs).To(Equal([]int64{
+			tweets[4].ID, tweets[3].ID, tweets[2].ID, tweets[1].ID, tweets[0].ID,
+		}))
+	})
+
+	It("should not return next cursor for the last full page", func() {
+		tweetsIDs, nextCursor, err := bookmarksDAO.GetBookmarkedTweetsIDs(user.ID, nil, 5)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(HaveLen(5))
+		Expect(nextCursor).To(BeNil())
+	})
+})
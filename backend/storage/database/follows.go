@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/model"
 	"github.com/lib/pq"
 )
 
@@ -11,8 +13,10 @@ import (
 type FollowsDAO interface {
 	FollowUser(followeeID, followerID int64) (bool, error)
 	UnfollowUser(followeeID, followerID int64) (bool, error)
-	GetFollowersIDs(userID int64) ([]int64, error)
-	GetFolloweesIDs(userID int64) ([]int64, error)
+	GetFollowersIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+	GetFolloweesIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+	GetAllFolloweesIDs(userID int64) ([]int64, error)
+	GetAllFollowersIDs(userID int64) ([]int64, error)
 	GetFollowerCount(userID int64) (int64, error)
 	GetFolloweeCount(userID int64) (int64, error)
 	IsFollowing(followerID, followeeID int64) (bool, error)
@@ -77,38 +81,77 @@ func (db *followsDB) UnfollowUser(followeeID, followerID int64) (bool, error) {
 	return affectedRows > 0, nil
 }
 
-func (db *followsDB) GetFollowersIDs(userID int64) ([]int64, error) {
-	rows, err := db.Query(`SELECT follower_id FROM follows WHERE followee_id = $1`, userID)
+// GetFollowersIDs returns IDs of at most `limit` followers of the user, most
+// recent followers first, which come after the `cursor` (nil means the first
+// page). Returned cursor is nil if there are no more followers.
+func (db *followsDB) GetFollowersIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT follower_id, followed_at FROM follows
+			WHERE followee_id = $1 AND ($2::TIMESTAMP IS NULL OR (followed_at, follower_id) < ($2, $3))
+			ORDER BY followed_at DESC, follower_id DESC
+			LIMIT $4`,
+		userID, cursorTime, cursorID, limit+1,
+	)
 	if err != nil {
-		log.WithError(err).Error("GetFollowersIDs query error")
-		return nil, err
+		log.WithFields(log.Fields{
+			"userID": userID,
+			"cursor": cursor,
+			"limit":  limit,
+		}).WithError(err).Error("GetFollowersIDs query error.")
+		return nil, nil, err
 	}
 	defer rows.Close()
 
-	followersIDs := make([]int64, 0)
-	for rows.Next() {
-		var followerID int64
+	followersIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetFollowersIDs rows scan/iteration error.")
+		return nil, nil, err
+	}
 
-		if err = rows.Scan(&followerID); err != nil {
-			log.WithError(err).Error("GetFollowersIDs row scan error.")
-			return nil, err
-		}
+	return followersIDs, nextCursor, nil
+}
 
-		followersIDs = append(followersIDs, followerID)
+// GetFolloweesIDs returns IDs of at most `limit` users followed by the user,
+// most recently followed first, which come after the `cursor` (nil means the
+// first page). Returned cursor is nil if there are no more followees.
+func (db *followsDB) GetFolloweesIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT followee_id, followed_at FROM follows
+			WHERE follower_id = $1 AND ($2::TIMESTAMP IS NULL OR (followed_at, followee_id) < ($2, $3))
+			ORDER BY followed_at DESC, followee_id DESC
+			LIMIT $4`,
+		userID, cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID": userID,
+			"cursor": cursor,
+			"limit":  limit,
+		}).WithError(err).Error("GetFolloweesIDs query error.")
+		return nil, nil, err
 	}
+	defer rows.Close()
 
-	if err = rows.Err(); err != nil {
-		log.WithError(err).Error("GetFollowersIDs rows iteration error.")
-		return nil, err
+	followeesIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetFolloweesIDs rows scan/iteration error.")
+		return nil, nil, err
 	}
 
-	return followersIDs, nil
+	return followeesIDs, nextCursor, nil
 }
 
-func (db *followsDB) GetFolloweesIDs(userID int64) ([]int64, error) {
+// GetAllFolloweesIDs returns IDs of all users followed by the user.
+func (db *followsDB) GetAllFolloweesIDs(userID int64) ([]int64, error) {
 	rows, err := db.Query(`SELECT followee_id FROM follows WHERE follower_id = $1`, userID)
 	if err != nil {
-		log.WithError(err).Error("GetFolloweesIDs query error")
+		log.WithError(err).Error("GetAllFolloweesIDs query error")
 		return nil, err
 	}
 	defer rows.Close()
@@ -119,7 +162,7 @@ func (db *followsDB) GetFolloweesIDs(userID int64) ([]int64, error) {
 
 		err = rows.Scan(&followeeID)
 		if err != nil {
-			log.WithError(err).Error("GetFolloweesIDs row scan error.")
+			log.WithError(err).Error("GetAllFolloweesIDs row scan error.")
 			return nil, err
 		}
 
@@ -127,13 +170,43 @@ func (db *followsDB) GetFolloweesIDs(userID int64) ([]int64, error) {
 	}
 
 	if err = rows.Err(); err != nil {
-		log.WithError(err).Error("GetFolloweesIDs rows iteration error.")
+		log.WithError(err).Error("GetAllFolloweesIDs rows iteration error.")
 		return nil, err
 	}
 
 	return followeesIDs, nil
 }
 
+// GetAllFollowersIDs returns IDs of all users following the user.
+func (db *followsDB) GetAllFollowersIDs(userID int64) ([]int64, error) {
+	rows, err := db.Query(`SELECT follower_id FROM follows WHERE followee_id = $1`, userID)
+	if err != nil {
+		log.WithError(err).Error("GetAllFollowersIDs query error")
+		return nil, err
+	}
+	defer rows.Close()
+
+	followersIDs := make([]int64, 0)
+	for rows.Next() {
+		var followerID int64
+
+		err = rows.Scan(&followerID)
+		if err != nil {
+			log.WithError(err).Error("GetAllFollowersIDs row scan error.")
+			return nil, err
+		}
+
+		followersIDs = append(followersIDs, followerID)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.WithError(err).Error("GetAllFollowersIDs rows iteration error.")
+		return nil, err
+	}
+
+	return followersIDs, nil
+}
+
 func (db *followsDB) GetFollowerCount(userID int64) (int64, error) {
 	var followerCount int64
 
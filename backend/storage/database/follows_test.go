@@ -5,12 +5,14 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
 )
 
 var _ = Describe("Follows", func() {
 	var (
 		conf       *config.Configuration = config.New()
 		db                               = NewPostgresDatabase(conf.Postgres)
+		usersDAO                         = NewUserDAO(db)
 		followsDAO                       = NewFollowsDAO(db)
 	)
 
@@ -40,4 +42,48 @@ var _ = Describe("Follows", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(followed).To(BeFalse())
 	})
+
+	It("should return followers and followees page by page, most recent first", func() {
+		users := make([]*model.PublicUser, 0)
+		for _, name := range []string{"first", "second", "third"} {
+			user, err := usersDAO.InsertUser(&model.NewUserForm{
+				Username: name,
+				Password: "password",
+				Email:    name + "@email.com",
+				Name:     name,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			users = append(users, user)
+		}
+
+		for _, follower := range users[1:] {
+			followed, err := followsDAO.FollowUser(users[0].ID, follower.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(followed).To(BeTrue())
+		}
+
+		followersIDs, cursor, err := followsDAO.GetFollowersIDs(users[0].ID, nil, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(followersIDs).To(Equal([]int64{users[2].ID}))
+		Expect(cursor).NotTo(BeNil())
+
+		followersIDs, cursor, err = followsDAO.GetFollowersIDs(users[0].ID, cursor, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(followersIDs).To(Equal([]int64{users[1].ID}))
+		Expect(cursor).To(BeNil())
+
+		followeesIDs, cursor, err := followsDAO.GetFolloweesIDs(users[1].ID, nil, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(followeesIDs).To(Equal([]int64{users[0].ID}))
+		Expect(cursor).To(BeNil())
+
+		followeesIDs, err = followsDAO.GetAllFolloweesIDs(users[2].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(followeesIDs).To(ConsistOf(users[0].ID))
+
+		followersIDs, err = followsDAO.GetAllFollowersIDs(users[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(followersIDs).To(ConsistOf(users[1].ID, users[2].ID))
+	})
 })
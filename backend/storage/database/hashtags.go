@@ -0,0 +1,98 @@
+package database
+
+import (
+	log "github.com/Sirupsen/logrus"
+	"github.com/lib/pq"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+// HashtagsDAO (Hashtags Data Access Object) is interface which provides operations on TweetHashtags database table.
+type HashtagsDAO interface {
+	InsertTweetHashtags(tweetID int64, hashtags []string) error
+	GetTweetsIDsByHashtag(hashtag string, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+}
+
+type hashtagsDB struct {
+	*Connection
+}
+
+// NewHashtagsDAO creates new struct which implements HashtagsDAO functions.
+func NewHashtagsDAO(conn *Connection) HashtagsDAO {
+	return &hashtagsDB{conn}
+}
+
+func (db *hashtagsDB) InsertTweetHashtags(tweetID int64, hashtags []string) error {
+	return insertTweetHashtags(db, tweetID, hashtags)
+}
+
+// insertTweetHashtags inserts hashtags using given queryer so it can be done
+// inside of the transaction as well.
+func insertTweetHashtags(q queryer, tweetID int64, hashtags []string) error {
+	if len(hashtags) == 0 {
+		return nil
+	}
+
+	// Hashtags are already lowercase (see `entities.HashtagsNames`).
+	_, err := q.Exec(
+		`INSERT INTO tweet_hashtags (tweet_id, hashtag, created_at)
+			SELECT id, unnest($2::VARCHAR[]), created_at FROM tweets WHERE id = $1
+		ON CONFLICT (tweet_id, hashtag) DO NOTHING`,
+		tweetID, pq.Array(hashtags),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID":  tweetID,
+			"hashtags": hashtags,
+		}).WithError(err).Error("InsertTweetHashtags query error.")
+		return err
+	}
+
+	return nil
+}
+
+// deleteTweetHashtags deletes all hashtags of the tweet. It is only used inside of
+// transactions which replace or delete them.
+func deleteTweetHashtags(q queryer, tweetID int64) error {
+	_, err := q.Exec(`DELETE FROM tweet_hashtags WHERE tweet_id = $1`, tweetID)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("DeleteTweetHashtags query error.")
+		return err
+	}
+
+	return nil
+}
+
+// GetTweetsIDsByHashtag returns IDs of at most `limit` tweets with the
+// hashtag, newest first, which come after the `cursor` (nil means the first
+// page). Returned cursor is nil if there are no more tweets.
+func (db *hashtagsDB) GetTweetsIDsByHashtag(hashtag string, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT tweet_id, created_at FROM tweet_hashtags
+			WHERE hashtag = lower($1)
+				AND ($2::TIMESTAMP IS NULL OR (created_at, tweet_id) < ($2, $3))
+			ORDER BY created_at DESC, tweet_id DESC
+			LIMIT $4`,
+		hashtag, cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"hashtag": hashtag,
+			"cursor":  cursor,
+			"limit":   limit,
+		}).WithError(err).Error("GetTweetsIDsByHashtag query error.")
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	tweetsIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetTweetsIDsByHashtag rows scan/iteration error.")
+		return nil, nil, err
+	}
+
+	return tweetsIDs, nextCursor, nil
+}
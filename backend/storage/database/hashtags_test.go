@@ -0,0 +1,132 @@
+package database
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Hashtags", func() {
+	var (
+		conf        *config.Configuration = config.New()
+		db                                = NewPostgresDatabase(conf.Postgres)
+		usersDAO                          = NewUserDAO(db)
+		tweetsDAO                         = NewTweetDAO(db)
+		hashtagsDAO                       = NewHashtagsDAO(db)
+
+		user *model.PublicUser
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		user, err = usersDAO.InsertUser(&model.NewUserForm{
+			Username: "user",
+			Password: "password",
+			Email:    "user@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM tweets;`)
+	})
+
+	It("should return tweets of the hashtag newest first", func() {
+		first, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "#go"})
+		Expect(err).NotTo(HaveOccurred())
+		second, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "#go #db"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(hashtagsDAO.InsertTweetHashtags(first.ID, []string{"go"})).To(Succeed())
+		Expect(hashtagsDAO.InsertTweetHashtags(second.ID, []string{"go", "db"})).To(Succeed())
+
+		tweetsIDs, _, err := hashtagsDAO.GetTweetsIDsByHashtag("go", nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{second.ID, first.ID}))
+
+		tweetsIDs, _, err = hashtagsDAO.GetTweetsIDsByHashtag("db", nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{second.ID}))
+	})
+
+	It("should replace hashtags and mentions of edited tweet", func() {
+		mentionsDAO := NewMentionsDAO(db)
+
+		tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "#go @user"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hashtagsDAO.InsertTweetHashtags(tweet.ID, []string{"go"})).To(Succeed())
+		Expect(mentionsDAO.InsertTweetMentions(tweet.ID, []string{"user"})).To(Succeed())
+
+		_, err = tweetsDAO.EditTweet(tweet.ID, "#db")
+		Expect(err).NotTo(HaveOccurred())
+
+		tweetsIDs, _, err := hashtagsDAO.GetTweetsIDsByHashtag("go", nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(BeEmpty())
+
+		tweetsIDs, _, err = hashtagsDAO.GetTweetsIDsByHashtag("db", nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweet.ID}))
+
+		mentions, err := mentionsDAO.GetTweetMentions(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mentions).To(BeEmpty())
+	})
+
+	It("should remove hashtags of deleted tweet", func() {
+		tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "#go"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hashtagsDAO.InsertTweetHashtags(tweet.ID, []string{"go"})).To(Succeed())
+
+		Expect(tweetsDAO.DeleteTweet(tweet.ID)).To(Succeed())
+
+		tweetsIDs, _, err := hashtagsDAO.GetTweetsIDsByHashtag("go", nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(BeEmpty())
+	})
+
+	It("should bring back hashtags of restored tweet", func() {
+		tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "#go"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsDAO.DeleteTweet(tweet.ID)).To(Succeed())
+
+		restored, err := tweetsDAO.RestoreTweet(tweet.ID, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored).To(BeTrue())
+
+		tweetsIDs, _, err := hashtagsDAO.GetTweetsIDsByHashtag("GO", nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweet.ID}))
+	})
+
+	It("should return tweets of the hashtag page by page", func() {
+		tweetsIDs := make([]int64, 0)
+		for i := 0; i < 3; i++ {
+			tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "#go"})
+			Expect(err).NotTo(HaveOccurred())
+
+			tweetsIDs = append([]int64{tweet.ID}, tweetsIDs...)
+		}
+
+		firstPage, cursor, err := hashtagsDAO.GetTweetsIDsByHashtag("go", nil, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(firstPage).To(Equal(tweetsIDs[:2]))
+		Expect(cursor).NotTo(BeNil())
+
+		secondPage, cursor, err := hashtagsDAO.GetTweetsIDsByHashtag("go", cursor, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secondPage).To(Equal(tweetsIDs[2:]))
+		Expect(cursor).To(BeNil())
+	})
+
+	It("should not fail when there are no hashtags", func() {
+		Expect(hashtagsDAO.InsertTweetHashtags(1, []string{})).To(Succeed())
+	})
+})
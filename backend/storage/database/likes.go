@@ -2,6 +2,8 @@ package database
 
 import (
 	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/model"
 )
 
 // LikesDAO (Likes Data Access Object) is interface which provides operations on Likes database table.
@@ -10,6 +12,8 @@ type LikesDAO interface {
 	UnlikeTweet(tweetID, userID int64) (bool, error)
 	GetLikeCount(tweetID int64) (int64, error)
 	IsLiked(tweetID, userID int64) (bool, error)
+	GetLikersIDs(tweetID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+	GetLikedTweetsIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
 }
 
 type likesDB struct {
@@ -91,3 +95,71 @@ func (db *likesDB) IsLiked(tweetID, userID int64) (bool, error) {
 
 	return isLiked, nil
 }
+
+// GetLikersIDs returns IDs of at most `limit` users who liked the tweet, most
+// recent likes first, which come after the `cursor` (nil means the first page).
+// Returned cursor is nil if there are no more likes.
+func (db *likesDB) GetLikersIDs(tweetID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT user_id, liked_at FROM likes
+			WHERE tweet_id = $1 AND ($2::TIMESTAMP IS NULL OR (liked_at, user_id) < ($2, $3))
+			ORDER BY liked_at DESC, user_id DESC
+			LIMIT $4`,
+		tweetID, cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"cursor":  cursor,
+			"limit":   limit,
+		}).WithError(err).Error("GetLikersIDs query error.")
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	usersIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetLikersIDs rows scan/iteration error.")
+		return nil, nil, err
+	}
+
+	return usersIDs, nextCursor, nil
+}
+
+// GetLikedTweetsIDs returns IDs of at most `limit` tweets liked by the user,
+// most recently liked first, which come after the `cursor` (nil means the
+// first page). Returned cursor is nil if there are no more likes.
+func (db *likesDB) GetLikedTweetsIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT likes.tweet_id, likes.liked_at FROM likes
+			JOIN tweets ON tweets.id = likes.tweet_id
+			WHERE likes.user_id = $1 AND tweets.deleted_at IS NULL
+				AND ($2::TIMESTAMP IS NULL OR (likes.liked_at, likes.tweet_id) < ($2, $3))
+			ORDER BY likes.liked_at DESC, likes.tweet_id DESC
+			LIMIT $4`,
+		userID, cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID": userID,
+			"cursor": cursor,
+			"limit":  limit,
+		}).WithError(err).Error("GetLikedTweetsIDs query error.")
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	tweetsIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetLikedTweetsIDs rows scan/iteration error.")
+		return nil, nil, err
+	}
+
+	return tweetsIDs, nextCursor, nil
+}
@@ -5,22 +5,84 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
 )
 
 var _ = Describe("Likes", func() {
 	var (
-		conf *config.Configuration = config.New()
-		db                         = NewPostgresDatabase(conf.Postgres)
+		conf      *config.Configuration = config.New()
+		db                              = NewPostgresDatabase(conf.Postgres)
+		usersDAO                        = NewUserDAO(db)
+		tweetsDAO                       = NewTweetDAO(db)
+		likesDAO                        = NewLikesDAO(db)
+
+		users  []*model.PublicUser
+		tweets []*model.Tweet
 	)
 
-	BeforeEach(func() {})
+	BeforeEach(func() {
+		users = make([]*model.PublicUser, 0)
+		for _, name := range []string{"first", "second", "third"} {
+			user, err := usersDAO.InsertUser(&model.NewUserForm{
+				Username: name,
+				Password: "password",
+				Email:    name + "@email.com",
+				Name:     name,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			users = append(users, user)
+		}
+
+		tweets = make([]*model.Tweet, 0)
+		for i := 0; i < 3; i++ {
+			tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: users[0].ID, Content: "tweet"})
+			Expect(err).NotTo(HaveOccurred())
+
+			tweets = append(tweets, tweet)
+		}
+	})
 
 	AfterEach(func() {
 		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
-		db.Exec(`DELETE FROM users; DELETE FROM likes;`)
+		db.Exec(`DELETE FROM users; DELETE FROM tweets; DELETE FROM likes;`)
+	})
+
+	It("should return likers page by page, most recent first", func() {
+		for _, user := range users {
+			liked, err := likesDAO.LikeTweet(tweets[0].ID, user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(liked).To(BeTrue())
+		}
+
+		usersIDs, cursor, err := likesDAO.GetLikersIDs(tweets[0].ID, nil, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(usersIDs).To(Equal([]int64{users[2].ID, users[1].ID}))
+		Expect(cursor).NotTo(BeNil())
+
+		usersIDs, cursor, err = likesDAO.GetLikersIDs(tweets[0].ID, cursor, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(usersIDs).To(Equal([]int64{users[0].ID}))
+		Expect(cursor).To(BeNil())
 	})
 
-	It("should do something", func() {
-		Expect(true).To(BeTrue())
+	It("should return liked tweets ordered by time of liking", func() {
+		for _, i := range []int{1, 0, 2} {
+			liked, err := likesDAO.LikeTweet(tweets[i].ID, users[1].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(liked).To(BeTrue())
+		}
+
+		tweetsIDs, cursor, err := likesDAO.GetLikedTweetsIDs(users[1].ID, nil, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweets[2].ID, tweets[0].ID, tweets[1].ID}))
+		Expect(cursor).To(BeNil())
+
+		err = tweetsDAO.DeleteTweet(tweets[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		tweetsIDs, _, err = likesDAO.GetLikedTweetsIDs(users[1].ID, nil, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweets[2].ID, tweets[1].ID}))
 	})
 })
@@ -0,0 +1,303 @@
+package database
+
+import (
+	"database/sql"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+// ListsDAO (Lists Data Access Object) is interface which provides operations on Lists, ListMembers and ListSubscriptions database tables.
+type ListsDAO interface {
+	InsertList(newList *model.NewList) (*model.List, error)
+	GetListByID(listID int64) (*model.List, error)
+	DeleteList(listID int64) error
+	GetListsByOwnerID(ownerID int64) ([]*model.List, error)
+	GetSubscribedLists(userID int64) ([]*model.List, error)
+	AddListMember(listID, userID int64) (bool, error)
+	RemoveListMember(listID, userID int64) (bool, error)
+	GetListMembersIDs(listID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+	GetAllListMembersIDs(listID int64) ([]int64, error)
+	SubscribeList(listID, userID int64) (bool, error)
+	UnsubscribeList(listID, userID int64) (bool, error)
+	IsSubscribed(listID, userID int64) (bool, error)
+}
+
+type listsDB struct {
+	*Connection
+}
+
+// NewListsDAO creates new struct which implements ListsDAO functions.
+func NewListsDAO(conn *Connection) ListsDAO {
+	return &listsDB{conn}
+}
+
+func (db *listsDB) InsertList(newList *model.NewList) (*model.List, error) {
+	row := db.QueryRow(
+		`INSERT INTO lists (owner_id, name, private) VALUES ($1, $2, $3)
+			RETURNING id, owner_id, name, private, created_at, 0, 0`,
+		newList.OwnerID, newList.Name, newList.Private,
+	)
+
+	list, err := readList(row)
+	if err != nil {
+		log.WithField("newList", *newList).WithError(err).Error("InsertList query error.")
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (db *listsDB) GetListByID(listID int64) (*model.List, error) {
+	row := db.QueryRow(
+		`SELECT id, owner_id, name, private, created_at,
+				(SELECT count(*) FROM list_members WHERE list_id = lists.id),
+				(SELECT count(*) FROM list_subscriptions WHERE list_id = lists.id)
+			FROM lists WHERE id = $1`,
+		listID,
+	)
+
+	list, err := readList(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.NoResultsError
+	} else if err != nil {
+		log.WithField("listID", listID).WithError(err).Error("GetListByID query error.")
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (db *listsDB) DeleteList(listID int64) error {
+	result, err := db.Exec(`DELETE FROM lists WHERE id = $1`, listID)
+	if err != nil {
+		log.WithField("listID", listID).WithError(err).Error("DeleteList query error.")
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affectedRows == 0 {
+		return errors.NoResultsError
+	}
+
+	return nil
+}
+
+// GetListsByOwnerID returns all lists of the owner, newest first.
+func (db *listsDB) GetListsByOwnerID(ownerID int64) ([]*model.List, error) {
+	rows, err := db.Query(
+		`SELECT id, owner_id, name, private, created_at,
+				(SELECT count(*) FROM list_members WHERE list_id = lists.id),
+				(SELECT count(*) FROM list_subscriptions WHERE list_id = lists.id)
+			FROM lists WHERE owner_id = $1
+			ORDER BY created_at DESC, id DESC`,
+		ownerID,
+	)
+	if err != nil {
+		log.WithField("ownerID", ownerID).WithError(err).Error("GetListsByOwnerID query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists, err := readMultipleLists(rows)
+	if err != nil {
+		log.WithError(err).Error("GetListsByOwnerID rows scan/iteration error.")
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+// GetSubscribedLists returns all lists subscribed by the user, most recently
+// subscribed first.
+func (db *listsDB) GetSubscribedLists(userID int64) ([]*model.List, error) {
+	rows, err := db.Query(
+		`SELECT lists.id, lists.owner_id, lists.name, lists.private, lists.created_at,
+				(SELECT count(*) FROM list_members WHERE list_id = lists.id),
+				(SELECT count(*) FROM list_subscriptions WHERE list_id = lists.id)
+			FROM lists JOIN list_subscriptions ON list_subscriptions.list_id = lists.id
+			WHERE list_subscriptions.user_id = $1
+			ORDER BY list_subscriptions.subscribed_at DESC, lists.id DESC`,
+		userID,
+	)
+	if err != nil {
+		log.WithField("userID", userID).WithError(err).Error("GetSubscribedLists query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists, err := readMultipleLists(rows)
+	if err != nil {
+		log.WithError(err).Error("GetSubscribedLists rows scan/iteration error.")
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (db *listsDB) AddListMember(listID, userID int64) (bool, error) {
+	result, err := db.Exec(
+		`INSERT INTO list_members (list_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (list_id, user_id) DO NOTHING`,
+		listID, userID,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"listID": listID,
+			"userID": userID,
+		}).WithError(err).Error("AddListMember query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *listsDB) RemoveListMember(listID, userID int64) (bool, error) {
+	result, err := db.Exec(`DELETE FROM list_members WHERE list_id = $1 AND user_id = $2`, listID, userID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"listID": listID,
+			"userID": userID,
+		}).WithError(err).Error("RemoveListMember query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+// GetListMembersIDs returns IDs of at most `limit` members of the list, most
+// recently added first, which come after the `cursor` (nil means the first
+// page). Returned cursor is nil if there are no more members.
+func (db *listsDB) GetListMembersIDs(listID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT user_id, added_at FROM list_members
+			WHERE list_id = $1 AND ($2::TIMESTAMP IS NULL OR (added_at, user_id) < ($2, $3))
+			ORDER BY added_at DESC, user_id DESC
+			LIMIT $4`,
+		listID, cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"listID": listID,
+			"cursor": cursor,
+			"limit":  limit,
+		}).WithError(err).Error("GetListMembersIDs query error.")
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	membersIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetListMembersIDs rows scan/iteration error.")
+		return nil, nil, err
+	}
+
+	return membersIDs, nextCursor, nil
+}
+
+// GetAllListMembersIDs returns IDs of all members of the list.
+func (db *listsDB) GetAllListMembersIDs(listID int64) ([]int64, error) {
+	rows, err := db.Query(`SELECT user_id FROM list_members WHERE list_id = $1`, listID)
+	if err != nil {
+		log.WithField("listID", listID).WithError(err).Error("GetAllListMembersIDs query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	membersIDs := make([]int64, 0)
+	for rows.Next() {
+		var memberID int64
+
+		err = rows.Scan(&memberID)
+		if err != nil {
+			log.WithError(err).Error("GetAllListMembersIDs row scan error.")
+			return nil, err
+		}
+
+		membersIDs = append(membersIDs, memberID)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.WithError(err).Error("GetAllListMembersIDs rows iteration error.")
+		return nil, err
+	}
+
+	return membersIDs, nil
+}
+
+func (db *listsDB) SubscribeList(listID, userID int64) (bool, error) {
+	result, err := db.Exec(
+		`INSERT INTO list_subscriptions (list_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (list_id, user_id) DO NOTHING`,
+		listID, userID,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"listID": listID,
+			"userID": userID,
+		}).WithError(err).Error("SubscribeList query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *listsDB) UnsubscribeList(listID, userID int64) (bool, error) {
+	result, err := db.Exec(`DELETE FROM list_subscriptions WHERE list_id = $1 AND user_id = $2`, listID, userID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"listID": listID,
+			"userID": userID,
+		}).WithError(err).Error("UnsubscribeList query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *listsDB) IsSubscribed(listID, userID int64) (bool, error) {
+	var isSubscribed bool
+
+	err := db.QueryRow(
+		`SELECT exists (SELECT TRUE FROM list_subscriptions WHERE list_id = $1 AND user_id = $2)`,
+		listID, userID,
+	).Scan(&isSubscribed)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"listID": listID,
+			"userID": userID,
+		}).WithError(err).Error("IsSubscribed query error.")
+		return false, err
+	}
+
+	return isSubscribed, nil
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+var _ = Describe("Lists", func() {
+	var (
+		conf     *config.Configuration = config.New()
+		db                             = NewPostgresDatabase(conf.Postgres)
+		usersDAO                       = NewUserDAO(db)
+		listsDAO                       = NewListsDAO(db)
+
+		owner   *model.PublicUser
+		members []*model.PublicUser
+		list    *model.List
+	)
+
+	insertUser := func(name string) *model.PublicUser {
+		user, err := usersDAO.InsertUser(&model.NewUserForm{
+			Username: name,
+			Password: "password",
+			Email:    name + "@email.com",
+			Name:     name,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return user
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		owner = insertUser("owner")
+		list, err = listsDAO.InsertList(&model.NewList{OwnerID: owner.ID, Name: "list"})
+		Expect(err).NotTo(HaveOccurred())
+
+		members = make([]*model.PublicUser, 0)
+		for _, name := range []string{"first", "second", "third"} {
+			member := insertUser(name)
+
+			added, err := listsDAO.AddListMember(list.ID, member.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeTrue())
+
+			members = append(members, member)
+		}
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM lists;`)
+	})
+
+	It("should count members and subscribers", func() {
+		subscribed, err := listsDAO.SubscribeList(list.ID, members[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subscribed).To(BeTrue())
+
+		subscribed, err = listsDAO.SubscribeList(list.ID, members[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subscribed).To(BeFalse())
+
+		dbList, err := listsDAO.GetListByID(list.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dbList.Owner.ID).To(Equal(owner.ID))
+		Expect(dbList.Name).To(Equal("list"))
+		Expect(dbList.MemberCount).To(Equal(int64(3)))
+		Expect(dbList.SubscriberCount).To(Equal(int64(1)))
+
+		subscribedLists, err := listsDAO.GetSubscribedLists(members[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subscribedLists).To(Equal([]*model.List{dbList}))
+	})
+
+	It("should not add member twice", func() {
+		added, err := listsDAO.AddListMember(list.ID, members[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(BeFalse())
+
+		removed, err := listsDAO.RemoveListMember(list.ID, members[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(BeTrue())
+
+		membersIDs, err := listsDAO.GetAllListMembersIDs(list.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(membersIDs).To(ConsistOf(members[1].ID, members[2].ID))
+	})
+
+	It("should return all members page by page", func() {
+		var (
+			cursor     *model.Cursor
+			membersIDs = make([]int64, 0)
+		)
+
+		for {
+			pageIDs, nextCursor, err := listsDAO.GetListMembersIDs(list.ID, cursor, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(pageIDs)).To(BeNumerically("<=", 2))
+
+			membersIDs = append(membersIDs, pageIDs...)
+			if nextCursor == nil {
+				break
+			}
+
+			cursor = nextCursor
+		}
+
+		Expect(membersIDs).To(Equal([]int64{members[2].ID, members[1].ID, members[0].ID}))
+	})
+
+	It("should delete list together with its members", func() {
+		Expect(listsDAO.DeleteList(list.ID)).To(Succeed())
+		Expect(listsDAO.DeleteList(list.ID)).To(Equal(errors.NoResultsError))
+
+		_, err := listsDAO.GetListByID(list.ID)
+		Expect(err).To(Equal(errors.NoResultsError))
+
+		membersIDs, err := listsDAO.GetAllListMembersIDs(list.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(membersIDs).To(BeEmpty())
+
+		lists, err := listsDAO.GetListsByOwnerID(owner.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lists).To(BeEmpty())
+	})
+})
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"database/sql"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/lib/pq"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+// MediaDAO (Media Data Access Object) is interface which provides operations on Media database table.
+type MediaDAO interface {
+	InsertMedia(media *model.Media) (*model.Media, error)
+	GetMediaByIDs(mediaIDs []int64) ([]*model.Media, error)
+	GetMediaByKey(key string) (*model.Media, error)
+	AttachMedia(tweetID, ownerID int64, mediaIDs []int64) error
+	GetTweetMedia(tweetID int64) ([]*model.Media, error)
+}
+
+type mediaDB struct {
+	*Connection
+}
+
+// NewMediaDAO creates new struct which implements MediaDAO functions.
+func NewMediaDAO(conn *Connection) MediaDAO {
+	return &mediaDB{conn}
+}
+
+func (db *mediaDB) InsertMedia(media *model.Media) (*model.Media, error) {
+	row := db.QueryRow(
+		`INSERT INTO media (owner_id, blob_key, mime_type, width, height, size)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, owner_id, tweet_id, blob_key, mime_type, width, height, size`,
+		media.OwnerID, media.Key, media.MimeType, media.Width, media.Height, media.Size,
+	)
+
+	insertedMedia, err := readMedia(row)
+	if err != nil {
+		log.WithField("media", *media).WithError(err).Error("InsertMedia query error.")
+		return nil, err
+	}
+
+	return insertedMedia, nil
+}
+
+func (db *mediaDB) GetMediaByIDs(mediaIDs []int64) ([]*model.Media, error) {
+	rows, err := db.Query(
+		`SELECT id, owner_id, tweet_id, blob_key, mime_type, width, height, size FROM media
+			WHERE id = ANY($1)`,
+		pq.Array(mediaIDs),
+	)
+	if err != nil {
+		log.WithField("mediaIDs", mediaIDs).WithError(err).Error("GetMediaByIDs query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	media, err := readMultipleMedia(rows)
+	if err != nil {
+		log.WithError(err).Error("GetMediaByIDs rows scan/iteration error.")
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (db *mediaDB) GetMediaByKey(key string) (*model.Media, error) {
+	row := db.QueryRow(
+		`SELECT id, owner_id, tweet_id, blob_key, mime_type, width, height, size FROM media
+			WHERE blob_key = $1`,
+		key,
+	)
+
+	media, err := readMedia(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.NoResultsError
+	} else if err != nil {
+		log.WithField("key", key).WithError(err).Error("GetMediaByKey query error.")
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (db *mediaDB) AttachMedia(tweetID, ownerID int64, mediaIDs []int64) error {
+	return attachMedia(db, tweetID, ownerID, mediaIDs)
+}
+
+// attachMedia attaches media using given queryer so it can be done inside of
+// the transaction as well.
+func attachMedia(q queryer, tweetID, ownerID int64, mediaIDs []int64) error {
+	// media is kept in the same order as IDs were given
+	_, err := q.Exec(
+		`UPDATE media SET tweet_id = $1, position = array_position($3, id)
+			WHERE id = ANY($3) AND owner_id = $2 AND tweet_id IS NULL`,
+		tweetID, ownerID, pq.Array(mediaIDs),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID":  tweetID,
+			"ownerID":  ownerID,
+			"mediaIDs": mediaIDs,
+		}).WithError(err).Error("AttachMedia query error.")
+		return err
+	}
+
+	return nil
+}
+
+func (db *mediaDB) GetTweetMedia(tweetID int64) ([]*model.Media, error) {
+	rows, err := db.Query(
+		`SELECT id, owner_id, tweet_id, blob_key, mime_type, width, height, size FROM media
+			WHERE tweet_id = $1 ORDER BY position`,
+		tweetID,
+	)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetTweetMedia query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	media, err := readMultipleMedia(rows)
+	if err != nil {
+		log.WithError(err).Error("GetTweetMedia rows scan/iteration error.")
+		return nil, err
+	}
+
+	return media, nil
+}
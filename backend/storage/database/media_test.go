@@ -0,0 +1,102 @@
+package database
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+var _ = Describe("Media", func() {
+	var (
+		conf      *config.Configuration = config.New()
+		db                              = NewPostgresDatabase(conf.Postgres)
+		usersDAO                        = NewUserDAO(db)
+		tweetsDAO                       = NewTweetDAO(db)
+		mediaDAO                        = NewMediaDAO(db)
+
+		user  *model.PublicUser
+		tweet *model.Tweet
+	)
+
+	insertMedia := func(key string) *model.Media {
+		media, err := mediaDAO.InsertMedia(&model.Media{
+			OwnerID:  user.ID,
+			Key:      key,
+			MimeType: "image/png",
+			Width:    10,
+			Height:   20,
+			Size:     100,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		return media
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		user, err = usersDAO.InsertUser(&model.NewUserForm{
+			Username: "user",
+			Password: "password",
+			Email:    "user@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err = tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "tweet"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM tweets; DELETE FROM media;`)
+	})
+
+	It("should insert media which is not attached", func() {
+		media := insertMedia("key")
+
+		Expect(media.OwnerID).To(Equal(user.ID))
+		Expect(media.TweetID).To(BeZero())
+		Expect(media.Key).To(Equal("key"))
+
+		allMedia, err := mediaDAO.GetMediaByIDs([]int64{media.ID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allMedia).To(Equal([]*model.Media{media}))
+
+		keyMedia, err := mediaDAO.GetMediaByKey("key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(keyMedia).To(Equal(media))
+
+		_, err = mediaDAO.GetMediaByKey("unknown")
+		Expect(err).To(Equal(errors.NoResultsError))
+	})
+
+	It("should attach media in given order", func() {
+		first := insertMedia("first")
+		second := insertMedia("second")
+
+		err := mediaDAO.AttachMedia(tweet.ID, user.ID, []int64{second.ID, first.ID})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweetMedia, err := mediaDAO.GetTweetMedia(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetMedia).To(HaveLen(2))
+		Expect(tweetMedia[0].ID).To(Equal(second.ID))
+		Expect(tweetMedia[1].ID).To(Equal(first.ID))
+		Expect(tweetMedia[0].TweetID).To(Equal(tweet.ID))
+	})
+
+	It("should not attach media of other user", func() {
+		media := insertMedia("key")
+
+		err := mediaDAO.AttachMedia(tweet.ID, user.ID+1, []int64{media.ID})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweetMedia, err := mediaDAO.GetTweetMedia(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetMedia).To(BeEmpty())
+	})
+})
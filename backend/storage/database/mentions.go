@@ -0,0 +1,134 @@
+package database
+
+import (
+	log "github.com/Sirupsen/logrus"
+	"github.com/lib/pq"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+// MentionsDAO (Mentions Data Access Object) is interface which provides operations on Mentions database table.
+type MentionsDAO interface {
+	InsertTweetMentions(tweetID int64, usernames []string) error
+	GetTweetMentions(tweetID int64) ([]*model.Mention, error)
+	GetMentioningTweetsIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+}
+
+type mentionsDB struct {
+	*Connection
+}
+
+// NewMentionsDAO creates new struct which implements MentionsDAO functions.
+func NewMentionsDAO(conn *Connection) MentionsDAO {
+	return &mentionsDB{conn}
+}
+
+func (db *mentionsDB) InsertTweetMentions(tweetID int64, usernames []string) error {
+	return insertTweetMentions(db, tweetID, usernames)
+}
+
+// insertTweetMentions inserts mentions using given queryer so it can be done
+// inside of the transaction as well.
+func insertTweetMentions(q queryer, tweetID int64, usernames []string) error {
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	// usernames which do not belong to any user are simply skipped
+	_, err := q.Exec(
+		`INSERT INTO mentions (tweet_id, user_id)
+			SELECT $1, id FROM users WHERE username = ANY($2)
+		ON CONFLICT (tweet_id, user_id) DO NOTHING`,
+		tweetID, pq.Array(usernames),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID":   tweetID,
+			"usernames": usernames,
+		}).WithError(err).Error("InsertTweetMentions query error.")
+		return err
+	}
+
+	return nil
+}
+
+// deleteTweetMentions deletes all mentions of the tweet. It is only used inside of
+// transactions which replace them.
+func deleteTweetMentions(q queryer, tweetID int64) error {
+	_, err := q.Exec(`DELETE FROM mentions WHERE tweet_id = $1`, tweetID)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("DeleteTweetMentions query error.")
+		return err
+	}
+
+	return nil
+}
+
+func (db *mentionsDB) GetTweetMentions(tweetID int64) ([]*model.Mention, error) {
+	rows, err := db.Query(
+		`SELECT users.id, users.username FROM mentions
+			JOIN users ON users.id = mentions.user_id
+			WHERE mentions.tweet_id = $1
+			ORDER BY users.username`,
+		tweetID,
+	)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetTweetMentions query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	mentions := make([]*model.Mention, 0)
+	for rows.Next() {
+		var mention model.Mention
+
+		if err = rows.Scan(&mention.UserID, &mention.Username); err != nil {
+			log.WithError(err).Error("GetTweetMentions row scan error.")
+			return nil, err
+		}
+
+		mentions = append(mentions, &mention)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.WithError(err).Error("GetTweetMentions rows iteration error.")
+		return nil, err
+	}
+
+	return mentions, nil
+}
+
+// GetMentioningTweetsIDs returns IDs of at most `limit` tweets which mention
+// the user, newest first, which come after the `cursor` (nil means the first
+// page). Returned cursor is nil if there are no more tweets.
+func (db *mentionsDB) GetMentioningTweetsIDs(userID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT tweets.id, tweets.created_at FROM tweets
+			JOIN mentions ON mentions.tweet_id = tweets.id
+			WHERE mentions.user_id = $1 AND tweets.deleted_at IS NULL
+				AND ($2::TIMESTAMP IS NULL OR (tweets.created_at, tweets.id) < ($2, $3))
+			ORDER BY tweets.created_at DESC, tweets.id DESC
+			LIMIT $4`,
+		userID, cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID": userID,
+			"cursor": cursor,
+			"limit":  limit,
+		}).WithError(err).Error("GetMentioningTweetsIDs query error.")
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	tweetsIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetMentioningTweetsIDs rows scan/iteration error.")
+		return nil, nil, err
+	}
+
+	return tweetsIDs, nextCursor, nil
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Mentions", func() {
+	var (
+		conf        *config.Configuration = config.New()
+		db                                = NewPostgresDatabase(conf.Postgres)
+		usersDAO                          = NewUserDAO(db)
+		tweetsDAO                         = NewTweetDAO(db)
+		mentionsDAO                       = NewMentionsDAO(db)
+
+		user  *model.PublicUser
+		tweet *model.Tweet
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		user, err = usersDAO.InsertUser(&model.NewUserForm{
+			Username: "user",
+			Password: "password",
+			Email:    "user@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err = tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "@user @unknown"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM tweets; DELETE FROM mentions;`)
+	})
+
+	It("should insert mentions only of existing users", func() {
+		err := mentionsDAO.InsertTweetMentions(tweet.ID, []string{"user", "unknown"})
+		Expect(err).NotTo(HaveOccurred())
+
+		mentions, err := mentionsDAO.GetTweetMentions(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mentions).To(Equal([]*model.Mention{{UserID: user.ID, Username: "user"}}))
+
+		tweetsIDs, _, err := mentionsDAO.GetMentioningTweetsIDs(user.ID, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweet.ID}))
+	})
+
+	It("should return empty lists when there are no mentions", func() {
+		Expect(mentionsDAO.InsertTweetMentions(tweet.ID, []string{})).To(Succeed())
+
+		mentions, err := mentionsDAO.GetTweetMentions(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mentions).To(BeEmpty())
+
+		tweetsIDs, _, err := mentionsDAO.GetMentioningTweetsIDs(user.ID, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(BeEmpty())
+	})
+})
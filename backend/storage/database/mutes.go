@@ -0,0 +1,169 @@
+package database
+
+import (
+	log "github.com/Sirupsen/logrus"
+)
+
+// MutesDAO (Mutes Data Access Object) is interface which provides operations on Mutes and MutedWords database tables.
+type MutesDAO interface {
+	MuteUser(userID, mutedUserID int64) (bool, error)
+	UnmuteUser(userID, mutedUserID int64) (bool, error)
+	GetAllMutedUsersIDs(userID int64) ([]int64, error)
+	MuteWord(userID int64, word string) (bool, error)
+	UnmuteWord(userID int64, word string) (bool, error)
+	GetAllMutedWords(userID int64) ([]string, error)
+}
+
+type mutesDB struct {
+	*Connection
+}
+
+// NewMutesDAO creates new struct which implements MutesDAO functions.
+func NewMutesDAO(conn *Connection) MutesDAO {
+	return &mutesDB{conn}
+}
+
+func (db *mutesDB) MuteUser(userID, mutedUserID int64) (bool, error) {
+	result, err := db.Exec(
+		`INSERT INTO mutes (user_id, muted_user_id) VALUES ($1, $2)
+			ON CONFLICT (user_id, muted_user_id) DO NOTHING`,
+		userID, mutedUserID,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID":      userID,
+			"mutedUserID": mutedUserID,
+		}).WithError(err).Error("MuteUser query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *mutesDB) UnmuteUser(userID, mutedUserID int64) (bool, error) {
+	result, err := db.Exec(`DELETE FROM mutes WHERE user_id = $1 AND muted_user_id = $2`, userID, mutedUserID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID":      userID,
+			"mutedUserID": mutedUserID,
+		}).WithError(err).Error("UnmuteUser query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+// GetAllMutedUsersIDs returns IDs of all users muted by the user, most
+// recently muted first.
+func (db *mutesDB) GetAllMutedUsersIDs(userID int64) ([]int64, error) {
+	rows, err := db.Query(
+		`SELECT muted_user_id FROM mutes WHERE user_id = $1
+			ORDER BY muted_at DESC, muted_user_id DESC`,
+		userID,
+	)
+	if err != nil {
+		log.WithField("userID", userID).WithError(err).Error("GetAllMutedUsersIDs query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	mutedUsersIDs := make([]int64, 0)
+	for rows.Next() {
+		var mutedUserID int64
+
+		err = rows.Scan(&mutedUserID)
+		if err != nil {
+			log.WithError(err).Error("GetAllMutedUsersIDs row scan error.")
+			return nil, err
+		}
+
+		mutedUsersIDs = append(mutedUsersIDs, mutedUserID)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.WithError(err).Error("GetAllMutedUsersIDs rows iteration error.")
+		return nil, err
+	}
+
+	return mutedUsersIDs, nil
+}
+
+func (db *mutesDB) MuteWord(userID int64, word string) (bool, error) {
+	result, err := db.Exec(
+		`INSERT INTO muted_words (user_id, word) VALUES ($1, $2)
+			ON CONFLICT (user_id, word) DO NOTHING`,
+		userID, word,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID": userID,
+			"word":   word,
+		}).WithError(err).Error("MuteWord query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *mutesDB) UnmuteWord(userID int64, word string) (bool, error) {
+	result, err := db.Exec(`DELETE FROM muted_words WHERE user_id = $1 AND word = $2`, userID, word)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID": userID,
+			"word":   word,
+		}).WithError(err).Error("UnmuteWord query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+// GetAllMutedWords returns all words muted by the user in alphabetical order.
+func (db *mutesDB) GetAllMutedWords(userID int64) ([]string, error) {
+	rows, err := db.Query(`SELECT word FROM muted_words WHERE user_id = $1 ORDER BY word`, userID)
+	if err != nil {
+		log.WithField("userID", userID).WithError(err).Error("GetAllMutedWords query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	words := make([]string, 0)
+	for rows.Next() {
+		var word string
+
+		err = rows.Scan(&word)
+		if err != nil {
+			log.WithError(err).Error("GetAllMutedWords row scan error.")
+			return nil, err
+		}
+
+		words = append(words, word)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.WithError(err).Error("GetAllMutedWords rows iteration error.")
+		return nil, err
+	}
+
+	return words, nil
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Mutes", func() {
+	var (
+		conf     *config.Configuration = config.New()
+		db                             = NewPostgresDatabase(conf.Postgres)
+		usersDAO                       = NewUserDAO(db)
+		mutesDAO                       = NewMutesDAO(db)
+
+		users []*model.PublicUser
+	)
+
+	BeforeEach(func() {
+		users = make([]*model.PublicUser, 0)
+		for _, name := range []string{"first", "second", "third"} {
+			user, err := usersDAO.InsertUser(&model.NewUserForm{
+				Username: name,
+				Password: "password",
+				Email:    name + "@email.com",
+				Name:     name,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			users = append(users, user)
+		}
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users;`)
+	})
+
+	It("should mute users only once", func() {
+		for _, user := range users[1:] {
+			muted, err := mutesDAO.MuteUser(users[0].ID, user.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(muted).To(BeTrue())
+		}
+
+		muted, err := mutesDAO.MuteUser(users[0].ID, users[1].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(muted).To(BeFalse())
+
+		mutedUsersIDs, err := mutesDAO.GetAllMutedUsersIDs(users[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mutedUsersIDs).To(ConsistOf(users[1].ID, users[2].ID))
+
+		mutedUsersIDs, err = mutesDAO.GetAllMutedUsersIDs(users[1].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mutedUsersIDs).To(BeEmpty())
+	})
+
+	It("should unmute user", func() {
+		_, err := mutesDAO.MuteUser(users[0].ID, users[1].ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		unmuted, err := mutesDAO.UnmuteUser(users[0].ID, users[1].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unmuted).To(BeTrue())
+
+		unmuted, err = mutesDAO.UnmuteUser(users[0].ID, users[1].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unmuted).To(BeFalse())
+
+		mutedUsersIDs, err := mutesDAO.GetAllMutedUsersIDs(users[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mutedUsersIDs).To(BeEmpty())
+	})
+
+	It("should mute and unmute words", func() {
+		for _, word := range []string{"spoiler", "#golang"} {
+			muted, err := mutesDAO.MuteWord(users[0].ID, word)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(muted).To(BeTrue())
+		}
+
+		muted, err := mutesDAO.MuteWord(users[0].ID, "spoiler")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(muted).To(BeFalse())
+
+		words, err := mutesDAO.GetAllMutedWords(users[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(words).To(Equal([]string{"#golang", "spoiler"}))
+
+		unmuted, err := mutesDAO.UnmuteWord(users[0].ID, "spoiler")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unmuted).To(BeTrue())
+
+		words, err = mutesDAO.GetAllMutedWords(users[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(words).To(Equal([]string{"#golang"}))
+	})
+})
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"database/sql"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/lib/pq"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+// PollsDAO (Polls Data Access Object) is interface which provides operations on Polls, PollOptions and PollVotes database tables.
+type PollsDAO interface {
+	InsertPoll(tweetID int64, poll *model.NewPoll) error
+	GetPoll(tweetID int64) (*model.Poll, error)
+	GetPollVoteCounts(tweetID int64) ([]int64, error)
+	VotePoll(tweetID, userID int64, option int) (bool, error)
+	GetPollVote(tweetID, userID int64) (int, error)
+}
+
+type pollsDB struct {
+	*Connection
+}
+
+// NewPollsDAO creates new struct which implements PollsDAO functions.
+func NewPollsDAO(conn *Connection) PollsDAO {
+	return &pollsDB{conn}
+}
+
+func (db *pollsDB) InsertPoll(tweetID int64, poll *model.NewPoll) error {
+	return insertPoll(db, tweetID, poll)
+}
+
+// insertPoll inserts the poll using given queryer so it can be done inside of
+// the transaction as well.
+func insertPoll(q queryer, tweetID int64, poll *model.NewPoll) error {
+	// options are numbered from 0 in the order they were given
+	_, err := q.Exec(
+		`WITH poll AS (
+			INSERT INTO polls (tweet_id, closes_at) VALUES ($1, $2)
+				RETURNING tweet_id
+		)
+		INSERT INTO poll_options (tweet_id, position, text)
+			SELECT poll.tweet_id, options.position - 1, options.text
+				FROM poll, unnest($3::VARCHAR[]) WITH ORDINALITY AS options (text, position)`,
+		tweetID, poll.ClosesAt.UTC(), pq.Array(poll.Options),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"poll":    *poll,
+		}).WithError(err).Error("InsertPoll query error.")
+		return err
+	}
+
+	return nil
+}
+
+func (db *pollsDB) GetPoll(tweetID int64) (*model.Poll, error) {
+	var (
+		poll    model.Poll
+		options pq.StringArray
+	)
+
+	err := db.QueryRow(
+		`SELECT polls.closes_at, array_agg(poll_options.text ORDER BY poll_options.position)
+			FROM polls JOIN poll_options ON poll_options.tweet_id = polls.tweet_id
+			WHERE polls.tweet_id = $1
+			GROUP BY polls.closes_at`,
+		tweetID,
+	).Scan(&poll.ClosesAt, &options)
+	if err == sql.ErrNoRows {
+		return nil, errors.NoResultsError
+	} else if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetPoll query error.")
+		return nil, err
+	}
+
+	poll.Options = make([]*model.PollOption, 0, len(options))
+	for _, text := range options {
+		poll.Options = append(poll.Options, &model.PollOption{Text: text})
+	}
+
+	return &poll, nil
+}
+
+func (db *pollsDB) GetPollVoteCounts(tweetID int64) ([]int64, error) {
+	var voteCounts pq.Int64Array
+
+	err := db.QueryRow(
+		`SELECT array_agg(vote_count ORDER BY position) FROM (
+			SELECT poll_options.position, COUNT(poll_votes.user_id) AS vote_count
+				FROM poll_options
+				LEFT JOIN poll_votes ON poll_votes.tweet_id = poll_options.tweet_id
+					AND poll_votes.position = poll_options.position
+				WHERE poll_options.tweet_id = $1
+				GROUP BY poll_options.position
+		) AS counts`,
+		tweetID,
+	).Scan(&voteCounts)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetPollVoteCounts query error.")
+		return nil, err
+	}
+
+	return voteCounts, nil
+}
+
+func (db *pollsDB) VotePoll(tweetID, userID int64, option int) (bool, error) {
+	result, err := db.Exec(
+		`INSERT INTO poll_votes (tweet_id, user_id, position) VALUES ($1, $2, $3)
+			ON CONFLICT (tweet_id, user_id) DO NOTHING`,
+		tweetID, userID, option,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"userID":  userID,
+			"option":  option,
+		}).WithError(err).Error("VotePoll query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+// GetPollVote returns index of the option voted by the user or -1 if user has
+// not voted.
+func (db *pollsDB) GetPollVote(tweetID, userID int64) (int, error) {
+	var option int
+
+	err := db.QueryRow(
+		`SELECT position FROM poll_votes WHERE tweet_id = $1 AND user_id = $2`,
+		tweetID, userID,
+	).Scan(&option)
+	if err == sql.ErrNoRows {
+		return -1, nil
+	} else if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"userID":  userID,
+		}).WithError(err).Error("GetPollVote query error.")
+		return 0, err
+	}
+
+	return option, nil
+}
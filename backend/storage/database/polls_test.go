@@ -0,0 +1,90 @@
+package database
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Polls", func() {
+	var (
+		conf      *config.Configuration = config.New()
+		db                              = NewPostgresDatabase(conf.Postgres)
+		usersDAO                        = NewUserDAO(db)
+		tweetsDAO                       = NewTweetDAO(db)
+		pollsDAO                        = NewPollsDAO(db)
+
+		user  *model.PublicUser
+		tweet *model.Tweet
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		user, err = usersDAO.InsertUser(&model.NewUserForm{
+			Username: "user",
+			Password: "password",
+			Email:    "user@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err = tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "tweet"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = pollsDAO.InsertPoll(tweet.ID, &model.NewPoll{
+			Options:  []string{"first", "second", "third"},
+			ClosesAt: time.Now().Add(time.Hour),
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM tweets; DELETE FROM polls;`)
+	})
+
+	It("should return poll options in order", func() {
+		poll, err := pollsDAO.GetPoll(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(poll.Options).To(HaveLen(3))
+		Expect(poll.Options[0].Text).To(Equal("first"))
+		Expect(poll.Options[2].Text).To(Equal("third"))
+	})
+
+	It("should count votes of each option", func() {
+		voted, err := pollsDAO.VotePoll(tweet.ID, user.ID, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(voted).To(BeTrue())
+
+		voteCounts, err := pollsDAO.GetPollVoteCounts(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(voteCounts).To(Equal([]int64{0, 0, 1}))
+
+		vote, err := pollsDAO.GetPollVote(tweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vote).To(Equal(2))
+	})
+
+	It("should not vote twice", func() {
+		pollsDAO.VotePoll(tweet.ID, user.ID, 0)
+
+		voted, err := pollsDAO.VotePoll(tweet.ID, user.ID, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(voted).To(BeFalse())
+
+		vote, err := pollsDAO.GetPollVote(tweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vote).To(Equal(0))
+	})
+
+	It("should return -1 when user has not voted", func() {
+		vote, err := pollsDAO.GetPollVote(tweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vote).To(Equal(-1))
+	})
+})
@@ -0,0 +1,207 @@
+package database
+
+import (
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/lib/pq"
+)
+
+// RetweetsDAO (Retweets Data Access Object) is interface which provides operations on Retweets database table.
+type RetweetsDAO interface {
+	RetweetTweet(tweetID, userID int64) (bool, error)
+	UnretweetTweet(tweetID, userID int64) (bool, error)
+	GetRetweetCount(tweetID int64) (int64, error)
+	IsRetweeted(tweetID, userID int64) (bool, error)
+	GetRetweetsByUsersIDs(usersIDs []int64, cursor *model.Cursor, limit int) ([]*model.Retweet, *model.Cursor, error)
+	GetRetweetersIDs(tweetID int64) ([]int64, error)
+	GetLatestRetweets(tweetsIDs, usersIDs []int64) ([]*model.Retweet, error)
+}
+
+type retweetsDB struct {
+	*Connection
+}
+
+// NewRetweetsDAO creates new struct which implements RetweetsDAO functions.
+func NewRetweetsDAO(conn *Connection) RetweetsDAO {
+	return &retweetsDB{conn}
+}
+
+func (db *retweetsDB) RetweetTweet(tweetID, userID int64) (bool, error) {
+	result, err := db.Exec(
+		`INSERT INTO retweets (tweet_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (tweet_id, user_id) DO NOTHING`,
+		tweetID, userID,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"userID":  userID,
+		}).WithError(err).Error("RetweetTweet query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *retweetsDB) UnretweetTweet(tweetID, userID int64) (bool, error) {
+	result, err := db.Exec(`DELETE FROM retweets WHERE tweet_id=$1 AND user_id=$2`, tweetID, userID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"userID":  userID,
+		}).WithError(err).Error("UnretweetTweet query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+func (db *retweetsDB) GetRetweetCount(tweetID int64) (int64, error) {
+	var retweetCount int64
+
+	err := db.QueryRow(`SELECT COUNT(*) FROM retweets WHERE tweet_id = $1`, tweetID).Scan(&retweetCount)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetRetweetCount query error.")
+		return 0, err
+	}
+
+	return retweetCount, nil
+}
+
+func (db *retweetsDB) IsRetweeted(tweetID, userID int64) (bool, error) {
+	var isRetweeted bool
+
+	err := db.QueryRow(
+		`SELECT exists (SELECT TRUE FROM retweets WHERE tweet_id = $1 AND user_id = $2)`,
+		tweetID, userID,
+	).Scan(&isRetweeted)
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"userID":  userID,
+		}).WithError(err).Error("IsRetweeted query error.")
+		return false, err
+	}
+
+	return isRetweeted, nil
+}
+
+// GetRetweetsByUsersIDs returns at most `limit` tweets retweeted by the users,
+// most recently retweeted first, which come after the `cursor` (nil means the
+// first page). Tweet retweeted by several users is returned only once, together
+// with the user who retweeted it most recently. Returned cursor is nil if
+// there are no more retweets.
+func (db *retweetsDB) GetRetweetsByUsersIDs(usersIDs []int64, cursor *model.Cursor, limit int) ([]*model.Retweet, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// Only the latest retweet of each tweet can be compared with the cursor,
+	// otherwise older retweets of tweets from previous pages would be returned.
+	// One additional row is fetched to know if there is a next page.
+	rows, err := db.Query(
+		`SELECT tweet_id, user_id, retweeted_at FROM (
+				SELECT DISTINCT ON (retweets.tweet_id) retweets.tweet_id, retweets.user_id, retweets.retweeted_at FROM retweets
+					JOIN tweets ON tweets.id = retweets.tweet_id
+					WHERE retweets.user_id = ANY($1) AND tweets.deleted_at IS NULL
+					ORDER BY retweets.tweet_id, retweets.retweeted_at DESC
+			) AS users_retweets
+			WHERE $2::TIMESTAMP IS NULL OR (retweeted_at, tweet_id) < ($2, $3)
+			ORDER BY retweeted_at DESC, tweet_id DESC
+			LIMIT $4`,
+		pq.Array(usersIDs), cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"usersIDs": usersIDs,
+			"cursor":   cursor,
+			"limit":    limit,
+		}).WithError(err).Error("GetRetweetsByUsersIDs query error.")
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	retweets, nextCursor, err := readRetweetsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetRetweetsByUsersIDs rows scan/iteration error.")
+		return nil, nil, err
+	}
+
+	return retweets, nextCursor, nil
+}
+
+func (db *retweetsDB) GetRetweetersIDs(tweetID int64) ([]int64, error) {
+	rows, err := db.Query(`SELECT user_id FROM retweets WHERE tweet_id = $1`, tweetID)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetRetweetersIDs query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	retweetersIDs := make([]int64, 0)
+	for rows.Next() {
+		var retweeterID int64
+
+		if err = rows.Scan(&retweeterID); err != nil {
+			log.WithError(err).Error("GetRetweetersIDs row scan error.")
+			return nil, err
+		}
+
+		retweetersIDs = append(retweetersIDs, retweeterID)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.WithError(err).Error("GetRetweetersIDs rows iteration error.")
+		return nil, err
+	}
+
+	return retweetersIDs, nil
+}
+
+// GetLatestRetweets returns the latest retweet of each of the tweets made by
+// any of the users. Tweets which were not retweeted by the users are skipped.
+func (db *retweetsDB) GetLatestRetweets(tweetsIDs, usersIDs []int64) ([]*model.Retweet, error) {
+	rows, err := db.Query(
+		`SELECT DISTINCT ON (tweet_id) tweet_id, user_id, retweeted_at FROM retweets
+			WHERE tweet_id = ANY($1) AND user_id = ANY($2)
+			ORDER BY tweet_id, retweeted_at DESC`,
+		pq.Array(tweetsIDs), pq.Array(usersIDs),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetsIDs": tweetsIDs,
+			"usersIDs":  usersIDs,
+		}).WithError(err).Error("GetLatestRetweets query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	retweets := make([]*model.Retweet, 0, len(tweetsIDs))
+	for rows.Next() {
+		var retweet model.Retweet
+
+		if err = rows.Scan(&retweet.TweetID, &retweet.UserID, &retweet.RetweetedAt); err != nil {
+			log.WithError(err).Error("GetLatestRetweets row scan error.")
+			return nil, err
+		}
+
+		retweets = append(retweets, &retweet)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.WithError(err).Error("GetLatestRetweets rows iteration error.")
+		return nil, err
+	}
+
+	return retweets, nil
+}
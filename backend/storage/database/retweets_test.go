@@ -0,0 +1,115 @@
+package database
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Retweets", func() {
+	var (
+		conf        *config.Configuration = config.New()
+		db                                = NewPostgresDatabase(conf.Postgres)
+		usersDAO                          = NewUserDAO(db)
+		tweetsDAO                         = NewTweetDAO(db)
+		retweetsDAO                       = NewRetweetsDAO(db)
+
+		user  *model.PublicUser
+		tweet *model.Tweet
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		user, err = usersDAO.InsertUser(&model.NewUserForm{
+			Username: "user",
+			Password: "password",
+			Email:    "user@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err = tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "tweet"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM tweets; DELETE FROM retweets;`)
+	})
+
+	It("should retweet tweet only once", func() {
+		retweeted, err := retweetsDAO.RetweetTweet(tweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retweeted).To(BeTrue())
+
+		retweeted, err = retweetsDAO.RetweetTweet(tweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retweeted).To(BeFalse())
+
+		count, err := retweetsDAO.GetRetweetCount(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(BeEquivalentTo(1))
+	})
+
+	It("should unretweet retweeted tweet", func() {
+		retweetsDAO.RetweetTweet(tweet.ID, user.ID)
+
+		unretweeted, err := retweetsDAO.UnretweetTweet(tweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unretweeted).To(BeTrue())
+
+		isRetweeted, err := retweetsDAO.IsRetweeted(tweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(isRetweeted).To(BeFalse())
+	})
+
+	It("should return retweeters", func() {
+		retweetsDAO.RetweetTweet(tweet.ID, user.ID)
+
+		retweetersIDs, err := retweetsDAO.GetRetweetersIDs(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retweetersIDs).To(ConsistOf(user.ID))
+	})
+
+	It("should return retweets of the users page by page, most recently retweeted first", func() {
+		other, err := usersDAO.InsertUser(&model.NewUserForm{
+			Username: "other",
+			Password: "password",
+			Email:    "other@email.com",
+			Name:     "other",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		newerTweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: user.ID, Content: "newer tweet"})
+		Expect(err).NotTo(HaveOccurred())
+
+		retweetsDAO.RetweetTweet(tweet.ID, user.ID)
+		retweetsDAO.RetweetTweet(newerTweet.ID, user.ID)
+		retweetsDAO.RetweetTweet(tweet.ID, other.ID)
+
+		// tweet retweeted by both users is returned once with the latest retweeter
+		retweets, cursor, err := retweetsDAO.GetRetweetsByUsersIDs([]int64{user.ID, other.ID}, nil, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retweets).To(HaveLen(1))
+		Expect(retweets[0].TweetID).To(Equal(tweet.ID))
+		Expect(retweets[0].UserID).To(Equal(other.ID))
+		Expect(cursor).NotTo(BeNil())
+
+		retweets, cursor, err = retweetsDAO.GetRetweetsByUsersIDs([]int64{user.ID, other.ID}, cursor, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retweets).To(HaveLen(1))
+		Expect(retweets[0].TweetID).To(Equal(newerTweet.ID))
+		Expect(retweets[0].UserID).To(Equal(user.ID))
+		Expect(cursor).To(BeNil())
+
+		latest, err := retweetsDAO.GetLatestRetweets([]int64{tweet.ID, newerTweet.ID}, []int64{user.ID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(latest).To(HaveLen(2))
+		Expect(latest[0].UserID).To(Equal(user.ID))
+		Expect(latest[1].UserID).To(Equal(user.ID))
+		Expect(latest[0].RetweetedAt).NotTo(BeZero())
+	})
+})
@@ -0,0 +1,226 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/lib/pq"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+// ScheduledTweetsDAO (Scheduled Tweets Data Access Object) is interface which provides operations on ScheduledTweets database table.
+type ScheduledTweetsDAO interface {
+	InsertScheduledTweet(newTweet *model.NewScheduledTweet) (*model.ScheduledTweet, error)
+	GetScheduledTweets(authorID int64) ([]*model.ScheduledTweet, error)
+	DeleteScheduledTweet(scheduledTweetID, authorID int64) error
+	PublishScheduledTweets(until time.Time, limit int, validate func(*model.NewTweet) error) ([]*model.Tweet, error)
+}
+
+type scheduledTweetsDB struct {
+	*Connection
+}
+
+// NewScheduledTweetsDAO creates new struct which implements ScheduledTweetsDAO functions.
+func NewScheduledTweetsDAO(conn *Connection) ScheduledTweetsDAO {
+	return &scheduledTweetsDB{conn}
+}
+
+func (db *scheduledTweetsDB) InsertScheduledTweet(newTweet *model.NewScheduledTweet) (*model.ScheduledTweet, error) {
+	row := db.QueryRow(
+		`INSERT INTO scheduled_tweets (author_id, content, in_reply_to_id, quoted_tweet_id, media_ids, visibility, publish_at)
+			VALUES ($1, $2, $3, $4, COALESCE($5, '{}'), COALESCE(NULLIF($6, ''), 'public'), $7)
+			RETURNING id, author_id, content, in_reply_to_id, quoted_tweet_id, media_ids, visibility, publish_at, created_at, failure`,
+		newTweet.AuthorID, newTweet.Content,
+		sql.NullInt64{Int64: newTweet.InReplyToID, Valid: newTweet.InReplyToID != 0},
+		sql.NullInt64{Int64: newTweet.QuotedTweetID, Valid: newTweet.QuotedTweetID != 0},
+		pq.Array(newTweet.MediaIDs), newTweet.Visibility, newTweet.PublishAt.UTC(),
+	)
+
+	scheduledTweet, err := readScheduledTweet(row)
+	if err != nil {
+		log.WithField("newTweet", *newTweet).WithError(err).Error("InsertScheduledTweet query error.")
+		return nil, err
+	}
+
+	return scheduledTweet, nil
+}
+
+func (db *scheduledTweetsDB) GetScheduledTweets(authorID int64) ([]*model.ScheduledTweet, error) {
+	rows, err := db.Query(
+		`SELECT id, author_id, content, in_reply_to_id, quoted_tweet_id, media_ids, visibility, publish_at, created_at, failure
+			FROM scheduled_tweets WHERE author_id = $1 ORDER BY publish_at, id`,
+		authorID,
+	)
+	if err != nil {
+		log.WithField("authorID", authorID).WithError(err).Error("GetScheduledTweets query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	scheduledTweets, err := readMultipleScheduledTweets(rows)
+	if err != nil {
+		log.WithError(err).Error("GetScheduledTweets rows scan/iteration error.")
+		return nil, err
+	}
+
+	return scheduledTweets, nil
+}
+
+func (db *scheduledTweetsDB) DeleteScheduledTweet(scheduledTweetID, authorID int64) error {
+	result, err := db.Exec(
+		`DELETE FROM scheduled_tweets WHERE id = $1 AND author_id = $2`,
+		scheduledTweetID, authorID,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"scheduledTweetID": scheduledTweetID,
+			"authorID":         authorID,
+		}).WithError(err).Error("DeleteScheduledTweet query error.")
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// tweet does not exist, belongs to someone else or was already published
+	if affectedRows == 0 {
+		return errors.NoResultsError
+	}
+
+	return nil
+}
+
+// publishFailure is the reason saved for scheduled tweets which passed the
+// validation but could not be inserted.
+const publishFailure = "Tweet could not be published."
+
+// PublishScheduledTweets publishes at most `limit` tweets which were scheduled
+// before `until`. Each tweet is checked with `validate` (if given) and
+// published in its own transaction, together with its media, hashtags and
+// mentions. Tweets which are rejected or can not be inserted are marked with
+// the failure and are never picked again, so one broken tweet does not block
+// the others. Published tweets are returned even if the error is returned.
+func (db *scheduledTweetsDB) PublishScheduledTweets(until time.Time, limit int, validate func(*model.NewTweet) error) ([]*model.Tweet, error) {
+	tweets := make([]*model.Tweet, 0, limit)
+	for len(tweets) < limit {
+		tweet, found, err := db.publishNextScheduledTweet(until, validate)
+		if err != nil {
+			return tweets, err
+		}
+		if !found {
+			break
+		}
+
+		if tweet != nil {
+			tweets = append(tweets, tweet)
+		}
+	}
+
+	return tweets, nil
+}
+
+// publishNextScheduledTweet publishes the oldest due tweet. Rows are claimed
+// with `FOR UPDATE SKIP LOCKED` and removed in the same transaction in which
+// tweets are inserted, so concurrent publishers (eg. on other replicas) never
+// publish the same tweet twice. Nil tweet is returned when the found tweet was
+// marked as failed.
+func (db *scheduledTweetsDB) publishNextScheduledTweet(until time.Time, validate func(*model.NewTweet) error) (*model.Tweet, bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.WithError(err).Error("PublishScheduledTweets begin transaction error.")
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(
+		`SELECT id, author_id, content, in_reply_to_id, quoted_tweet_id, media_ids, visibility, publish_at, created_at, failure
+			FROM scheduled_tweets WHERE publish_at <= $1 AND failure IS NULL
+			ORDER BY publish_at LIMIT 1
+			FOR UPDATE SKIP LOCKED`,
+		until.UTC(),
+	)
+
+	scheduledTweet, err := readScheduledTweet(row)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	} else if err != nil {
+		log.WithField("until", until).WithError(err).Error("PublishScheduledTweets query error.")
+		return nil, false, err
+	}
+
+	newTweet := &model.NewTweet{
+		AuthorID:      scheduledTweet.AuthorID,
+		Content:       scheduledTweet.Content,
+		InReplyToID:   scheduledTweet.InReplyToID,
+		QuotedTweetID: scheduledTweet.QuotedTweetID,
+		MediaIDs:      scheduledTweet.MediaIDs,
+		Visibility:    scheduledTweet.Visibility,
+	}
+
+	failure := ""
+	if validate != nil {
+		err = validate(newTweet)
+		if err == errors.UnexpectedError {
+			// the tweet is not rejected, it is tried again later
+			return nil, false, err
+		} else if err != nil {
+			failure = err.Error()
+		}
+	}
+
+	var tweet *model.Tweet
+	if failure == "" {
+		// failed insert aborts the transaction so it is rolled back only to
+		// the savepoint, where the row is still locked and can be marked
+		if _, err = tx.Exec(`SAVEPOINT publish`); err != nil {
+			log.WithError(err).Error("PublishScheduledTweets savepoint error.")
+			return nil, false, err
+		}
+
+		tweet, err = publishScheduledTweet(tx, scheduledTweet.ID, newTweet)
+		if err != nil {
+			if _, err = tx.Exec(`ROLLBACK TO SAVEPOINT publish`); err != nil {
+				log.WithError(err).Error("PublishScheduledTweets rollback to savepoint error.")
+				return nil, false, err
+			}
+
+			failure = publishFailure
+		}
+	}
+
+	if failure != "" {
+		_, err = tx.Exec(`UPDATE scheduled_tweets SET failure = $2 WHERE id = $1`, scheduledTweet.ID, failure)
+		if err != nil {
+			log.WithField("scheduledTweetID", scheduledTweet.ID).WithError(err).Error("PublishScheduledTweets mark failure query error.")
+			return nil, false, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.WithError(err).Error("PublishScheduledTweets commit error.")
+		return nil, false, err
+	}
+
+	return tweet, true, nil
+}
+
+// publishScheduledTweet inserts the tweet and removes the scheduled tweet.
+func publishScheduledTweet(tx *sql.Tx, scheduledTweetID int64, newTweet *model.NewTweet) (*model.Tweet, error) {
+	tweet, err := insertCompleteTweet(tx, newTweet)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`DELETE FROM scheduled_tweets WHERE id = $1`, scheduledTweetID)
+	if err != nil {
+		log.WithField("scheduledTweetID", scheduledTweetID).WithError(err).Error("PublishScheduledTweets delete query error.")
+		return nil, err
+	}
+
+	return tweet, nil
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+)
+
+var _ = Describe("ScheduledTweets", func() {
+	var (
+		conf               *config.Configuration = config.New()
+		db                                       = NewPostgresDatabase(conf.Postgres)
+		usersDAO                                 = NewUserDAO(db)
+		tweetsDAO                                = NewTweetDAO(db)
+		scheduledTweetsDAO                       = NewScheduledTweetsDAO(db)
+
+		user *model.PublicUser
+	)
+
+	schedule := func(content string, publishAt time.Time) *model.ScheduledTweet {
+		scheduledTweet, err := scheduledTweetsDAO.InsertScheduledTweet(&model.NewScheduledTweet{
+			NewTweet:  model.NewTweet{AuthorID: user.ID, Content: content},
+			PublishAt: publishAt,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		return scheduledTweet
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		user, err = usersDAO.InsertUser(&model.NewUserForm{
+			Username: "user",
+			Password: "password",
+			Email:    "user@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// HACK: this is hack since TRUNCATE can execute up to 1s... whereas this ~5ms
+		db.Exec(`DELETE FROM users; DELETE FROM tweets; DELETE FROM scheduled_tweets;`)
+	})
+
+	It("should publish only due tweets", func() {
+		now := time.Now()
+		schedule("later", now.Add(time.Hour))
+		due := schedule("due", now.Add(-time.Minute))
+
+		tweets, err := scheduledTweetsDAO.PublishScheduledTweets(now, 10, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweets).To(HaveLen(1))
+		Expect(tweets[0].Content).To(Equal(due.Content))
+
+		scheduledTweets, err := scheduledTweetsDAO.GetScheduledTweets(user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scheduledTweets).To(HaveLen(1))
+		Expect(scheduledTweets[0].Content).To(Equal("later"))
+	})
+
+	It("should mark rejected tweets as failed and publish the others", func() {
+		rejected := schedule("rejected", time.Now().Add(-2*time.Minute))
+		schedule("accepted", time.Now().Add(-time.Minute))
+
+		validate := func(tweet *model.NewTweet) error {
+			if tweet.Content == "rejected" {
+				return errors.NoResultsError
+			}
+
+			tweet.Content = "normalized"
+			return nil
+		}
+
+		tweets, err := scheduledTweetsDAO.PublishScheduledTweets(time.Now(), 10, validate)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweets).To(HaveLen(1))
+		Expect(tweets[0].Content).To(Equal("normalized"))
+
+		// failed tweet is kept for the author but never published again
+		tweets, err = scheduledTweetsDAO.PublishScheduledTweets(time.Now(), 10, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweets).To(BeEmpty())
+
+		scheduledTweets, err := scheduledTweetsDAO.GetScheduledTweets(user.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scheduledTweets).To(HaveLen(1))
+		Expect(scheduledTweets[0].ID).To(Equal(rejected.ID))
+		Expect(scheduledTweets[0].Failure).To(Equal(errors.NoResultsError.Error()))
+	})
+
+	It("should keep tweet which could not be validated for the next try", func() {
+		schedule("tweet", time.Now().Add(-time.Minute))
+
+		failing := func(tweet *model.NewTweet) error {
+			return errors.UnexpectedError
+		}
+
+		_, err := scheduledTweetsDAO.PublishScheduledTweets(time.Now(), 10, failing)
+		Expect(err).To(HaveOccurred())
+
+		tweets, err := scheduledTweetsDAO.PublishScheduledTweets(time.Now(), 10, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweets).To(HaveLen(1))
+	})
+
+	It("should publish each tweet once when publishing concurrently", func() {
+		const count = 20
+		for i := 0; i < count; i++ {
+			schedule("tweet", time.Now().Add(-time.Minute))
+		}
+
+		var (
+			wg        sync.WaitGroup
+			mutex     sync.Mutex
+			published = make(map[int64]bool)
+		)
+
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				for {
+					tweets, err := scheduledTweetsDAO.PublishScheduledTweets(time.Now(), 3, nil)
+					Expect(err).NotTo(HaveOccurred())
+					if len(tweets) == 0 {
+						return
+					}
+
+					mutex.Lock()
+					for _, tweet := range tweets {
+						published[tweet.ID] = true
+					}
+					mutex.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		Expect(published).To(HaveLen(count))
+
+		tweetsIDs, _, err := tweetsDAO.GetTweetsIDsByAuthorIDs([]int64{user.ID}, nil, count+1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(HaveLen(count))
+	})
+
+	It("should not delete scheduled tweet of other user", func() {
+		scheduledTweet := schedule("tweet", time.Now().Add(time.Hour))
+
+		err := scheduledTweetsDAO.DeleteScheduledTweet(scheduledTweet.ID, user.ID+1)
+		Expect(err).To(HaveOccurred())
+
+		err = scheduledTweetsDAO.DeleteScheduledTweet(scheduledTweet.ID, user.ID)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -2,9 +2,12 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 
+	"github.com/VirrageS/chirp/backend/entities"
 	"github.com/VirrageS/chirp/backend/model"
 	"github.com/VirrageS/chirp/backend/model/errors"
 	"github.com/lib/pq"
@@ -12,11 +15,21 @@ import (
 
 // TweetsDAO (Tweets Data Access Object) is interface which provides operations on Tweet database table.
 type TweetsDAO interface {
-	GetTweetsIDsByAuthorID(userID int64) ([]int64, error)
+	GetTweetsIDsByAuthorIDs(authorsIDs []int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+	GetTweetsPositionsByAuthorIDs(authorsIDs []int64, cursor *model.Cursor, limit int) ([]model.Cursor, *model.Cursor, error)
 	GetTweetsByIDs(tweetsIDs []int64) ([]*model.Tweet, error)
 	GetTweetByID(tweetID int64) (*model.Tweet, error)
+	GetDeletedTweetByID(tweetID int64) (*model.Tweet, error)
 	InsertTweet(newTweet *model.NewTweet) (*model.Tweet, error)
+	EditTweet(tweetID int64, content string) (*model.Tweet, error)
+	GetTweetRevisions(tweetID int64) ([]*model.TweetRevision, error)
 	DeleteTweet(tweetID int64) error
+	RestoreTweet(tweetID int64, window time.Duration) (bool, error)
+	GetTweetsIDsToPurge(window time.Duration, limit int, excludedIDs []int64) ([]int64, error)
+	PurgeTweet(tweetID int64) (bool, error)
+	GetRepliesIDs(tweetID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+	GetReplyCount(tweetID int64) (int64, error)
+	HasRecentDuplicate(authorID int64, content string, window time.Duration) (bool, error)
 }
 
 type tweetsDB struct {
@@ -28,26 +41,60 @@ func NewTweetDAO(conn *Connection) TweetsDAO {
 	return &tweetsDB{conn}
 }
 
-func (db *tweetsDB) GetTweetsIDsByAuthorID(userID int64) ([]int64, error) {
-	rows, err := db.Query(`SELECT id FROM tweets WHERE author_id = $1 ORDER BY created_at DESC`, userID)
+// GetTweetsIDsByAuthorIDs returns IDs of at most `limit` tweets of the authors,
+// newest first, which come after the `cursor` (nil means the first page).
+// Returned cursor is nil if there are no more tweets.
+func (db *tweetsDB) GetTweetsIDsByAuthorIDs(authorsIDs []int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	positions, nextCursor, err := db.GetTweetsPositionsByAuthorIDs(authorsIDs, cursor, limit)
 	if err != nil {
-		log.WithField("userID", userID).WithError(err).Error("GetTweetsIDsByAuthorID query error.")
-		return nil, err
+		return nil, nil, err
+	}
+
+	tweetsIDs := make([]int64, 0, len(positions))
+	for _, position := range positions {
+		tweetsIDs = append(tweetsIDs, position.ID)
+	}
+
+	return tweetsIDs, nextCursor, nil
+}
+
+// GetTweetsPositionsByAuthorIDs works like `GetTweetsIDsByAuthorIDs` but
+// returns both IDs and creation times of the tweets.
+func (db *tweetsDB) GetTweetsPositionsByAuthorIDs(authorsIDs []int64, cursor *model.Cursor, limit int) ([]model.Cursor, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT id, created_at FROM tweets
+			WHERE author_id = ANY($1) AND deleted_at IS NULL
+				AND ($2::TIMESTAMP IS NULL OR (created_at, id) < ($2, $3))
+			ORDER BY created_at DESC, id DESC
+			LIMIT $4`,
+		pq.Array(authorsIDs), cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"authorsIDs": authorsIDs,
+			"cursor":     cursor,
+			"limit":      limit,
+		}).WithError(err).Error("GetTweetsPositionsByAuthorIDs query error.")
+		return nil, nil, err
 	}
 	defer rows.Close()
 
-	tweetIDs, err := readMultipleTweetsIDs(rows)
+	positions, nextCursor, err := readPositionsPage(rows, limit)
 	if err != nil {
-		log.WithError(err).Error("GetTweetsIDsByAuthorID rows scan/iteration error.")
+		log.WithError(err).Error("GetTweetsPositionsByAuthorIDs rows scan/iteration error.")
+		return nil, nil, err
 	}
 
-	return tweetIDs, nil
+	return positions, nextCursor, nil
 }
 
 func (db *tweetsDB) GetTweetsByIDs(tweetsIDs []int64) ([]*model.Tweet, error) {
 	rows, err := db.Query(
-		`SELECT id, created_at, content, author_id FROM tweets
-			WHERE id = cors.New(config)($1) ORDER BY created_at DESC`,
+		`SELECT id, created_at, edited_at, content, author_id, in_reply_to_id, root_id, quoted_tweet_id, entities, visibility FROM tweets
+			WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY created_at DESC`,
 		pq.Array(tweetsIDs),
 	)
 	if err != nil {
@@ -66,8 +113,8 @@ func (db *tweetsDB) GetTweetsByIDs(tweetsIDs []int64) ([]*model.Tweet, error) {
 
 func (db *tweetsDB) GetTweetByID(tweetID int64) (*model.Tweet, error) {
 	row := db.QueryRow(
-		`SELECT id, created_at, content, author_id FROM tweets
-			WHERE id = $1 ORDER BY created_at DESC`,
+		`SELECT id, created_at, edited_at, content, author_id, in_reply_to_id, root_id, quoted_tweet_id, entities, visibility FROM tweets
+			WHERE id = $1 AND deleted_at IS NULL ORDER BY created_at DESC`,
 		tweetID,
 	)
 
@@ -82,11 +129,99 @@ func (db *tweetsDB) GetTweetByID(tweetID int64) (*model.Tweet, error) {
 	return tweet, err
 }
 
-func (db *tweetsDB) InsertTweet(newTweet *model.NewTweet) (*model.Tweet, error) {
+// GetDeletedTweetByID returns tweet which was deleted but not purged yet.
+func (db *tweetsDB) GetDeletedTweetByID(tweetID int64) (*model.Tweet, error) {
 	row := db.QueryRow(
-		`INSERT INTO tweets (author_id, content) VALUES ($1, $2)
-			RETURNING id, created_at, content, author_id`,
+		`SELECT id, created_at, edited_at, content, author_id, in_reply_to_id, root_id, quoted_tweet_id, entities, visibility FROM tweets
+			WHERE id = $1 AND deleted_at IS NOT NULL`,
+		tweetID,
+	)
+
+	tweet, err := readTweet(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.NoResultsError
+	} else if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetDeletedTweetByID query error.")
+		return nil, err
+	}
+
+	return tweet, err
+}
+
+// InsertTweet inserts the tweet together with its media, poll, hashtags and
+// mentions in one transaction, so incomplete tweet is never visible.
+func (db *tweetsDB) InsertTweet(newTweet *model.NewTweet) (*model.Tweet, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.WithError(err).Error("InsertTweet begin transaction error.")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	insertedTweet, err := insertCompleteTweet(tx, newTweet)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.WithError(err).Error("InsertTweet commit error.")
+		return nil, err
+	}
+
+	return insertedTweet, nil
+}
+
+// insertCompleteTweet inserts the tweet with its media, poll, hashtags and
+// mentions. It has to be used inside of the transaction.
+func insertCompleteTweet(q queryer, newTweet *model.NewTweet) (*model.Tweet, error) {
+	insertedTweet, err := insertTweet(q, newTweet)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(newTweet.MediaIDs) > 0 {
+		err = attachMedia(q, insertedTweet.ID, newTweet.AuthorID, newTweet.MediaIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if newTweet.Poll != nil {
+		if err = insertPoll(q, insertedTweet.ID, newTweet.Poll); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = insertTweetHashtags(q, insertedTweet.ID, insertedTweet.Entities.HashtagsNames()); err != nil {
+		return nil, err
+	}
+	if err = insertTweetMentions(q, insertedTweet.ID, insertedTweet.Entities.Usernames()); err != nil {
+		return nil, err
+	}
+
+	return insertedTweet, nil
+}
+
+// insertTweet inserts the tweet using given queryer so it can be done
+// inside of the transaction as well.
+func insertTweet(q queryer, newTweet *model.NewTweet) (*model.Tweet, error) {
+	// entities are computed only once and stored together with the tweet
+	entitiesJSON, err := json.Marshal(entities.Parse(newTweet.Content))
+	if err != nil {
+		log.WithField("newTweet", *newTweet).WithError(err).Error("InsertTweet entities marshal error.")
+		return nil, err
+	}
+
+	// root of the thread is inherited from the parent or parent is the root
+	// itself, tweets without visibility are public
+	row := q.QueryRow(
+		`INSERT INTO tweets (author_id, content, in_reply_to_id, root_id, quoted_tweet_id, entities, visibility)
+			VALUES ($1, $2, $3, (SELECT COALESCE(root_id, id) FROM tweets WHERE id = $3), $4, $5, COALESCE(NULLIF($6, ''), 'public'))
+			RETURNING id, created_at, edited_at, content, author_id, in_reply_to_id, root_id, quoted_tweet_id, entities, visibility`,
 		newTweet.AuthorID, newTweet.Content,
+		sql.NullInt64{Int64: newTweet.InReplyToID, Valid: newTweet.InReplyToID != 0},
+		sql.NullInt64{Int64: newTweet.QuotedTweetID, Valid: newTweet.QuotedTweetID != 0},
+		entitiesJSON, newTweet.Visibility,
 	)
 
 	insertedTweet, err := readTweet(row)
@@ -98,12 +233,291 @@ func (db *tweetsDB) InsertTweet(newTweet *model.NewTweet) (*model.Tweet, error)
 	return insertedTweet, nil
 }
 
+// EditTweet replaces content of the tweet together with its hashtags and
+// mentions in one transaction, so the tweet is never left with entities of
+// the previous version.
+func (db *tweetsDB) EditTweet(tweetID int64, content string) (*model.Tweet, error) {
+	parsedEntities := entities.Parse(content)
+	entitiesJSON, err := json.Marshal(parsedEntities)
+	if err != nil {
+		log.WithField("content", content).WithError(err).Error("EditTweet entities marshal error.")
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.WithError(err).Error("EditTweet begin transaction error.")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Current version is moved to revisions in the same statement. Row is
+	// locked so concurrent edits do not lose any version.
+	row := tx.QueryRow(
+		`WITH revision AS (
+			INSERT INTO tweet_revisions (tweet_id, content, created_at)
+				SELECT id, content, COALESCE(edited_at, created_at) FROM tweets
+					WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		)
+		UPDATE tweets SET content = $2, entities = $3, edited_at = now()
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING id, created_at, edited_at, content, author_id, in_reply_to_id, root_id, quoted_tweet_id, entities, visibility`,
+		tweetID, content, entitiesJSON,
+	)
+
+	editedTweet, err := readTweet(row)
+	if err == sql.ErrNoRows {
+		return nil, errors.NoResultsError
+	} else if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"content": content,
+		}).WithError(err).Error("EditTweet query error.")
+		return nil, err
+	}
+
+	if err = deleteTweetHashtags(tx, tweetID); err != nil {
+		return nil, err
+	}
+	if err = insertTweetHashtags(tx, tweetID, parsedEntities.HashtagsNames()); err != nil {
+		return nil, err
+	}
+
+	if err = deleteTweetMentions(tx, tweetID); err != nil {
+		return nil, err
+	}
+	if err = insertTweetMentions(tx, tweetID, parsedEntities.Usernames()); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.WithError(err).Error("EditTweet commit error.")
+		return nil, err
+	}
+
+	return editedTweet, nil
+}
+
+func (db *tweetsDB) GetTweetRevisions(tweetID int64) ([]*model.TweetRevision, error) {
+	rows, err := db.Query(
+		`SELECT content, created_at FROM tweet_revisions
+			WHERE tweet_id = $1 ORDER BY created_at DESC`,
+		tweetID,
+	)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetTweetRevisions query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]*model.TweetRevision, 0)
+	for rows.Next() {
+		var revision model.TweetRevision
+
+		if err = rows.Scan(&revision.Content, &revision.CreatedAt); err != nil {
+			log.WithError(err).Error("GetTweetRevisions row scan error.")
+			return nil, err
+		}
+
+		revisions = append(revisions, &revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.WithError(err).Error("GetTweetRevisions rows iteration error.")
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// DeleteTweet only marks the tweet as deleted so it can be restored later.
+// Hashtags of the tweet are removed in the same transaction, so the tweet is
+// not listed under them. Deleted tweets are removed for good by PurgeTweet.
 func (db *tweetsDB) DeleteTweet(tweetID int64) error {
-	_, err := db.Exec(`DELETE FROM tweets WHERE id=$1`, tweetID)
+	tx, err := db.Begin()
+	if err != nil {
+		log.WithError(err).Error("DeleteTweet begin transaction error.")
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`UPDATE tweets SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`, tweetID)
 	if err != nil {
 		log.WithField("tweetID", tweetID).WithError(err).Error("DeleteTweet query error.")
 		return err
 	}
 
+	if err = deleteTweetHashtags(tx, tweetID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.WithError(err).Error("DeleteTweet commit error.")
+		return err
+	}
+
 	return nil
 }
+
+// RestoreTweet restores tweet which was deleted during the last `window`
+// together with its hashtags. Returns true if the tweet was restored.
+func (db *tweetsDB) RestoreTweet(tweetID int64, window time.Duration) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.WithError(err).Error("RestoreTweet begin transaction error.")
+		return false, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(
+		`UPDATE tweets SET deleted_at = NULL
+			WHERE id = $1 AND deleted_at > now() - make_interval(secs => $2)
+			RETURNING id, created_at, edited_at, content, author_id, in_reply_to_id, root_id, quoted_tweet_id, entities, visibility`,
+		tweetID, window.Seconds(),
+	)
+
+	restoredTweet, err := readTweet(row)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"window":  window,
+		}).WithError(err).Error("RestoreTweet query error.")
+		return false, err
+	}
+
+	if err = insertTweetHashtags(tx, tweetID, restoredTweet.Entities.HashtagsNames()); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.WithError(err).Error("RestoreTweet commit error.")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetTweetsIDsToPurge returns IDs of at most `limit` tweets which were deleted
+// more than `window` ago, except tweets with `excludedIDs`.
+func (db *tweetsDB) GetTweetsIDsToPurge(window time.Duration, limit int, excludedIDs []int64) ([]int64, error) {
+	if excludedIDs == nil {
+		// NULL array would exclude all tweets
+		excludedIDs = []int64{}
+	}
+
+	rows, err := db.Query(
+		`SELECT id FROM tweets
+			WHERE deleted_at < now() - make_interval(secs => $1) AND id <> ALL($3)
+			ORDER BY deleted_at LIMIT $2`,
+		window.Seconds(), limit, pq.Array(excludedIDs),
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"window": window,
+			"limit":  limit,
+		}).WithError(err).Error("GetTweetsIDsToPurge query error.")
+		return nil, err
+	}
+	defer rows.Close()
+
+	tweetsIDs, err := readMultipleTweetsIDs(rows)
+	if err != nil {
+		log.WithError(err).Error("GetTweetsIDsToPurge rows scan/iteration error.")
+		return nil, err
+	}
+
+	return tweetsIDs, nil
+}
+
+// PurgeTweet removes deleted tweet for good together with everything which
+// references it (likes, retweets, media, ...). Returns true if the tweet was
+// removed, false means that it was not deleted or has been already purged.
+func (db *tweetsDB) PurgeTweet(tweetID int64) (bool, error) {
+	result, err := db.Exec(`DELETE FROM tweets WHERE id = $1 AND deleted_at IS NOT NULL`, tweetID)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("PurgeTweet query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
+
+// HasRecentDuplicate checks if the author posted tweet with exactly the same
+// content during the last `window`.
+func (db *tweetsDB) HasRecentDuplicate(authorID int64, content string, window time.Duration) (bool, error) {
+	var hasDuplicate bool
+
+	// window is computed by the database so it does not depend on time zones
+	err := db.QueryRow(
+		`SELECT exists (SELECT TRUE FROM tweets
+			WHERE author_id = $1 AND content = $2 AND deleted_at IS NULL
+				AND created_at > now() - make_interval(secs => $3))`,
+		authorID, content, window.Seconds(),
+	).Scan(&hasDuplicate)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"authorID": authorID,
+			"content":  content,
+			"window":   window,
+		}).WithError(err).Error("HasRecentDuplicate query error.")
+		return false, err
+	}
+
+	return hasDuplicate, nil
+}
+
+// GetRepliesIDs returns IDs of at most `limit` replies to the tweet, oldest
+// first, which come after the `cursor` (nil means the first page). Returned
+// cursor is nil if there are no more replies.
+func (db *tweetsDB) GetRepliesIDs(tweetID int64, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	cursorTime, cursorID := cursorArgs(cursor)
+
+	// one additional row is fetched to know if there is a next page
+	rows, err := db.Query(
+		`SELECT id, created_at FROM tweets
+			WHERE in_reply_to_id = $1 AND deleted_at IS NULL
+				AND ($2::TIMESTAMP IS NULL OR (created_at, id) > ($2, $3))
+			ORDER BY created_at ASC, id ASC
+			LIMIT $4`,
+		tweetID, cursorTime, cursorID, limit+1,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"tweetID": tweetID,
+			"cursor":  cursor,
+			"limit":   limit,
+		}).WithError(err).Error("GetRepliesIDs query error.")
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	repliesIDs, nextCursor, err := readIDsPage(rows, limit)
+	if err != nil {
+		log.WithError(err).Error("GetRepliesIDs rows scan/iteration error.")
+		return nil, nil, err
+	}
+
+	return repliesIDs, nextCursor, nil
+}
+
+func (db *tweetsDB) GetReplyCount(tweetID int64) (int64, error) {
+	var replyCount int64
+
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM tweets WHERE in_reply_to_id = $1 AND deleted_at IS NULL`,
+		tweetID,
+	).Scan(&replyCount)
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("GetReplyCount query error.")
+		return 0, err
+	}
+
+	return replyCount, nil
+}
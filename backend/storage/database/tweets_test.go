@@ -1,16 +1,22 @@
 package database
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
 )
 
 var _ = Describe("Tweets", func() {
 	var (
-		conf *config.Configuration = config.New()
-		db                         = NewPostgresDatabase(conf.Postgres)
+		conf      *config.Configuration = config.New()
+		db                              = NewPostgresDatabase(conf.Postgres)
+		usersDAO                        = NewUserDAO(db)
+		tweetsDAO                       = NewTweetDAO(db)
 	)
 
 	BeforeEach(func() {})
@@ -23,4 +29,229 @@ var _ = Describe("Tweets", func() {
 	It("should do something", func() {
 		Expect(true).To(BeTrue())
 	})
+
+	It("should find duplicates posted by the same author within the window", func() {
+		author, err := usersDAO.InsertUser(&model.NewUserForm{
+			Username: "author",
+			Password: "password",
+			Email:    "author@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		other, err := usersDAO.InsertUser(&model.NewUserForm{
+			Username: "other",
+			Password: "password",
+			Email:    "other@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: author.ID, Content: "tweet"})
+		Expect(err).NotTo(HaveOccurred())
+
+		duplicate, err := tweetsDAO.HasRecentDuplicate(author.ID, "tweet", time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeTrue())
+
+		duplicate, err = tweetsDAO.HasRecentDuplicate(other.ID, "tweet", time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeFalse())
+
+		duplicate, err = tweetsDAO.HasRecentDuplicate(author.ID, "other tweet", time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeFalse())
+
+		_, err = db.Exec(`UPDATE tweets SET created_at = now() - interval '2 hours' WHERE id = $1`, tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		duplicate, err = tweetsDAO.HasRecentDuplicate(author.ID, "tweet", time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(duplicate).To(BeFalse())
+	})
+	It("should hide deleted tweets until they are purged", func() {
+		author, err := usersDAO.InsertUser(&model.NewUserForm{
+			Username: "author",
+			Password: "password",
+			Email:    "author@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: author.ID, Content: "tweet"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = tweetsDAO.DeleteTweet(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = tweetsDAO.GetTweetByID(tweet.ID)
+		Expect(err).To(Equal(errors.NoResultsError))
+
+		deletedTweet, err := tweetsDAO.GetDeletedTweetByID(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletedTweet.ID).To(Equal(tweet.ID))
+
+		tweetsIDs, err := tweetsDAO.GetTweetsIDsToPurge(time.Hour, 10, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(BeEmpty())
+
+		restored, err := tweetsDAO.RestoreTweet(tweet.ID, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored).To(BeTrue())
+
+		_, err = tweetsDAO.GetTweetByID(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = tweetsDAO.DeleteTweet(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = db.Exec(`UPDATE tweets SET deleted_at = now() - interval '2 hours' WHERE id = $1`, tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		restored, err = tweetsDAO.RestoreTweet(tweet.ID, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored).To(BeFalse())
+
+		tweetsIDs, err = tweetsDAO.GetTweetsIDsToPurge(time.Hour, 10, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweet.ID}))
+
+		tweetsIDs, err = tweetsDAO.GetTweetsIDsToPurge(time.Hour, 10, []int64{tweet.ID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(BeEmpty())
+
+		purged, err := tweetsDAO.PurgeTweet(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(BeTrue())
+
+		purged, err = tweetsDAO.PurgeTweet(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(BeFalse())
+
+		_, err = tweetsDAO.GetDeletedTweetByID(tweet.ID)
+		Expect(err).To(Equal(errors.NoResultsError))
+	})
+
+	It("should not edit deleted tweet", func() {
+		author, err := usersDAO.InsertUser(&model.NewUserForm{
+			Username: "author",
+			Password: "password",
+			Email:    "author@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: author.ID, Content: "tweet"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsDAO.DeleteTweet(tweet.ID)).To(Succeed())
+
+		_, err = tweetsDAO.EditTweet(tweet.ID, "edited")
+		Expect(err).To(Equal(errors.NoResultsError))
+
+		revisions, err := tweetsDAO.GetTweetRevisions(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revisions).To(BeEmpty())
+
+		deletedTweet, err := tweetsDAO.GetDeletedTweetByID(tweet.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletedTweet.Content).To(Equal("tweet"))
+	})
+
+	It("should not insert tweet when its poll can not be inserted", func() {
+		author, err := usersDAO.InsertUser(&model.NewUserForm{
+			Username: "author",
+			Password: "password",
+			Email:    "author@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = tweetsDAO.InsertTweet(&model.NewTweet{
+			AuthorID: author.ID,
+			Content:  "tweet",
+			Poll: &model.NewPoll{
+				Options:  []string{"short", "option longer than the column allows"},
+				ClosesAt: time.Now().Add(time.Hour),
+			},
+		})
+		Expect(err).To(HaveOccurred())
+
+		tweetsIDs, _, err := tweetsDAO.GetTweetsIDsByAuthorIDs([]int64{author.ID}, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(BeEmpty())
+	})
+
+	It("should return tweets of the authors page by page, newest first", func() {
+		authors := make([]*model.PublicUser, 0)
+		for _, name := range []string{"first", "second"} {
+			author, err := usersDAO.InsertUser(&model.NewUserForm{
+				Username: name,
+				Password: "password",
+				Email:    name + "@email.com",
+				Name:     name,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			authors = append(authors, author)
+		}
+
+		tweets := make([]*model.Tweet, 0)
+		for _, author := range []*model.PublicUser{authors[0], authors[1], authors[0]} {
+			tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: author.ID, Content: "tweet"})
+			Expect(err).NotTo(HaveOccurred())
+
+			tweets = append(tweets, tweet)
+		}
+
+		// tweets created at the same time are ordered by ID
+		_, err := db.Exec(`UPDATE tweets SET created_at = $1 WHERE id = $2`, tweets[1].CreatedAt, tweets[2].ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		authorsIDs := []int64{authors[0].ID, authors[1].ID}
+
+		tweetsIDs, cursor, err := tweetsDAO.GetTweetsIDsByAuthorIDs(authorsIDs, nil, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweets[2].ID, tweets[1].ID}))
+		Expect(cursor).NotTo(BeNil())
+
+		tweetsIDs, cursor, err = tweetsDAO.GetTweetsIDsByAuthorIDs(authorsIDs, cursor, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweets[0].ID}))
+		Expect(cursor).To(BeNil())
+
+		tweetsIDs, _, err = tweetsDAO.GetTweetsIDsByAuthorIDs([]int64{authors[1].ID}, nil, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweetsIDs).To(Equal([]int64{tweets[1].ID}))
+	})
+
+	It("should return replies page by page, oldest first", func() {
+		author, err := usersDAO.InsertUser(&model.NewUserForm{
+			Username: "author",
+			Password: "password",
+			Email:    "author@email.com",
+			Name:     "name",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tweet, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: author.ID, Content: "tweet"})
+		Expect(err).NotTo(HaveOccurred())
+
+		repliesIDs := make([]int64, 0)
+		for i := 0; i < 3; i++ {
+			reply, err := tweetsDAO.InsertTweet(&model.NewTweet{AuthorID: author.ID, Content: "reply", InReplyToID: tweet.ID})
+			Expect(err).NotTo(HaveOccurred())
+
+			repliesIDs = append(repliesIDs, reply.ID)
+		}
+
+		firstPage, cursor, err := tweetsDAO.GetRepliesIDs(tweet.ID, nil, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(firstPage).To(Equal(repliesIDs[:2]))
+		Expect(cursor).NotTo(BeNil())
+
+		secondPage, cursor, err := tweetsDAO.GetRepliesIDs(tweet.ID, cursor, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(secondPage).To(Equal(repliesIDs[2:]))
+		Expect(cursor).To(BeNil())
+	})
 })
@@ -17,6 +17,8 @@ type UsersDAO interface {
 	GetUserByEmail(userEmail string) (*model.User, error)
 	InsertUser(user *model.NewUserForm) (*model.PublicUser, error)
 	UpdateUserLastLoginTime(userID int64, lastLoginTime *time.Time) error
+	PinTweet(userID, tweetID int64) error
+	UnpinTweet(userID, tweetID int64) (bool, error)
 }
 
 type usersDB struct {
@@ -46,9 +48,12 @@ func (db *usersDB) GetPublicUsers() ([]*model.PublicUser, error) {
 }
 
 func (db *usersDB) GetPublicUserByID(userID int64) (*model.PublicUser, error) {
-	row := db.QueryRow(`SELECT id, username, name, avatar_url FROM users WHERE id = $1`, userID)
+	var user model.PublicUser
 
-	user, err := readPublicUser(row)
+	err := db.QueryRow(
+		`SELECT id, username, name, avatar_url, COALESCE(pinned_tweet_id, 0) FROM users WHERE id = $1`,
+		userID,
+	).Scan(&user.ID, &user.Username, &user.Name, &user.AvatarUrl, &user.PinnedTweetID)
 	if err == sql.ErrNoRows {
 		return nil, errors.NoResultsError
 	} else if err != nil {
@@ -56,7 +61,7 @@ func (db *usersDB) GetPublicUserByID(userID int64) (*model.PublicUser, error) {
 		return nil, err
 	}
 
-	return user, err
+	return &user, err
 }
 
 func (db *usersDB) GetUserByEmail(userEmail string) (*model.User, error) {
@@ -109,3 +114,39 @@ func (db *usersDB) UpdateUserLastLoginTime(userID int64, lastLoginTime *time.Tim
 
 	return nil
 }
+
+func (db *usersDB) PinTweet(userID, tweetID int64) error {
+	_, err := db.Exec(`UPDATE users SET pinned_tweet_id = $1 WHERE id = $2`, tweetID, userID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID":  userID,
+			"tweetID": tweetID,
+		}).WithError(err).Error("PinTweet query error.")
+		return err
+	}
+
+	return nil
+}
+
+// UnpinTweet clears pinned tweet of the user but only if it is the given tweet.
+// Returns true if the pin was cleared.
+func (db *usersDB) UnpinTweet(userID, tweetID int64) (bool, error) {
+	result, err := db.Exec(
+		`UPDATE users SET pinned_tweet_id = NULL WHERE id = $1 AND pinned_tweet_id = $2`,
+		userID, tweetID,
+	)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"userID":  userID,
+			"tweetID": tweetID,
+		}).WithError(err).Error("UnpinTweet query error.")
+		return false, err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affectedRows > 0, nil
+}
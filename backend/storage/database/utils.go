@@ -2,7 +2,11 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 
+	"github.com/lib/pq"
+
+	"github.com/VirrageS/chirp/backend/entities"
 	"github.com/VirrageS/chirp/backend/model"
 )
 
@@ -11,6 +15,13 @@ type scannable interface {
 	Scan(dest ...interface{}) error
 }
 
+// queryer is helper interface that wraps database and transaction so queries
+// can be executed on both of them in the same function.
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 func readMultipleUsers(rows *sql.Rows) ([]*model.PublicUser, error) {
 	users := make([]*model.PublicUser, 0)
 
@@ -97,15 +108,274 @@ func readMultipleTweets(rows *sql.Rows) ([]*model.Tweet, error) {
 
 func readTweet(row scannable) (*model.Tweet, error) {
 	var (
-		tweet    model.Tweet
-		authorID int64
+		tweet         model.Tweet
+		authorID      int64
+		inReplyToID   sql.NullInt64
+		rootID        sql.NullInt64
+		quotedTweetID sql.NullInt64
+		editedAt      pq.NullTime
+		entitiesJSON  []byte
 	)
 
-	err := row.Scan(&tweet.ID, &tweet.CreatedAt, &tweet.Content, &authorID)
+	err := row.Scan(
+		&tweet.ID, &tweet.CreatedAt, &editedAt, &tweet.Content, &authorID,
+		&inReplyToID, &rootID, &quotedTweetID, &entitiesJSON, &tweet.Visibility,
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	// tweets inserted before entities were stored do not have them
+	if entitiesJSON == nil {
+		tweet.Entities = entities.Parse(tweet.Content)
+	} else if err = json.Unmarshal(entitiesJSON, &tweet.Entities); err != nil {
+		return nil, err
+	}
+
+	if editedAt.Valid {
+		tweet.EditedAt = &editedAt.Time
+	}
+
 	tweet.Author = &model.PublicUser{ID: authorID}
+	tweet.InReplyToID = inReplyToID.Int64
+	tweet.RootID = rootID.Int64
+	tweet.QuotedTweetID = quotedTweetID.Int64
 	return &tweet, nil
 }
+
+func readMedia(row scannable) (*model.Media, error) {
+	var (
+		media   model.Media
+		tweetID sql.NullInt64
+	)
+
+	err := row.Scan(
+		&media.ID, &media.OwnerID, &tweetID, &media.Key,
+		&media.MimeType, &media.Width, &media.Height, &media.Size,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	media.TweetID = tweetID.Int64
+	return &media, nil
+}
+
+func readMultipleMedia(rows *sql.Rows) ([]*model.Media, error) {
+	media := make([]*model.Media, 0)
+
+	for rows.Next() {
+		m, err := readMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		media = append(media, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func readScheduledTweet(row scannable) (*model.ScheduledTweet, error) {
+	var (
+		scheduledTweet model.ScheduledTweet
+		inReplyToID    sql.NullInt64
+		quotedTweetID  sql.NullInt64
+		mediaIDs       pq.Int64Array
+		failure        sql.NullString
+	)
+
+	err := row.Scan(
+		&scheduledTweet.ID, &scheduledTweet.AuthorID, &scheduledTweet.Content, &inReplyToID,
+		&quotedTweetID, &mediaIDs, &scheduledTweet.Visibility, &scheduledTweet.PublishAt, &scheduledTweet.CreatedAt,
+		&failure,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledTweet.InReplyToID = inReplyToID.Int64
+	scheduledTweet.QuotedTweetID = quotedTweetID.Int64
+	scheduledTweet.MediaIDs = append(make([]int64, 0, len(mediaIDs)), mediaIDs...)
+	scheduledTweet.Failure = failure.String
+	return &scheduledTweet, nil
+}
+
+func readMultipleScheduledTweets(rows *sql.Rows) ([]*model.ScheduledTweet, error) {
+	scheduledTweets := make([]*model.ScheduledTweet, 0)
+
+	for rows.Next() {
+		scheduledTweet, err := readScheduledTweet(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		scheduledTweets = append(scheduledTweets, scheduledTweet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scheduledTweets, nil
+}
+
+func readList(row scannable) (*model.List, error) {
+	var (
+		list    model.List
+		ownerID int64
+	)
+
+	err := row.Scan(
+		&list.ID, &ownerID, &list.Name, &list.Private, &list.CreatedAt,
+		&list.MemberCount, &list.SubscriberCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Owner = &model.PublicUser{ID: ownerID}
+	return &list, nil
+}
+
+func readMultipleLists(rows *sql.Rows) ([]*model.List, error) {
+	lists := make([]*model.List, 0)
+
+	for rows.Next() {
+		list, err := readList(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		lists = append(lists, list)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+// cursorArgs converts cursor to query arguments. Time is NULL for the first
+// page (when cursor is nil).
+func cursorArgs(cursor *model.Cursor) (pq.NullTime, int64) {
+	if cursor == nil {
+		return pq.NullTime{}, 0
+	}
+
+	return pq.NullTime{Time: cursor.Time.UTC(), Valid: true}, cursor.ID
+}
+
+// readIDsPage reads rows of ID and time (eg. of tweet or user) of at most
+// `limit + 1` items. Cursor of the next page is returned only if there are
+// more than `limit` rows.
+func readIDsPage(rows *sql.Rows, limit int) ([]int64, *model.Cursor, error) {
+	positions, nextCursor, err := readPositionsPage(rows, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]int64, 0, len(positions))
+	for _, position := range positions {
+		ids = append(ids, position.ID)
+	}
+
+	return ids, nextCursor, nil
+}
+
+// readPositionsPage works like `readIDsPage` but returns both ID and time of
+// the items.
+func readPositionsPage(rows *sql.Rows, limit int) ([]model.Cursor, *model.Cursor, error) {
+	var (
+		positions = make([]model.Cursor, 0, limit)
+		hasMore   bool
+	)
+
+	for rows.Next() {
+		var item model.Cursor
+
+		if err := rows.Scan(&item.ID, &item.Time); err != nil {
+			return nil, nil, err
+		}
+
+		if len(positions) == limit {
+			hasMore = true
+			break
+		}
+
+		positions = append(positions, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if !hasMore {
+		return positions, nil, nil
+	}
+
+	last := positions[len(positions)-1]
+	return positions, &last, nil
+}
+
+// readRetweetsPage works like `readIDsPage` but reads rows of tweet ID, user ID
+// and time of the retweet.
+func readRetweetsPage(rows *sql.Rows, limit int) ([]*model.Retweet, *model.Cursor, error) {
+	var (
+		retweets = make([]*model.Retweet, 0, limit)
+		last     model.Cursor
+		hasMore  bool
+	)
+
+	for rows.Next() {
+		var retweet model.Retweet
+
+		if err := rows.Scan(&retweet.TweetID, &retweet.UserID, &retweet.RetweetedAt); err != nil {
+			return nil, nil, err
+		}
+
+		if len(retweets) == limit {
+			hasMore = true
+			break
+		}
+
+		retweets = append(retweets, &retweet)
+		last = model.Cursor{Time: retweet.RetweetedAt, ID: retweet.TweetID}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if !hasMore {
+		return retweets, nil, nil
+	}
+
+	return retweets, &last, nil
+}
+
+func readMultipleAnalyticsPoints(rows *sql.Rows) ([]*model.AnalyticsPoint, error) {
+	points := make([]*model.AnalyticsPoint, 0)
+
+	for rows.Next() {
+		var point model.AnalyticsPoint
+
+		err := rows.Scan(&point.Time, &point.Impressions, &point.Likes, &point.Retweets, &point.ProfileClicks)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, &point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/storage/blobstore"
 	"github.com/VirrageS/chirp/backend/storage/cache"
+	"github.com/VirrageS/chirp/backend/storage/counter"
 	"github.com/VirrageS/chirp/backend/storage/database"
 	"github.com/VirrageS/chirp/backend/storage/fulltextsearch"
+	"github.com/VirrageS/chirp/backend/storage/timeline"
 )
 
 // FakeStorage exports all fields which can be necessary for tests.
@@ -15,7 +18,7 @@ type FakeStorage struct {
 }
 
 // NewFakeStorage creates new fake storage necessary for tests.
-func NewFakeStorage(postgresConfig config.PostgresConfigProvider) *FakeStorage {
+func NewFakeStorage(postgresConfig config.PostgresConfigProvider, timelinesConfig config.TimelinesConfigProvider) *FakeStorage {
 	db := database.NewPostgresDatabase(postgresConfig)
 	if db == nil {
 		panic("failed to connect to Postgres instance")
@@ -25,18 +28,45 @@ func NewFakeStorage(postgresConfig config.PostgresConfigProvider) *FakeStorage {
 	tweetsDAO := database.NewTweetDAO(db)
 	followsDAO := database.NewFollowsDAO(db)
 	likesDAO := database.NewLikesDAO(db)
+	retweetsDAO := database.NewRetweetsDAO(db)
+	bookmarksDAO := database.NewBookmarksDAO(db)
+	hashtagsDAO := database.NewHashtagsDAO(db)
+	mentionsDAO := database.NewMentionsDAO(db)
+	mediaDAO := database.NewMediaDAO(db)
+	scheduledTweetsDAO := database.NewScheduledTweetsDAO(db)
+	pollsDAO := database.NewPollsDAO(db)
+	listsDAO := database.NewListsDAO(db)
+	mutesDAO := database.NewMutesDAO(db)
+	analyticsDAO := database.NewAnalyticsDAO(db)
 
 	cache := cache.NewFakeCache() // TODO this shoud be redis...
+	counters := counter.NewFakeBuffer()
+	timelinesStore := timeline.NewFakeStore(timelinesConfig.GetSize())
 	fts := fulltextsearch.NewFakeSearch()
+	blobStore := blobstore.NewFakeBlobStore()
 
-	usersStorage := newUsersStorage(usersDAO, followsDAO, cache, fts)
-	tweetsStorage := newTweetsStorage(tweetsDAO, likesDAO, usersStorage, cache, fts)
+	// tests read timelines right after tweets are created so entries are
+	// pushed synchronously
+	timelinesStorage := newTimelinesStorage(tweetsDAO, retweetsDAO, followsDAO, timelinesStore, timelinesConfig, 0)
+	usersStorage := newUsersStorage(usersDAO, followsDAO, cache, fts, timelinesStorage)
+	mediaStorage := newMediaStorage(mediaDAO, cache, blobStore)
+	pollsStorage := newPollsStorage(pollsDAO, cache)
+	listsStorage := newListsStorage(listsDAO, usersStorage, cache)
+	mutesStorage := newMutesStorage(mutesDAO, usersStorage, cache)
+	analyticsStorage := newAnalyticsStorage(analyticsDAO, counters)
+	tweetsStorage := newTweetsStorage(tweetsDAO, likesDAO, retweetsDAO, bookmarksDAO, hashtagsDAO, mentionsDAO, scheduledTweetsDAO, usersStorage, mediaStorage, pollsStorage, cache, fts, timelinesStorage)
 	return &FakeStorage{
 		Database: db,
 		Cache:    cache,
 		Storage: &storage{
-			usersDataAccessor:  usersStorage,
-			tweetsDataAccessor: tweetsStorage,
+			usersDataAccessor:     usersStorage,
+			tweetsDataAccessor:    tweetsStorage,
+			mediaDataAccessor:     mediaStorage,
+			pollsDataAccessor:     pollsStorage,
+			listsDataAccessor:     listsStorage,
+			mutesDataAccessor:     mutesStorage,
+			analyticsDataAccessor: analyticsStorage,
+			timelines:             timelinesStorage,
 		},
 	}
 }
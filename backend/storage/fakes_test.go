@@ -0,0 +1,348 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/vmihailenco/msgpack.v2"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/storage/cache"
+	"github.com/VirrageS/chirp/backend/storage/database"
+)
+
+// memoryCache is cache.Accessor which keeps marshaled values in memory so
+// values are decoded the same way as the ones read from Redis.
+type memoryCache struct {
+	cache.Accessor
+
+	mutex  sync.Mutex
+	values map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string][]byte)}
+}
+
+func (c *memoryCache) Set(entries ...cache.Entry) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, entry := range entries {
+		data, err := msgpack.Marshal(entry.Value)
+		if err != nil {
+			return err
+		}
+
+		c.values[fmt.Sprint(entry.Key)] = data
+	}
+
+	return nil
+}
+
+func (c *memoryCache) GetSingle(key cache.Key, value cache.Value) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data, ok := c.values[fmt.Sprint(key)]
+	if !ok {
+		return false, nil
+	}
+
+	if err := msgpack.Unmarshal(data, value); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (c *memoryCache) Delete(keys ...cache.Key) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, key := range keys {
+		delete(c.values, fmt.Sprint(key))
+	}
+
+	return nil
+}
+
+// fakeTweetsDAO serves tweets from memory and counts how many times each of
+// them was read. All tweets can be purged, in no particular order.
+type fakeTweetsDAO struct {
+	database.TweetsDAO
+
+	mutex  sync.Mutex
+	tweets map[int64]*model.Tweet
+	reads  map[int64]int
+}
+
+func newFakeTweetsDAO(tweets ...*model.Tweet) *fakeTweetsDAO {
+	dao := &fakeTweetsDAO{
+		tweets: make(map[int64]*model.Tweet),
+		reads:  make(map[int64]int),
+	}
+
+	for _, tweet := range tweets {
+		dao.tweets[tweet.ID] = tweet
+	}
+
+	return dao
+}
+
+func (dao *fakeTweetsDAO) GetTweetByID(tweetID int64) (*model.Tweet, error) {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	dao.reads[tweetID]++
+	tweet, ok := dao.tweets[tweetID]
+	if !ok {
+		return nil, errors.NoResultsError
+	}
+
+	copied := *tweet
+	copied.Author = &model.PublicUser{ID: tweet.Author.ID}
+	return &copied, nil
+}
+
+func (dao *fakeTweetsDAO) GetReplyCount(tweetID int64) (int64, error) {
+	return 0, nil
+}
+
+func (dao *fakeTweetsDAO) GetTweetsIDsToPurge(window time.Duration, limit int, excludedIDs []int64) ([]int64, error) {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	excluded := make(map[int64]bool, len(excludedIDs))
+	for _, tweetID := range excludedIDs {
+		excluded[tweetID] = true
+	}
+
+	tweetsIDs := make([]int64, 0, len(dao.tweets))
+	for tweetID := range dao.tweets {
+		if !excluded[tweetID] && len(tweetsIDs) < limit {
+			tweetsIDs = append(tweetsIDs, tweetID)
+		}
+	}
+
+	return tweetsIDs, nil
+}
+
+func (dao *fakeTweetsDAO) PurgeTweet(tweetID int64) (bool, error) {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	_, ok := dao.tweets[tweetID]
+	delete(dao.tweets, tweetID)
+	return ok, nil
+}
+
+func (dao *fakeTweetsDAO) readCount(tweetID int64) int {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	return dao.reads[tweetID]
+}
+
+// fakeUsersDAO serves users from memory and counts how many times each of
+// them was read.
+type fakeUsersDAO struct {
+	database.UsersDAO
+
+	mutex sync.Mutex
+	users map[int64]*model.PublicUser
+	reads map[int64]int
+}
+
+func newFakeUsersDAO(users ...*model.PublicUser) *fakeUsersDAO {
+	dao := &fakeUsersDAO{
+		users: make(map[int64]*model.PublicUser),
+		reads: make(map[int64]int),
+	}
+
+	for _, user := range users {
+		dao.users[user.ID] = user
+	}
+
+	return dao
+}
+
+func (dao *fakeUsersDAO) GetPublicUserByID(userID int64) (*model.PublicUser, error) {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	dao.reads[userID]++
+	user, ok := dao.users[userID]
+	if !ok {
+		return nil, errors.NoResultsError
+	}
+
+	copied := *user
+	return &copied, nil
+}
+
+func (dao *fakeUsersDAO) UnpinTweet(userID, tweetID int64) (bool, error) {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	user, ok := dao.users[userID]
+	if !ok || user.PinnedTweetID != tweetID {
+		return false, nil
+	}
+
+	user.PinnedTweetID = 0
+	return true, nil
+}
+
+func (dao *fakeUsersDAO) readCount(userID int64) int {
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	return dao.reads[userID]
+}
+
+type fakeLikesDAO struct {
+	database.LikesDAO
+}
+
+func (dao *fakeLikesDAO) GetLikeCount(tweetID int64) (int64, error) {
+	return 0, nil
+}
+
+func (dao *fakeLikesDAO) IsLiked(tweetID, userID int64) (bool, error) {
+	return false, nil
+}
+
+type fakeRetweetsDAO struct {
+	database.RetweetsDAO
+}
+
+func (dao *fakeRetweetsDAO) GetRetweetCount(tweetID int64) (int64, error) {
+	return 0, nil
+}
+
+func (dao *fakeRetweetsDAO) IsRetweeted(tweetID, userID int64) (bool, error) {
+	return false, nil
+}
+
+type fakeBookmarksDAO struct {
+	database.BookmarksDAO
+}
+
+func (dao *fakeBookmarksDAO) IsBookmarked(tweetID, userID int64) (bool, error) {
+	return false, nil
+}
+
+type fakeMentionsDAO struct {
+	database.MentionsDAO
+}
+
+func (dao *fakeMentionsDAO) GetTweetMentions(tweetID int64) ([]*model.Mention, error) {
+	return []*model.Mention{}, nil
+}
+
+// fakeUsersStorage knows users only by their IDs. `followees` maps followers
+// to users they follow.
+type fakeUsersStorage struct {
+	usersDataAccessor
+
+	followees map[int64][]int64
+}
+
+func (s *fakeUsersStorage) GetUserByID(userID, requestingUserID int64) (*model.PublicUser, error) {
+	return &model.PublicUser{ID: userID}, nil
+}
+
+func (s *fakeUsersStorage) IsFollowing(followerID, followeeID int64) (bool, error) {
+	for _, id := range s.followees[followerID] {
+		if id == followeeID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// fakeMediaStorage keeps media of the tweets and keys of their blobs. Blobs
+// with keys in `failing` can not be deleted.
+type fakeMediaStorage struct {
+	mediaDataAccessor
+
+	media   map[int64][]*model.Media
+	blobs   map[string]bool
+	failing map[string]bool
+}
+
+func (s *fakeMediaStorage) GetTweetMedia(tweetID int64) ([]*model.Media, error) {
+	if media, ok := s.media[tweetID]; ok {
+		return media, nil
+	}
+
+	return []*model.Media{}, nil
+}
+
+func (s *fakeMediaStorage) DeleteMediaBlobs(media []*model.Media) error {
+	var err error
+	for _, m := range media {
+		if s.failing[m.Key] {
+			err = errors.UnexpectedError
+			continue
+		}
+		delete(s.blobs, m.Key)
+	}
+
+	return err
+}
+
+type fakePollsStorage struct {
+	pollsDataAccessor
+}
+
+func (s *fakePollsStorage) GetPoll(tweetID, requestingUserID int64) (*model.Poll, error) {
+	return nil, nil
+}
+
+// fakeFollowsDAO counts followers only after `release` is closed so tests
+// can tell whether the caller waits for it. First `failures` counts fail.
+type fakeFollowsDAO struct {
+	database.FollowsDAO
+
+	mutex     sync.Mutex
+	followers map[int64][]int64
+	release   chan struct{}
+	failures  int
+}
+
+func (dao *fakeFollowsDAO) GetFollowerCount(userID int64) (int64, error) {
+	<-dao.release
+
+	dao.mutex.Lock()
+	defer dao.mutex.Unlock()
+
+	if dao.failures > 0 {
+		dao.failures--
+		return 0, errors.UnexpectedError
+	}
+
+	return int64(len(dao.followers[userID])), nil
+}
+
+func (dao *fakeFollowsDAO) GetFolloweeCount(userID int64) (int64, error) {
+	return 0, nil
+}
+
+func (dao *fakeFollowsDAO) IsFollowing(followerID, followeeID int64) (bool, error) {
+	return false, nil
+}
+
+func (dao *fakeFollowsDAO) GetAllFollowersIDs(userID int64) ([]int64, error) {
+	return dao.followers[userID], nil
+}
+
+type fakeTimelinesConfig struct{}
+
+func (fakeTimelinesConfig) GetSize() int                     { return 10 }
+func (fakeTimelinesConfig) GetFanoutLimit() int              { return 100 }
+func (fakeTimelinesConfig) GetExpirationTime() time.Duration { return time.Hour }
@@ -3,10 +3,13 @@ package fulltextsearch
 import (
 	"context"
 	"encoding/json"
+	"strconv"
+	"time"
 
 	"fmt"
 	log "github.com/Sirupsen/logrus"
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
 	"gopkg.in/olivere/elastic.v5"
 )
 
@@ -19,6 +22,12 @@ const (
 	userType          = "user"
 	userNameField     = "name"
 	userUsernameField = "username"
+
+	createdAtField = "created_at"
+	idField        = "id"
+
+	// format of dates which is understood by Elasticsearch by default
+	dateFormat = "2006-01-02T15:04:05.000Z07:00"
 )
 
 type elasticsearchClient struct {
@@ -45,47 +54,117 @@ func NewElasticsearchSearch(config config.ElasticsearchConfigProvider) Searcher
 	return &elasticsearchClient{client}
 }
 
-func (e *elasticsearchClient) GetTweetsIDs(querystring string) ([]int64, error) {
-	return e.getIDsFromIndex(querystring, tweetType, tweetContentField)
+func (e *elasticsearchClient) GetTweetsIDs(querystring string, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	return e.getIDsFromIndex(querystring, cursor, limit, tweetType, tweetContentField)
 }
 
-func (e *elasticsearchClient) GetUsersIDs(querystring string) ([]int64, error) {
-	return e.getIDsFromIndex(querystring, userType, userUsernameField, userNameField)
+// IndexTweet creates or replaces document of the tweet. Documents have the same
+// format as the ones created by logstash.
+func (e *elasticsearchClient) IndexTweet(tweetID int64, content string, createdAt time.Time) error {
+	document := map[string]interface{}{
+		idField:           tweetID,
+		tweetContentField: content,
+		createdAtField:    createdAt.UTC().Format(dateFormat),
+	}
+
+	_, err := e.Index().
+		Index(indexName).
+		Type(tweetType).
+		Id(strconv.FormatInt(tweetID, 10)).
+		BodyJson(document).
+		Do(context.Background())
+
+	if err != nil {
+		log.WithField("tweetID", tweetID).WithError(err).Error("Error indexing tweet in elasticsearch.")
+		return err
+	}
+
+	return nil
+}
+
+func (e *elasticsearchClient) GetUsersIDs(querystring string, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	return e.getIDsFromIndex(querystring, cursor, limit, userType, userUsernameField, userNameField)
 }
 
-func (e *elasticsearchClient) getIDsFromIndex(querystring, typeName string, fields ...string) ([]int64, error) {
+// getIDsFromIndex returns IDs of at most `limit` matching documents, newest
+// first, which come after the `cursor` (nil means the first page). Returned
+// cursor is nil if there are no more documents.
+func (e *elasticsearchClient) getIDsFromIndex(querystring string, cursor *model.Cursor, limit int, typeName string, fields ...string) ([]int64, *model.Cursor, error) {
 	// Creates a MatchQuery with "and" operator - a query that will require
 	// each word in `querystring` to be matched in one of the `fields`.
 	query := elastic.NewMultiMatchQuery(querystring, fields...).Operator("and")
 
-	searchResult, err := e.Search().
+	// one additional document is fetched to know if there is a next page
+	search := e.Search().
 		Index(indexName).
 		Type(typeName).
 		Query(query).
-		Do(context.Background())
+		Sort(createdAtField, false).
+		Sort(idField, false).
+		Size(limit + 1)
+
+	if cursor != nil {
+		// dates are sorted with millisecond precision
+		search = search.SearchAfter(cursor.Time.UnixNano()/int64(time.Millisecond), cursor.ID)
+	}
 
+	searchResult, err := search.Do(context.Background())
 	if err != nil {
 		log.WithError(err).Error("Error querying elasticsearch in getIDsFromIndex.")
-		return nil, err
+		return nil, nil, err
 	}
 
-	ids := make([]int64, searchResult.Hits.TotalHits)
+	var (
+		ids  = make([]int64, 0, limit)
+		last model.Cursor
+	)
+
+	for _, hit := range searchResult.Hits.Hits {
+		if len(ids) == limit {
+			return ids, &last, nil
+		}
 
-	for i, hit := range searchResult.Hits.Hits {
-		var id struct {
+		var document struct {
 			ID int64 `json:"id" binding:"required"`
 		}
 
-		err := json.Unmarshal(*hit.Source, &id)
+		err := json.Unmarshal(*hit.Source, &document)
 		if err != nil {
 			log.WithError(err).Error("Error umarshalling elasticsearch response in getIDsFromIndex.")
-			return nil, err
+			return nil, nil, err
 		}
 
-		ids[i] = id.ID
+		last, err = cursorFromSortValues(hit.Sort)
+		if err != nil {
+			log.WithError(err).Error("Error reading sort values of elasticsearch response in getIDsFromIndex.")
+			return nil, nil, err
+		}
+
+		ids = append(ids, document.ID)
+	}
+
+	return ids, nil, nil
+}
+
+// cursorFromSortValues creates cursor from sort values of the hit which are
+// creation time (in milliseconds) and ID of the document.
+func cursorFromSortValues(values []interface{}) (model.Cursor, error) {
+	if len(values) != 2 {
+		return model.Cursor{}, fmt.Errorf("expected 2 sort values, got %d", len(values))
+	}
+
+	milliseconds, ok := values[0].(float64)
+	if !ok {
+		return model.Cursor{}, fmt.Errorf("invalid creation time sort value: %v", values[0])
+	}
+
+	id, ok := values[1].(float64)
+	if !ok {
+		return model.Cursor{}, fmt.Errorf("invalid ID sort value: %v", values[1])
 	}
 
-	// Results from elasticsearch are sorted by score by default.
-	// We do not need to sort them ourselves.
-	return ids, nil
+	return model.Cursor{
+		Time: time.Unix(0, int64(milliseconds)*int64(time.Millisecond)).UTC(),
+		ID:   int64(id),
+	}, nil
 }
@@ -1,5 +1,11 @@
 package fulltextsearch
 
+import (
+	"time"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
 type fakeSearch struct{}
 
 // NewFakeSearch creates new instance of fake searcher which imitates searching values.
@@ -8,10 +14,14 @@ func NewFakeSearch() Searcher {
 	return &fakeSearch{}
 }
 
-func (d *fakeSearch) GetTweetsIDs(querystring string) ([]int64, error) {
-	return nil, nil
+func (d *fakeSearch) GetTweetsIDs(querystring string, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	return nil, nil, nil
+}
+
+func (d *fakeSearch) IndexTweet(tweetID int64, content string, createdAt time.Time) error {
+	return nil
 }
 
-func (d *fakeSearch) GetUsersIDs(querystring string) ([]int64, error) {
-	return nil, nil
+func (d *fakeSearch) GetUsersIDs(querystring string, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error) {
+	return nil, nil, nil
 }
@@ -1,15 +1,22 @@
 package fulltextsearch
 
+import (
+	"time"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
 // TweetsSearcher is interface which defines all full text search functions which
 // are connected with tweets.
 type TweetsSearcher interface {
-	GetTweetsIDs(querystring string) ([]int64, error)
+	GetTweetsIDs(querystring string, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
+	IndexTweet(tweetID int64, content string, createdAt time.Time) error
 }
 
 // UsersSearcher is interface which defines all full text search functions which
 // are connected with users.
 type UsersSearcher interface {
-	GetUsersIDs(querystring string) ([]int64, error)
+	GetUsersIDs(querystring string, cursor *model.Cursor, limit int) ([]int64, *model.Cursor, error)
 }
 
 // Searcher is interface which defines all full text search functions used in system.
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/storage/cache"
+	"github.com/VirrageS/chirp/backend/storage/database"
+)
+
+// listsStorage is struct which implements listsDataAccessor using given DAO, cache and users storage
+type listsStorage struct {
+	listsDAO     database.ListsDAO
+	usersStorage usersDataAccessor
+	cache        cache.Accessor
+}
+
+// newListsStorage constructs listsStorage that uses given listsDAO, usersDataAccessor and cache Accessor
+func newListsStorage(listsDAO database.ListsDAO, usersStorage usersDataAccessor, cache cache.Accessor) listsDataAccessor {
+	return &listsStorage{
+		listsDAO:     listsDAO,
+		usersStorage: usersStorage,
+		cache:        cache,
+	}
+}
+
+func (s *listsStorage) InsertList(newList *model.NewList) (*model.List, error) {
+	list, err := s.listsDAO.InsertList(newList)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	err = s.collectListsData([]*model.List{list}, newList.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetList returns the list regardless of whether it is private - checking if
+// the requesting user can see it is up to the caller.
+func (s *listsStorage) GetList(listID, requestingUserID int64) (*model.List, error) {
+	list, err := s.listsDAO.GetListByID(listID)
+	if err == errors.NoResultsError {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	err = s.collectListsData([]*model.List{list}, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *listsStorage) DeleteList(listID int64) error {
+	err := s.listsDAO.DeleteList(listID)
+	if err == errors.NoResultsError {
+		return err
+	} else if err != nil {
+		return errors.UnexpectedError
+	}
+
+	s.cache.Delete(cache.Key{"list", listID, "members.ids"})
+	return nil
+}
+
+// GetListsByOwnerID returns all lists of the owner, including private ones.
+func (s *listsStorage) GetListsByOwnerID(ownerID, requestingUserID int64) ([]*model.List, error) {
+	lists, err := s.listsDAO.GetListsByOwnerID(ownerID)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	err = s.collectListsData(lists, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (s *listsStorage) GetSubscribedLists(userID int64) ([]*model.List, error) {
+	lists, err := s.listsDAO.GetSubscribedLists(userID)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	err = s.collectListsData(lists, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+func (s *listsStorage) AddListMember(listID, userID int64) error {
+	added, err := s.listsDAO.AddListMember(listID, userID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if added {
+		s.cache.Delete(cache.Key{"list", listID, "members.ids"})
+	}
+
+	return nil
+}
+
+func (s *listsStorage) RemoveListMember(listID, userID int64) error {
+	removed, err := s.listsDAO.RemoveListMember(listID, userID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if removed {
+		s.cache.Delete(cache.Key{"list", listID, "members.ids"})
+	}
+
+	return nil
+}
+
+// GetListMembers returns single page of members of the list, most recently
+// added first.
+func (s *listsStorage) GetListMembers(listID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	membersIDs, nextCursor, err := s.listsDAO.GetListMembersIDs(listID, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
+
+	members, err := s.usersStorage.GetUsersByIDs(membersIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
+
+	return sortUsersByIDs(members, membersIDs), nextCursor, nil
+}
+
+func (s *listsStorage) GetListMembersIDs(listID int64) ([]int64, error) {
+	membersIDs := make([]int64, 0)
+
+	key := cache.Key{"list", listID, "members.ids"}
+	if exists, _ := s.cache.SMembers(key, &membersIDs); !exists {
+		var err error
+
+		membersIDs, err = s.listsDAO.GetAllListMembersIDs(listID)
+		if err != nil {
+			return nil, errors.UnexpectedError
+		}
+
+		s.cache.SAdd(key, membersIDs)
+	}
+
+	return membersIDs, nil
+}
+
+func (s *listsStorage) SubscribeList(listID, userID int64) error {
+	_, err := s.listsDAO.SubscribeList(listID, userID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	return nil
+}
+
+func (s *listsStorage) UnsubscribeList(listID, userID int64) error {
+	_, err := s.listsDAO.UnsubscribeList(listID, userID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	return nil
+}
+
+// collectListsData fills owners of the lists and tells if the requesting user
+// subscribes them.
+func (s *listsStorage) collectListsData(lists []*model.List, requestingUserID int64) error {
+	ownersIDs := make([]int64, 0, len(lists))
+	for _, list := range lists {
+		ownersIDs = append(ownersIDs, list.Owner.ID)
+	}
+
+	owners, err := s.usersStorage.GetUsersByIDs(ownersIDs, requestingUserID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	ownersByID := make(map[int64]*model.PublicUser, len(owners))
+	for _, owner := range owners {
+		ownersByID[owner.ID] = owner
+	}
+
+	for _, list := range lists {
+		if owner, ok := ownersByID[list.Owner.ID]; ok {
+			list.Owner = owner
+		}
+
+		list.Subscribed, err = s.listsDAO.IsSubscribed(list.ID, requestingUserID)
+		if err != nil {
+			return errors.UnexpectedError
+		}
+	}
+
+	return nil
+}
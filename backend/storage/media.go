@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/storage/blobstore"
+	"github.com/VirrageS/chirp/backend/storage/cache"
+	"github.com/VirrageS/chirp/backend/storage/database"
+)
+
+// Length (in bytes) of random part of the blob key.
+const mediaKeyLength = 16
+
+// mediaStorage is struct which implements mediaDataAccessor using given DAO, cache and blob store
+type mediaStorage struct {
+	mediaDAO  database.MediaDAO
+	cache     cache.Accessor
+	blobStore blobstore.BlobStore
+}
+
+// newMediaStorage constructs mediaStorage that uses given mediaDAO, cache Accessor and BlobStore
+func newMediaStorage(mediaDAO database.MediaDAO, cache cache.Accessor, blobStore blobstore.BlobStore) mediaDataAccessor {
+	return &mediaStorage{
+		mediaDAO:  mediaDAO,
+		cache:     cache,
+		blobStore: blobStore,
+	}
+}
+
+func (s *mediaStorage) InsertMedia(newMedia *model.NewMedia) (*model.Media, error) {
+	key, err := generateMediaKey()
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	err = s.blobStore.Put(key, newMedia.Data)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	media, err := s.mediaDAO.InsertMedia(&model.Media{
+		OwnerID:  newMedia.OwnerID,
+		Key:      key,
+		MimeType: newMedia.MimeType,
+		Width:    newMedia.Width,
+		Height:   newMedia.Height,
+		Size:     int64(len(newMedia.Data)),
+	})
+	if err != nil {
+		s.blobStore.Delete(key)
+		return nil, errors.UnexpectedError
+	}
+
+	media.URL = s.blobStore.URL(media.Key)
+	return media, nil
+}
+
+func (s *mediaStorage) GetMediaByIDs(mediaIDs []int64) ([]*model.Media, error) {
+	media, err := s.mediaDAO.GetMediaByIDs(mediaIDs)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	for _, m := range media {
+		m.URL = s.blobStore.URL(m.Key)
+	}
+
+	return media, nil
+}
+
+func (s *mediaStorage) GetMediaByKey(key string) (*model.Media, error) {
+	media, err := s.mediaDAO.GetMediaByKey(key)
+	if err == errors.NoResultsError {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	media.URL = s.blobStore.URL(media.Key)
+	return media, nil
+}
+
+// GetMediaBlob returns uploaded data of the media.
+func (s *mediaStorage) GetMediaBlob(media *model.Media) ([]byte, error) {
+	data, err := s.blobStore.Get(media.Key)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	return data, nil
+}
+
+func (s *mediaStorage) GetTweetMedia(tweetID int64) ([]*model.Media, error) {
+	media := make([]*model.Media, 0)
+
+	key := cache.Key{"tweet", tweetID, "media"}
+	if exists, _ := s.cache.GetSingle(key, &media); !exists {
+		var err error
+
+		media, err = s.mediaDAO.GetTweetMedia(tweetID)
+		if err != nil {
+			return nil, errors.UnexpectedError
+		}
+
+		for _, m := range media {
+			m.URL = s.blobStore.URL(m.Key)
+		}
+
+		s.cache.Set(cache.Entry{key, media})
+	}
+
+	return media, nil
+}
+
+// DeleteMediaBlobs deletes blobs of the media. All blobs are tried even if
+// some of them could not be deleted. Deleting blob which does not exist is
+// not an error so failed deletes can be retried.
+func (s *mediaStorage) DeleteMediaBlobs(media []*model.Media) error {
+	var deleteErr error
+	for _, m := range media {
+		if err := s.blobStore.Delete(m.Key); err != nil {
+			log.WithField("media", *m).WithError(err).Error("Failed to delete media blob.")
+			deleteErr = errors.UnexpectedError
+		}
+
+		s.cache.Delete(cache.Key{"tweet", m.TweetID, "media"})
+	}
+
+	return deleteErr
+}
+
+func generateMediaKey() (string, error) {
+	bytes := make([]byte, mediaKeyLength)
+	if _, err := rand.Read(bytes); err != nil {
+		log.WithError(err).Error("Failed to generate media key.")
+		return "", err
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
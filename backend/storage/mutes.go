@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/storage/cache"
+	"github.com/VirrageS/chirp/backend/storage/database"
+)
+
+// mutesStorage is struct which implements mutesDataAccessor using given DAO, cache and users storage
+type mutesStorage struct {
+	mutesDAO     database.MutesDAO
+	usersStorage usersDataAccessor
+	cache        cache.Accessor
+}
+
+// newMutesStorage constructs mutesStorage that uses given mutesDAO, usersDataAccessor and cache Accessor
+func newMutesStorage(mutesDAO database.MutesDAO, usersStorage usersDataAccessor, cache cache.Accessor) mutesDataAccessor {
+	return &mutesStorage{
+		mutesDAO:     mutesDAO,
+		usersStorage: usersStorage,
+		cache:        cache,
+	}
+}
+
+func (s *mutesStorage) MuteUser(userID, mutedUserID int64) error {
+	muted, err := s.mutesDAO.MuteUser(userID, mutedUserID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if muted {
+		s.cache.Delete(cache.Key{"user", userID, "muted.ids"})
+	}
+
+	return nil
+}
+
+func (s *mutesStorage) UnmuteUser(userID, mutedUserID int64) error {
+	unmuted, err := s.mutesDAO.UnmuteUser(userID, mutedUserID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if unmuted {
+		s.cache.Delete(cache.Key{"user", userID, "muted.ids"})
+	}
+
+	return nil
+}
+
+// GetMutedUsers returns all users muted by the user, most recently muted first.
+func (s *mutesStorage) GetMutedUsers(userID int64) ([]*model.PublicUser, error) {
+	mutedUsersIDs, err := s.mutesDAO.GetAllMutedUsersIDs(userID)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	mutedUsers, err := s.usersStorage.GetUsersByIDs(mutedUsersIDs, userID)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	return sortUsersByIDs(mutedUsers, mutedUsersIDs), nil
+}
+
+func (s *mutesStorage) GetMutedUsersIDs(userID int64) ([]int64, error) {
+	mutedUsersIDs := make([]int64, 0)
+
+	key := cache.Key{"user", userID, "muted.ids"}
+	if exists, _ := s.cache.SMembers(key, &mutedUsersIDs); !exists {
+		var err error
+
+		mutedUsersIDs, err = s.mutesDAO.GetAllMutedUsersIDs(userID)
+		if err != nil {
+			return nil, errors.UnexpectedError
+		}
+
+		s.cache.SAdd(key, mutedUsersIDs)
+	}
+
+	return mutedUsersIDs, nil
+}
+
+func (s *mutesStorage) MuteWord(userID int64, word string) error {
+	muted, err := s.mutesDAO.MuteWord(userID, word)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if muted {
+		s.cache.Delete(cache.Key{"user", userID, "muted.words"})
+	}
+
+	return nil
+}
+
+func (s *mutesStorage) UnmuteWord(userID int64, word string) error {
+	unmuted, err := s.mutesDAO.UnmuteWord(userID, word)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if unmuted {
+		s.cache.Delete(cache.Key{"user", userID, "muted.words"})
+	}
+
+	return nil
+}
+
+// GetMutedWords returns all words muted by the user in no particular order.
+func (s *mutesStorage) GetMutedWords(userID int64) ([]string, error) {
+	words := make([]string, 0)
+
+	key := cache.Key{"user", userID, "muted.words"}
+	if exists, _ := s.cache.SMembers(key, &words); !exists {
+		var err error
+
+		words, err = s.mutesDAO.GetAllMutedWords(userID)
+		if err != nil {
+			return nil, errors.UnexpectedError
+		}
+
+		s.cache.SAdd(key, words)
+	}
+
+	return words, nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/storage/cache"
+	"github.com/VirrageS/chirp/backend/storage/database"
+)
+
+// pollsStorage is struct which implements pollsDataAccessor using given DAO and cache
+type pollsStorage struct {
+	pollsDAO database.PollsDAO
+	cache    cache.Accessor
+}
+
+// newPollsStorage constructs pollsStorage that uses given pollsDAO and cache Accessor
+func newPollsStorage(pollsDAO database.PollsDAO, cache cache.Accessor) pollsDataAccessor {
+	return &pollsStorage{
+		pollsDAO: pollsDAO,
+		cache:    cache,
+	}
+}
+
+// GetPoll returns poll attached to the tweet together with vote counts and
+// vote of the requesting user. When tweet does not have poll nil is returned.
+func (s *pollsStorage) GetPoll(tweetID, requestingUserID int64) (*model.Poll, error) {
+	var (
+		poll       model.Poll
+		voteCounts = make([]int64, 0)
+		vote       int
+	)
+
+	// tweets without poll are cached as poll without options
+	key := cache.Key{"tweet", tweetID, "poll"}
+	if exists, _ := s.cache.GetSingle(key, &poll); !exists {
+		dbPoll, err := s.pollsDAO.GetPoll(tweetID)
+		if err == errors.NoResultsError {
+			dbPoll = &model.Poll{}
+		} else if err != nil {
+			return nil, errors.UnexpectedError
+		}
+
+		poll = *dbPoll
+		s.cache.Set(cache.Entry{key, poll})
+	}
+
+	if len(poll.Options) == 0 {
+		return nil, nil
+	}
+
+	// Counters are kept separately for each option so voting can simply
+	// increment them. If any of them is missing all are fetched again.
+	for option := range poll.Options {
+		var voteCount int64
+
+		key := cache.Key{"tweet", tweetID, "poll.option", option, "vote.count"}
+		if exists, _ := s.cache.GetSingle(key, &voteCount); !exists {
+			break
+		}
+
+		voteCounts = append(voteCounts, voteCount)
+	}
+
+	if len(voteCounts) != len(poll.Options) {
+		var err error
+
+		voteCounts, err = s.pollsDAO.GetPollVoteCounts(tweetID)
+		if err != nil || len(voteCounts) != len(poll.Options) {
+			return nil, errors.UnexpectedError
+		}
+
+		for option, voteCount := range voteCounts {
+			s.cache.Set(cache.Entry{cache.Key{"tweet", tweetID, "poll.option", option, "vote.count"}, voteCount})
+		}
+	}
+
+	key = cache.Key{"tweet", tweetID, "poll.voted.by", requestingUserID}
+	if exists, _ := s.cache.GetSingle(key, &vote); !exists {
+		var err error
+
+		vote, err = s.pollsDAO.GetPollVote(tweetID, requestingUserID)
+		if err != nil {
+			return nil, errors.UnexpectedError
+		}
+
+		s.cache.Set(cache.Entry{key, vote})
+	}
+
+	for option, voteCount := range voteCounts {
+		poll.Options[option].VoteCount = voteCount
+	}
+
+	if vote >= 0 {
+		poll.Vote = &vote
+	}
+
+	poll.Closed = !time.Now().Before(poll.ClosesAt)
+	return &poll, nil
+}
+
+func (s *pollsStorage) VotePoll(tweetID, userID int64, option int) (bool, error) {
+	voted, err := s.pollsDAO.VotePoll(tweetID, userID, option)
+	if err != nil {
+		return false, errors.UnexpectedError
+	}
+
+	if voted {
+		s.cache.Incr(cache.Key{"tweet", tweetID, "poll.option", option, "vote.count"})
+		s.cache.Set(
+			cache.Entry{cache.Key{"tweet", tweetID, "poll.voted.by", userID}, option},
+		)
+	}
+
+	return voted, nil
+}
@@ -2,18 +2,34 @@ package storage
 
 import (
 	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/storage/blobstore"
 	"github.com/VirrageS/chirp/backend/storage/cache"
+	"github.com/VirrageS/chirp/backend/storage/counter"
 	"github.com/VirrageS/chirp/backend/storage/database"
 	"github.com/VirrageS/chirp/backend/storage/fulltextsearch"
+	"github.com/VirrageS/chirp/backend/storage/timeline"
 )
 
 type storage struct {
 	usersDataAccessor
 	tweetsDataAccessor
+	mediaDataAccessor
+	pollsDataAccessor
+	listsDataAccessor
+	mutesDataAccessor
+	analyticsDataAccessor
+
+	timelines *timelinesStorage
+}
+
+// Close waits until changes of timelines queued in the background are
+// applied.
+func (s *storage) Close() {
+	s.timelines.Close()
 }
 
 // New constructs Accessor that TODO
-func New(postgresConfig config.PostgresConfigProvider, redisConfig config.RedisConfigProvider, elasticsearchConfig config.ElasticsearchConfigProvider) Accessor {
+func New(postgresConfig config.PostgresConfigProvider, redisConfig config.RedisConfigProvider, elasticsearchConfig config.ElasticsearchConfigProvider, mediaConfig config.MediaConfigProvider, timelinesConfig config.TimelinesConfigProvider) Accessor {
 	db := database.NewPostgresDatabase(postgresConfig)
 	if db == nil {
 		panic("failed to connect to Postgres instance")
@@ -23,21 +39,58 @@ func New(postgresConfig config.PostgresConfigProvider, redisConfig config.RedisC
 	tweetsDAO := database.NewTweetDAO(db)
 	followsDAO := database.NewFollowsDAO(db)
 	likesDAO := database.NewLikesDAO(db)
+	retweetsDAO := database.NewRetweetsDAO(db)
+	bookmarksDAO := database.NewBookmarksDAO(db)
+	hashtagsDAO := database.NewHashtagsDAO(db)
+	mentionsDAO := database.NewMentionsDAO(db)
+	mediaDAO := database.NewMediaDAO(db)
+	scheduledTweetsDAO := database.NewScheduledTweetsDAO(db)
+	pollsDAO := database.NewPollsDAO(db)
+	listsDAO := database.NewListsDAO(db)
+	mutesDAO := database.NewMutesDAO(db)
+	analyticsDAO := database.NewAnalyticsDAO(db)
 
 	cache := cache.NewRedisCache(redisConfig)
 	if cache == nil {
 		panic("failed to connect to Redis instance")
 	}
 
+	counters := counter.NewRedisBuffer(redisConfig, "analytics.counters")
+	if counters == nil {
+		panic("failed to connect to Redis instance")
+	}
+
+	timelinesStore := timeline.NewRedisStore(redisConfig, timelinesConfig.GetSize(), timelinesConfig.GetExpirationTime())
+	if timelinesStore == nil {
+		panic("failed to connect to Redis instance")
+	}
+
 	fts := fulltextsearch.NewElasticsearchSearch(elasticsearchConfig)
 	if fts == nil {
 		panic("failed to connect to Elasticsearch instance")
 	}
 
-	usersStorage := newUsersStorage(usersDAO, followsDAO, cache, fts)
-	tweetsStorage := newTweetsStorage(tweetsDAO, likesDAO, usersStorage, cache, fts)
+	blobStore := blobstore.NewLocalBlobStore(mediaConfig)
+	if blobStore == nil {
+		panic("failed to create blob store")
+	}
+
+	timelinesStorage := newTimelinesStorage(tweetsDAO, retweetsDAO, followsDAO, timelinesStore, timelinesConfig, fanoutWorkers)
+	usersStorage := newUsersStorage(usersDAO, followsDAO, cache, fts, timelinesStorage)
+	mediaStorage := newMediaStorage(mediaDAO, cache, blobStore)
+	pollsStorage := newPollsStorage(pollsDAO, cache)
+	listsStorage := newListsStorage(listsDAO, usersStorage, cache)
+	mutesStorage := newMutesStorage(mutesDAO, usersStorage, cache)
+	analyticsStorage := newAnalyticsStorage(analyticsDAO, counters)
+	tweetsStorage := newTweetsStorage(tweetsDAO, likesDAO, retweetsDAO, bookmarksDAO, hashtagsDAO, mentionsDAO, scheduledTweetsDAO, usersStorage, mediaStorage, pollsStorage, cache, fts, timelinesStorage)
 	return &storage{
-		usersDataAccessor:  usersStorage,
-		tweetsDataAccessor: tweetsStorage,
+		usersDataAccessor:     usersStorage,
+		tweetsDataAccessor:    tweetsStorage,
+		mediaDataAccessor:     mediaStorage,
+		pollsDataAccessor:     pollsStorage,
+		listsDataAccessor:     listsStorage,
+		mutesDataAccessor:     mutesStorage,
+		analyticsDataAccessor: analyticsStorage,
+		timelines:             timelinesStorage,
 	}
 }
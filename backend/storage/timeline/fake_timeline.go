@@ -0,0 +1,180 @@
+package timeline
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+type fakeTimeline struct {
+	entries  []Entry
+	complete bool
+}
+
+type fakeStore struct {
+	mutex     sync.Mutex
+	size      int
+	timelines map[int64]*fakeTimeline
+	popular   map[int64]bool
+}
+
+// NewFakeStore creates new instance of fake store which keeps all timelines
+// in memory. Timelines are limited to `size` members the same way as in Redis
+// store so the tests see the same behaviour.
+func NewFakeStore(size int) Store {
+	return &fakeStore{
+		size:      size,
+		timelines: make(map[int64]*fakeTimeline),
+		popular:   make(map[int64]bool),
+	}
+}
+
+func (store *fakeStore) Push(usersIDs []int64, entry Entry) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, userID := range usersIDs {
+		if timeline, ok := store.timelines[userID]; ok {
+			timeline.entries = append(removeEntries(timeline.entries, entry), entry)
+			store.trim(timeline)
+		}
+	}
+
+	return nil
+}
+
+func (store *fakeStore) Get(userID int64, cursor *model.Cursor, limit int) (*Page, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	timeline, ok := store.timelines[userID]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entries := make([]Entry, 0, limit)
+	for _, entry := range timeline.entries {
+		if cursor == nil || isBefore(*cursor, entry.Position()) {
+			entries = append(entries, entry)
+		}
+	}
+
+	entries, nextCursor := cut(entries, limit)
+	return &Page{
+		Entries:    entries,
+		NextCursor: nextCursor,
+	}, timeline.complete && nextCursor == nil, nil
+}
+
+func (store *fakeStore) Count(userID int64, since model.Cursor) (int, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	timeline, ok := store.timelines[userID]
+	if !ok {
+		return 0, false, nil
+	}
+
+	count := 0
+	for _, entry := range timeline.entries {
+		if isBefore(entry.Position(), since) {
+			count++
+		}
+	}
+
+	return count, true, nil
+}
+
+func (store *fakeStore) Set(userID int64, entries []Entry, complete bool) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	timeline := &fakeTimeline{
+		entries:  append([]Entry(nil), entries...),
+		complete: complete,
+	}
+	store.trim(timeline)
+
+	store.timelines[userID] = timeline
+	return nil
+}
+
+func (store *fakeStore) Remove(usersIDs []int64, entries ...Entry) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, userID := range usersIDs {
+		if timeline, ok := store.timelines[userID]; ok {
+			timeline.entries = removeEntries(timeline.entries, entries...)
+		}
+	}
+
+	return nil
+}
+
+func (store *fakeStore) Delete(usersIDs ...int64) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, userID := range usersIDs {
+		delete(store.timelines, userID)
+	}
+
+	return nil
+}
+
+func (store *fakeStore) AddPopularAuthor(authorID int64) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.popular[authorID] = true
+	return nil
+}
+
+func (store *fakeStore) PopularAuthors() ([]int64, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	authorsIDs := make([]int64, 0, len(store.popular))
+	for authorID := range store.popular {
+		authorsIDs = append(authorsIDs, authorID)
+	}
+
+	return authorsIDs, nil
+}
+
+// trim sorts entries of the timeline and drops the oldest ones so the
+// timeline, together with the mark of complete timeline, has at most `size`
+// members.
+func (store *fakeStore) trim(timeline *fakeTimeline) {
+	sort.Stable(byPosition(timeline.entries))
+
+	if timeline.complete && len(timeline.entries) >= store.size {
+		timeline.complete = false
+	}
+	if len(timeline.entries) > store.size {
+		timeline.entries = timeline.entries[:store.size]
+	}
+}
+
+// removeEntries returns entries without the ones representing the same tweet
+// or retweet as any of `removed`.
+func removeEntries(entries []Entry, removed ...Entry) []Entry {
+	kept := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		found := false
+		for _, r := range removed {
+			if entry.TweetID == r.TweetID && entry.Retweet == r.Retweet {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			kept = append(kept, entry)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,352 @@
+package timeline
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"gopkg.in/redis.v5"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+const (
+	popularAuthorsKey = "timeline:popular.authors"
+
+	// endMember marks timeline which contains all entries. Its score is lower
+	// than score of any entry so it is the first member dropped when the
+	// timeline gets too long.
+	endMember = "end"
+	endScore  = -1
+
+	retweetSuffix = ":rt"
+
+	// pushChunkSize is the maximal number of timelines updated by one script
+	// call so Redis is not blocked for too long.
+	pushChunkSize = 1000
+)
+
+// pushScript adds entry to each of the timelines which exist and drops the
+// oldest members of the timelines which got too long.
+var pushScript = redis.NewScript(`
+local size = tonumber(ARGV[3])
+for _, key in ipairs(KEYS) do
+	if redis.call("EXISTS", key) == 1 then
+		redis.call("ZADD", key, ARGV[1], ARGV[2])
+		redis.call("ZREMRANGEBYRANK", key, 0, -size - 1)
+	end
+end
+return 0
+`)
+
+type redisStore struct {
+	client     *redis.Client
+	size       int
+	expiration time.Duration
+}
+
+// NewRedisStore constructs store which keeps each timeline in Redis sorted set
+// of at most `size` members. Entries are scored with time of the event and
+// members are formatted so entries with the same score are ordered by ID of
+// the tweet. Pushing retweet of the tweet which is already retweeted in the
+// timeline only updates its score. Timelines expire `expiration` after they
+// were set so timelines of inactive users are not kept forever.
+func NewRedisStore(config config.RedisConfigProvider, size int, expiration time.Duration) Store {
+	address := fmt.Sprintf("%s:%s", config.GetHost(), config.GetPort())
+
+	client := redis.NewClient(&redis.Options{
+		Addr:       address,
+		Password:   config.GetPassword(),
+		DB:         config.GetDB(),
+		MaxRetries: 3,
+	})
+
+	if _, err := client.Ping().Result(); err != nil {
+		log.WithError(err).Error("Error connecting to timelines instance.")
+		return nil
+	}
+
+	return &redisStore{
+		client:     client,
+		size:       size,
+		expiration: expiration,
+	}
+}
+
+func (store *redisStore) Push(usersIDs []int64, entry Entry) error {
+	for start := 0; start < len(usersIDs); start += pushChunkSize {
+		end := start + pushChunkSize
+		if end > len(usersIDs) {
+			end = len(usersIDs)
+		}
+
+		keys := make([]string, 0, end-start)
+		for _, userID := range usersIDs[start:end] {
+			keys = append(keys, timelineKey(userID))
+		}
+
+		err := pushScript.Run(store.client, keys, score(entry.Time), member(entry), store.size).Err()
+		if err != nil && err != redis.Nil {
+			log.WithField("entry", entry).WithError(err).Error("Push: failed to push entry to timelines")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get reads the timeline in batches since entries with the same position as
+// the cursor have to be skipped and entries with the same position as the
+// last one have to be included.
+func (store *redisStore) Get(userID int64, cursor *model.Cursor, limit int) (*Page, bool, error) {
+	key := timelineKey(userID)
+
+	max := "+inf"
+	if cursor != nil {
+		max = strconv.FormatFloat(score(cursor.Time), 'f', -1, 64)
+	}
+
+	var (
+		entries  = make([]Entry, 0, limit)
+		complete bool
+		offset   int64
+	)
+
+	for {
+		members, err := store.client.ZRevRangeByScoreWithScores(key, redis.ZRangeBy{
+			Min:    strconv.Itoa(endScore),
+			Max:    max,
+			Offset: offset,
+			Count:  int64(limit) + 1,
+		}).Result()
+		if err != nil {
+			log.WithField("key", key).WithError(err).Error("Get: failed to read timeline")
+			return nil, false, err
+		}
+
+		if offset == 0 && len(members) == 0 {
+			exists, err := store.client.Exists(key).Result()
+			if err != nil {
+				log.WithField("key", key).WithError(err).Error("Get: failed to check timeline")
+				return nil, false, err
+			} else if !exists {
+				return nil, false, nil
+			}
+		}
+
+		for _, z := range members {
+			value, _ := z.Member.(string)
+			if value == endMember {
+				complete = true
+				continue
+			}
+
+			entry, err := parseEntry(value, z.Score)
+			if err != nil {
+				log.WithField("member", value).WithError(err).Error("Get: invalid timeline entry")
+				continue
+			}
+
+			if cursor == nil || isBefore(*cursor, entry.Position()) {
+				entries = append(entries, entry)
+			}
+		}
+
+		offset += int64(len(members))
+
+		// one more entry than necessary tells if the page is complete
+		n := len(entries)
+		full := n > limit && isBefore(entries[limit-1].Position(), entries[n-1].Position())
+		if full || complete || len(members) <= limit {
+			break
+		}
+	}
+
+	// members with the same score are ordered by ID of the tweet only
+	sort.Sort(byPosition(entries))
+
+	entries, nextCursor := cut(entries, limit)
+	if nextCursor != nil {
+		complete = false
+	}
+
+	return &Page{
+		Entries:    entries,
+		NextCursor: nextCursor,
+	}, complete, nil
+}
+
+// Count counts members with higher score than `since` in Redis. Members with
+// the same score have to be compared by ID of the tweet.
+func (store *redisStore) Count(userID int64, since model.Cursor) (int, bool, error) {
+	key := timelineKey(userID)
+	sinceScore := strconv.FormatFloat(score(since.Time), 'f', -1, 64)
+
+	pipe := store.client.Pipeline()
+	exists := pipe.Exists(key)
+	newer := pipe.ZCount(key, "("+sinceScore, "+inf")
+	equal := pipe.ZRangeByScoreWithScores(key, redis.ZRangeBy{Min: sinceScore, Max: sinceScore})
+	if _, err := pipe.Exec(); err != nil && err != redis.Nil {
+		log.WithField("key", key).WithError(err).Error("Count: failed to count timeline entries")
+		return 0, false, err
+	}
+
+	if !exists.Val() {
+		return 0, false, nil
+	}
+
+	count := int(newer.Val())
+	for _, z := range equal.Val() {
+		value, _ := z.Member.(string)
+		if value == endMember {
+			continue
+		}
+
+		entry, err := parseEntry(value, z.Score)
+		if err == nil && isBefore(entry.Position(), since) {
+			count++
+		}
+	}
+
+	return count, true, nil
+}
+
+// Set replaces the timeline in transaction so readers never see it partially
+// written.
+func (store *redisStore) Set(userID int64, entries []Entry, complete bool) error {
+	key := timelineKey(userID)
+
+	members := make([]redis.Z, 0, len(entries)+1)
+	for _, entry := range entries {
+		members = append(members, redis.Z{Score: score(entry.Time), Member: member(entry)})
+	}
+	if complete {
+		members = append(members, redis.Z{Score: endScore, Member: endMember})
+	}
+
+	_, err := store.client.TxPipelined(func(pipe *redis.Pipeline) error {
+		pipe.Del(key)
+		if len(members) > 0 {
+			pipe.ZAdd(key, members...)
+			pipe.ZRemRangeByRank(key, 0, int64(-store.size-1))
+			pipe.Expire(key, store.expiration)
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithField("key", key).WithError(err).Error("Set: failed to set timeline")
+		return err
+	}
+
+	return nil
+}
+
+func (store *redisStore) Remove(usersIDs []int64, entries ...Entry) error {
+	if len(usersIDs) == 0 || len(entries) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		members = append(members, member(entry))
+	}
+
+	pipe := store.client.Pipeline()
+	for _, userID := range usersIDs {
+		pipe.ZRem(timelineKey(userID), members...)
+	}
+
+	if _, err := pipe.Exec(); err != nil {
+		log.WithField("entries", entries).WithError(err).Error("Remove: failed to remove entries from timelines")
+		return err
+	}
+
+	return nil
+}
+
+func (store *redisStore) Delete(usersIDs ...int64) error {
+	if len(usersIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(usersIDs))
+	for _, userID := range usersIDs {
+		keys = append(keys, timelineKey(userID))
+	}
+
+	if err := store.client.Del(keys...).Err(); err != nil {
+		log.WithField("usersIDs", usersIDs).WithError(err).Error("Delete: failed to delete timelines")
+		return err
+	}
+
+	return nil
+}
+
+func (store *redisStore) AddPopularAuthor(authorID int64) error {
+	if err := store.client.SAdd(popularAuthorsKey, authorID).Err(); err != nil {
+		log.WithField("authorID", authorID).WithError(err).Error("AddPopularAuthor: failed to add author")
+		return err
+	}
+
+	return nil
+}
+
+func (store *redisStore) PopularAuthors() ([]int64, error) {
+	values, err := store.client.SMembers(popularAuthorsKey).Result()
+	if err != nil {
+		log.WithError(err).Error("PopularAuthors: failed to get authors")
+		return nil, err
+	}
+
+	authorsIDs := make([]int64, 0, len(values))
+	for _, value := range values {
+		authorID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.WithField("value", value).WithError(err).Error("PopularAuthors: invalid author ID")
+			continue
+		}
+
+		authorsIDs = append(authorsIDs, authorID)
+	}
+
+	return authorsIDs, nil
+}
+
+func timelineKey(userID int64) string {
+	return fmt.Sprintf("timeline:%d", userID)
+}
+
+// score returns time in microseconds which is the precision of the database
+// so scores of the same time are always equal.
+func score(t time.Time) float64 {
+	return float64(t.UnixNano() / int64(time.Microsecond))
+}
+
+// member returns member of the sorted set representing the entry. ID of the
+// tweet is zero-padded so members with the same score are ordered by it.
+func member(entry Entry) string {
+	if entry.Retweet {
+		return fmt.Sprintf("%020d%s", entry.TweetID, retweetSuffix)
+	}
+	return fmt.Sprintf("%020d", entry.TweetID)
+}
+
+func parseEntry(value string, score float64) (Entry, error) {
+	retweet := strings.HasSuffix(value, retweetSuffix)
+
+	tweetID, err := strconv.ParseInt(strings.TrimSuffix(value, retweetSuffix), 10, 64)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	micros := int64(score)
+	return Entry{
+		TweetID: tweetID,
+		Retweet: retweet,
+		Time:    time.Unix(micros/1e6, (micros%1e6)*int64(time.Microsecond)).UTC(),
+	}, nil
+}
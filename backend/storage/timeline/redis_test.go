@@ -0,0 +1,136 @@
+package timeline
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("RedisStore", func() {
+	var (
+		conf  *config.Configuration = config.New()
+		store Store                 = NewRedisStore(conf.Redis, 3, time.Hour)
+		now                         = time.Now().Truncate(time.Microsecond).UTC()
+	)
+
+	tweet := func(tweetID int64, age time.Duration) Entry {
+		return Entry{TweetID: tweetID, Time: now.Add(-age)}
+	}
+
+	retweet := func(tweetID int64, age time.Duration) Entry {
+		return Entry{TweetID: tweetID, Retweet: true, Time: now.Add(-age)}
+	}
+
+	AfterEach(func() {
+		store.Delete(1, 2)
+	})
+
+	It("should return nil page when timeline is not stored", func() {
+		page, complete, err := store.Get(1, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page).To(BeNil())
+		Expect(complete).To(BeFalse())
+	})
+
+	It("should expire stored timelines", func() {
+		expiringStore := NewRedisStore(conf.Redis, 3, 50*time.Millisecond)
+		Expect(expiringStore.Set(1, []Entry{tweet(1, time.Minute)}, true)).To(Succeed())
+
+		Eventually(func() *Page {
+			page, _, _ := expiringStore.Get(1, nil, 10)
+			return page
+		}).Should(BeNil())
+	})
+
+	It("should push entries only to stored timelines", func() {
+		Expect(store.Set(1, nil, true)).To(Succeed())
+		Expect(store.Push([]int64{1, 2}, tweet(1, time.Minute))).To(Succeed())
+
+		page, complete, err := store.Get(1, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Entries).To(Equal([]Entry{tweet(1, time.Minute)}))
+		Expect(complete).To(BeTrue())
+
+		page, _, err = store.Get(2, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page).To(BeNil())
+	})
+
+	It("should keep only the latest retweet of the tweet", func() {
+		Expect(store.Set(1, []Entry{retweet(1, 2*time.Minute)}, true)).To(Succeed())
+		Expect(store.Push([]int64{1}, retweet(1, time.Minute))).To(Succeed())
+
+		page, _, err := store.Get(1, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Entries).To(Equal([]Entry{retweet(1, time.Minute)}))
+	})
+
+	It("should paginate timeline from the newest", func() {
+		entries := []Entry{tweet(2, time.Minute), retweet(2, time.Minute), tweet(1, 2*time.Minute)}
+		Expect(store.Set(1, entries, false)).To(Succeed())
+
+		page, complete, err := store.Get(1, nil, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Entries).To(Equal(entries[:2]))
+		Expect(page.NextCursor).NotTo(BeNil())
+		Expect(complete).To(BeFalse())
+
+		page, complete, err = store.Get(1, page.NextCursor, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Entries).To(Equal(entries[2:]))
+		Expect(page.NextCursor).To(BeNil())
+		Expect(complete).To(BeFalse())
+	})
+
+	It("should count entries newer than the position", func() {
+		count, stored, err := store.Count(1, model.Cursor{Time: now, ID: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored).To(BeFalse())
+
+		Expect(store.Set(1, []Entry{
+			tweet(3, time.Minute),
+			tweet(2, time.Minute),
+			retweet(1, time.Minute),
+		}, true)).To(Succeed())
+
+		count, stored, err = store.Count(1, model.Cursor{Time: now.Add(-time.Minute), ID: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored).To(BeTrue())
+		Expect(count).To(Equal(1))
+
+		count, _, err = store.Count(1, model.Cursor{Time: now.Add(-2 * time.Minute), ID: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(3))
+	})
+
+	It("should drop the oldest entries and mark timeline incomplete", func() {
+		Expect(store.Set(1, []Entry{tweet(2, 2*time.Minute), tweet(1, 3*time.Minute)}, true)).To(Succeed())
+		Expect(store.Push([]int64{1}, tweet(3, time.Minute))).To(Succeed())
+
+		page, complete, err := store.Get(1, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Entries).To(HaveLen(3))
+		Expect(complete).To(BeFalse())
+	})
+
+	It("should remove entries from timelines", func() {
+		Expect(store.Set(1, []Entry{retweet(2, time.Minute), tweet(1, 2*time.Minute)}, true)).To(Succeed())
+		Expect(store.Remove([]int64{1, 2}, retweet(2, time.Minute))).To(Succeed())
+
+		page, _, err := store.Get(1, nil, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(page.Entries).To(Equal([]Entry{tweet(1, 2*time.Minute)}))
+	})
+
+	It("should keep popular authors", func() {
+		Expect(store.AddPopularAuthor(7)).To(Succeed())
+
+		authorsIDs, err := store.PopularAuthors()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(authorsIDs).To(ContainElement(int64(7)))
+	})
+})
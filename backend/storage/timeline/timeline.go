@@ -0,0 +1,177 @@
+package timeline
+
+import (
+	"sort"
+	"time"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+// Entry is a single item of the home timeline: tweet or retweet of the tweet.
+// Retweets of the same tweet by different users are a single entry so the
+// tweet is not repeated in the timeline. Who retweeted it is not kept since it
+// can change (eg. one of the users unretweets it).
+type Entry struct {
+	TweetID int64
+	Retweet bool
+	// Time is time of the event: creation of the tweet or the latest retweet.
+	Time time.Time
+}
+
+// Position returns position of the entry in the timeline ordered from the
+// newest events.
+func (entry *Entry) Position() model.Cursor {
+	return model.Cursor{Time: entry.Time, ID: entry.TweetID}
+}
+
+// Page is a single page of the timeline with cursor pointing to the next page.
+type Page struct {
+	Entries []Entry
+	// NextCursor is nil when there are no more entries.
+	NextCursor *model.Cursor
+}
+
+// Store is interface which defines all functions used to keep precomputed
+// home timelines of the users. Only timelines which are already stored are
+// updated so timelines of inactive users are not kept at all.
+type Store interface {
+	// Push adds entry to stored timelines of each of the users.
+	Push(usersIDs []int64, entry Entry) error
+	// Get returns at most `limit` entries of the stored timeline of the user
+	// which come after the `cursor` (nil means the first page). Entries with
+	// the same position are never split between pages so the page can be
+	// longer than `limit`. Returned page is nil if the timeline is not stored.
+	// `complete` tells if the timeline ends where the stored entries end - if
+	// it is false, older entries were dropped and have to be read from
+	// somewhere else.
+	Get(userID int64, cursor *model.Cursor, limit int) (page *Page, complete bool, err error)
+	// Count returns number of entries of the stored timeline of the user which
+	// are newer than `since`. `stored` is false if the timeline is not stored.
+	Count(userID int64, since model.Cursor) (count int, stored bool, err error)
+	// Set replaces timeline of the user with given entries.
+	Set(userID int64, entries []Entry, complete bool) error
+	// Remove removes entries from stored timelines of each of the users.
+	Remove(usersIDs []int64, entries ...Entry) error
+	// Delete removes timelines of the users so they have to be set again.
+	Delete(usersIDs ...int64) error
+	// AddPopularAuthor marks the author as the one whose tweets are not
+	// pushed to the timelines and have to be read on request.
+	AddPopularAuthor(authorID int64) error
+	// PopularAuthors returns IDs of all authors marked as popular.
+	PopularAuthors() ([]int64, error)
+}
+
+// Merge merges pages of timelines into single page of about `limit` entries.
+// Entries older than next cursor of any of the pages are left for the next
+// page since older entries of that page have not been read yet. The same
+// entry can be present in several pages (possibly with different times) and
+// only the newest one is returned.
+func Merge(limit int, pages ...*Page) *Page {
+	var bound *model.Cursor
+	for _, page := range pages {
+		if page.NextCursor != nil && (bound == nil || isBefore(*page.NextCursor, *bound)) {
+			bound = page.NextCursor
+		}
+	}
+
+	all := make([]Entry, 0)
+	for _, page := range pages {
+		for _, entry := range page.Entries {
+			if bound == nil || !isBefore(*bound, entry.Position()) {
+				all = append(all, entry)
+			}
+		}
+	}
+	sort.Stable(byPosition(all))
+
+	type key struct {
+		tweetID int64
+		retweet bool
+	}
+
+	seen := make(map[key]bool, len(all))
+	entries := make([]Entry, 0, len(all))
+	for _, entry := range all {
+		k := key{entry.TweetID, entry.Retweet}
+		if !seen[k] {
+			seen[k] = true
+			entries = append(entries, entry)
+		}
+	}
+
+	entries, nextCursor := cut(entries, limit)
+	if nextCursor == nil {
+		nextCursor = bound
+	}
+
+	return &Page{
+		Entries:    entries,
+		NextCursor: nextCursor,
+	}
+}
+
+// Since returns page without entries which are not newer than `since`. If
+// any entry is dropped, the page is the last one.
+func (page *Page) Since(since model.Cursor) *Page {
+	entries := make([]Entry, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		if isBefore(entry.Position(), since) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if len(entries) == len(page.Entries) {
+		return page
+	}
+
+	return &Page{Entries: entries}
+}
+
+// cut returns first `limit` of sorted entries together with following entries
+// with the same position as the last of them. Returned cursor is nil if all
+// entries are returned.
+func cut(entries []Entry, limit int) ([]Entry, *model.Cursor) {
+	if len(entries) <= limit {
+		return entries, nil
+	}
+
+	last := entries[limit-1].Position()
+	end := limit
+	for end < len(entries) && !isBefore(last, entries[end].Position()) {
+		end++
+	}
+
+	if end == len(entries) {
+		return entries, nil
+	}
+
+	return entries[:end], &last
+}
+
+// isBefore checks if position `a` comes before position `b` in timeline
+// ordered from the newest.
+func isBefore(a, b model.Cursor) bool {
+	if a.Time.Equal(b.Time) {
+		return a.ID > b.ID
+	}
+	return a.Time.After(b.Time)
+}
+
+// byPosition orders entries from the newest. Tweet comes before its retweets.
+type byPosition []Entry
+
+func (entries byPosition) Len() int {
+	return len(entries)
+}
+
+func (entries byPosition) Swap(i, j int) {
+	entries[i], entries[j] = entries[j], entries[i]
+}
+
+func (entries byPosition) Less(i, j int) bool {
+	a, b := entries[i].Position(), entries[j].Position()
+	if isBefore(a, b) || isBefore(b, a) {
+		return isBefore(a, b)
+	}
+	return !entries[i].Retweet && entries[j].Retweet
+}
@@ -0,0 +1,13 @@
+package timeline
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTimeline(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Timeline")
+}
@@ -0,0 +1,102 @@
+package timeline
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Merge", func() {
+	var now = time.Now()
+
+	tweet := func(tweetID int64, age time.Duration) Entry {
+		return Entry{TweetID: tweetID, Time: now.Add(-age)}
+	}
+
+	retweet := func(tweetID int64, age time.Duration) Entry {
+		return Entry{TweetID: tweetID, Retweet: true, Time: now.Add(-age)}
+	}
+
+	It("should merge pages ordered from the newest", func() {
+		page := Merge(10,
+			&Page{Entries: []Entry{tweet(3, 1*time.Minute), tweet(1, 3*time.Minute)}},
+			&Page{Entries: []Entry{retweet(1, 2*time.Minute)}},
+		)
+
+		Expect(page.Entries).To(Equal([]Entry{
+			tweet(3, 1*time.Minute),
+			retweet(1, 2*time.Minute),
+			tweet(1, 3*time.Minute),
+		}))
+		Expect(page.NextCursor).To(BeNil())
+	})
+
+	It("should leave entries older than next cursor of any page for the next page", func() {
+		bound := model.Cursor{Time: now.Add(-2 * time.Minute), ID: 2}
+		page := Merge(10,
+			&Page{Entries: []Entry{tweet(3, 1*time.Minute), tweet(1, 3*time.Minute)}},
+			&Page{Entries: []Entry{tweet(2, 2*time.Minute)}, NextCursor: &bound},
+		)
+
+		Expect(page.Entries).To(Equal([]Entry{
+			tweet(3, 1*time.Minute),
+			tweet(2, 2*time.Minute),
+		}))
+		Expect(page.NextCursor).To(Equal(&bound))
+	})
+
+	It("should return only the newest of the same entries", func() {
+		page := Merge(10,
+			&Page{Entries: []Entry{retweet(1, 2*time.Minute), tweet(1, 3*time.Minute)}},
+			&Page{Entries: []Entry{retweet(1, 1*time.Minute), tweet(1, 3*time.Minute)}},
+		)
+
+		Expect(page.Entries).To(Equal([]Entry{
+			retweet(1, 1*time.Minute),
+			tweet(1, 3*time.Minute),
+		}))
+	})
+
+	It("should keep entries with the same position on the same page", func() {
+		page := Merge(2,
+			&Page{Entries: []Entry{tweet(3, 1*time.Minute), tweet(2, 2*time.Minute), tweet(1, 3*time.Minute)}},
+			&Page{Entries: []Entry{retweet(2, 2*time.Minute)}},
+		)
+
+		Expect(page.Entries).To(Equal([]Entry{
+			tweet(3, 1*time.Minute),
+			tweet(2, 2*time.Minute),
+			retweet(2, 2*time.Minute),
+		}))
+		Expect(page.NextCursor).To(Equal(&model.Cursor{Time: now.Add(-2 * time.Minute), ID: 2}))
+	})
+})
+
+var _ = Describe("Page", func() {
+	var now = time.Now()
+
+	tweet := func(tweetID int64, age time.Duration) Entry {
+		return Entry{TweetID: tweetID, Time: now.Add(-age)}
+	}
+
+	It("should keep only entries newer than the position", func() {
+		cursor := model.Cursor{Time: now.Add(-3 * time.Minute), ID: 1}
+		page := &Page{
+			Entries:    []Entry{tweet(3, 1*time.Minute), tweet(2, 2*time.Minute), tweet(1, 3*time.Minute)},
+			NextCursor: &cursor,
+		}
+
+		since := page.Since(model.Cursor{Time: now.Add(-2 * time.Minute), ID: 2})
+		Expect(since.Entries).To(Equal([]Entry{tweet(3, 1*time.Minute)}))
+		Expect(since.NextCursor).To(BeNil())
+	})
+
+	It("should return the same page when all entries are newer than the position", func() {
+		page := &Page{Entries: []Entry{tweet(2, 1*time.Minute)}}
+
+		Expect(page.Since(model.Cursor{Time: now.Add(-time.Minute), ID: 1})).To(Equal(page))
+	})
+})
@@ -0,0 +1,495 @@
+package storage
+
+import (
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/model/errors"
+	"github.com/VirrageS/chirp/backend/storage/database"
+	"github.com/VirrageS/chirp/backend/storage/timeline"
+)
+
+const (
+	// fanoutWorkers is the number of goroutines which push entries to
+	// timelines in the background.
+	fanoutWorkers = 4
+
+	// fanoutQueueSize is the maximal number of entries waiting to be pushed.
+	// When the queue is full, entries are pushed by the caller.
+	fanoutQueueSize = 1000
+
+	// maxFanoutAttempts is the number of times the change of timelines is
+	// tried before it is dropped.
+	maxFanoutAttempts = 3
+)
+
+// timelinesStorage keeps precomputed home timelines of the users. Tweets and
+// retweets are pushed to timelines of their authors and followers of the
+// authors in the background after they are created, except
+// tweets of popular authors (with more than `fanoutLimit` followers) which
+// are read from the database on request. Timelines are only a copy of data
+// from the database so errors of the store are never returned - affected
+// timelines are deleted and built again on the next read.
+type timelinesStorage struct {
+	tweetsDAO   database.TweetsDAO
+	retweetsDAO database.RetweetsDAO
+	followsDAO  database.FollowsDAO
+	store       timeline.Store
+	size        int
+	fanoutLimit int64
+	fanouts     chan fanout
+	stopChan    chan struct{}
+	workers     sync.WaitGroup
+}
+
+// fanout is a change of timelines (eg. pushing an entry to timelines of the
+// user and followers) waiting to be applied. Followers are read from the
+// database so the change can fail and it is queued again then - affected
+// timelines are not known until followers are read.
+type fanout struct {
+	apply    func() error
+	attempts int
+}
+
+// newTimelinesStorage constructs timelinesStorage that uses given tweetsDAO, retweetsDAO, followsDAO, timelines Store and its configuration.
+// Entries are pushed by `workers` goroutines or by the caller when `workers` is zero.
+func newTimelinesStorage(tweetsDAO database.TweetsDAO, retweetsDAO database.RetweetsDAO, followsDAO database.FollowsDAO, store timeline.Store, timelinesConfig config.TimelinesConfigProvider, workers int) *timelinesStorage {
+	s := &timelinesStorage{
+		tweetsDAO:   tweetsDAO,
+		retweetsDAO: retweetsDAO,
+		followsDAO:  followsDAO,
+		store:       store,
+		size:        timelinesConfig.GetSize(),
+		fanoutLimit: int64(timelinesConfig.GetFanoutLimit()),
+	}
+
+	if workers > 0 {
+		s.fanouts = make(chan fanout, fanoutQueueSize)
+		s.stopChan = make(chan struct{})
+		s.workers.Add(workers)
+		for i := 0; i < workers; i++ {
+			go s.runFanout()
+		}
+	}
+
+	return s
+}
+
+// Close stops the workers after they apply all queued changes. Changes
+// enqueued after Close are applied by the caller.
+func (s *timelinesStorage) Close() {
+	if s.fanouts == nil {
+		return
+	}
+
+	close(s.stopChan)
+	s.workers.Wait()
+}
+
+// getPage returns single page of the home timeline of the user which consists
+// of tweets and retweets of `authorsIDs` (the user and followees). The page is
+// read from the stored timeline (which is built first if it is not stored) and
+// merged with entries of popular authors.
+func (s *timelinesStorage) getPage(userID int64, authorsIDs []int64, cursor *model.Cursor, limit int) (*timeline.Page, error) {
+	regularAuthorsIDs, popularAuthorsIDs, err := s.splitAuthors(authorsIDs)
+	if err != nil {
+		return s.pull(authorsIDs, cursor, limit)
+	}
+
+	storedPage, err := s.getStoredPage(userID, regularAuthorsIDs, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(popularAuthorsIDs) == 0 {
+		return storedPage, nil
+	}
+
+	popularPage, err := s.pull(popularAuthorsIDs, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return timeline.Merge(limit, storedPage, popularPage), nil
+}
+
+// count returns number of entries of the home timeline of the user which are
+// newer than `since`. Entries are counted up to the size of stored timelines
+// and only positions of the entries are read so the count can include entries
+// which are not returned in the timeline (eg. deleted tweets).
+func (s *timelinesStorage) count(userID int64, authorsIDs []int64, since model.Cursor) (int, error) {
+	regularAuthorsIDs, popularAuthorsIDs, err := s.splitAuthors(authorsIDs)
+	if err != nil {
+		return s.pullCount(authorsIDs, since)
+	}
+
+	count, stored, err := s.store.Count(userID, since)
+	if err == nil && !stored {
+		if err = s.build(userID, regularAuthorsIDs); err == nil {
+			count, stored, err = s.store.Count(userID, since)
+		}
+	}
+
+	if err != nil || !stored {
+		count, err = s.pullCount(regularAuthorsIDs, since)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	popularCount, err := s.pullCount(popularAuthorsIDs, since)
+	if err != nil {
+		return 0, err
+	}
+
+	count += popularCount
+	if count > s.size {
+		count = s.size
+	}
+
+	return count, nil
+}
+
+// position returns the newest position of the tweet in the home timeline
+// consisting of tweets and retweets of `authorsIDs`.
+func (s *timelinesStorage) position(tweetID int64, authorsIDs []int64) (*model.Cursor, error) {
+	tweet, err := s.tweetsDAO.GetTweetByID(tweetID)
+	if err == errors.NoResultsError {
+		// the tweet could be deleted after it was read
+		tweet, err = s.tweetsDAO.GetDeletedTweetByID(tweetID)
+	}
+	if err == errors.NoResultsError {
+		return nil, errors.NoResultsError
+	} else if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	retweets, err := s.retweetsDAO.GetLatestRetweets([]int64{tweetID}, authorsIDs)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	position := &model.Cursor{Time: tweet.CreatedAt, ID: tweetID}
+	for _, retweet := range retweets {
+		if retweet.RetweetedAt.After(position.Time) {
+			position.Time = retweet.RetweetedAt
+		}
+	}
+
+	return position, nil
+}
+
+// splitAuthors splits authors into regular ones, whose entries are pushed to
+// the stored timelines, and popular ones.
+func (s *timelinesStorage) splitAuthors(authorsIDs []int64) ([]int64, []int64, error) {
+	popularIDs, err := s.store.PopularAuthors()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	popular := make(map[int64]bool, len(popularIDs))
+	for _, authorID := range popularIDs {
+		popular[authorID] = true
+	}
+
+	regularAuthorsIDs := make([]int64, 0, len(authorsIDs))
+	popularAuthorsIDs := make([]int64, 0)
+	for _, authorID := range authorsIDs {
+		if popular[authorID] {
+			popularAuthorsIDs = append(popularAuthorsIDs, authorID)
+		} else {
+			regularAuthorsIDs = append(regularAuthorsIDs, authorID)
+		}
+	}
+
+	return regularAuthorsIDs, popularAuthorsIDs, nil
+}
+
+// getStoredPage returns page of the stored timeline. Older entries which were
+// dropped from the stored timeline are read from the database.
+func (s *timelinesStorage) getStoredPage(userID int64, authorsIDs []int64, cursor *model.Cursor, limit int) (*timeline.Page, error) {
+	page, complete, err := s.store.Get(userID, cursor, limit)
+	if err == nil && page == nil {
+		if err = s.build(userID, authorsIDs); err == nil {
+			page, complete, err = s.store.Get(userID, cursor, limit)
+		}
+	}
+
+	if err != nil || page == nil {
+		return s.pull(authorsIDs, cursor, limit)
+	}
+
+	if page.NextCursor != nil || complete {
+		return page, nil
+	}
+
+	from := cursor
+	if len(page.Entries) > 0 {
+		last := page.Entries[len(page.Entries)-1].Position()
+		from = &last
+	}
+
+	olderPage, err := s.pull(authorsIDs, from, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return timeline.Merge(limit, page, olderPage), nil
+}
+
+// build sets timeline of the user to the newest entries from the database.
+func (s *timelinesStorage) build(userID int64, authorsIDs []int64) error {
+	page, err := s.pull(authorsIDs, nil, s.size)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Set(userID, page.Entries, page.NextCursor == nil)
+}
+
+// pull reads single page of tweets and retweets of the users from the database.
+func (s *timelinesStorage) pull(usersIDs []int64, cursor *model.Cursor, limit int) (*timeline.Page, error) {
+	if len(usersIDs) == 0 {
+		return &timeline.Page{}, nil
+	}
+
+	positions, tweetsCursor, err := s.tweetsDAO.GetTweetsPositionsByAuthorIDs(usersIDs, cursor, limit)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	retweets, retweetsCursor, err := s.retweetsDAO.GetRetweetsByUsersIDs(usersIDs, cursor, limit)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	tweetsEntries := make([]timeline.Entry, 0, len(positions))
+	for _, position := range positions {
+		tweetsEntries = append(tweetsEntries, timeline.Entry{
+			TweetID: position.ID,
+			Time:    position.Time,
+		})
+	}
+
+	retweetsEntries := make([]timeline.Entry, 0, len(retweets))
+	for _, retweet := range retweets {
+		retweetsEntries = append(retweetsEntries, timeline.Entry{
+			TweetID: retweet.TweetID,
+			Retweet: true,
+			Time:    retweet.RetweetedAt,
+		})
+	}
+
+	return timeline.Merge(
+		limit,
+		&timeline.Page{Entries: tweetsEntries, NextCursor: tweetsCursor},
+		&timeline.Page{Entries: retweetsEntries, NextCursor: retweetsCursor},
+	), nil
+}
+
+// pullCount counts entries of the users newer than `since` in the database.
+// At most `size` newest entries are read.
+func (s *timelinesStorage) pullCount(usersIDs []int64, since model.Cursor) (int, error) {
+	page, err := s.pull(usersIDs, nil, s.size)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(page.Since(since).Entries), nil
+}
+
+// addTweet pushes the tweet to timelines of its author and followers.
+func (s *timelinesStorage) addTweet(tweet *model.Tweet) {
+	entry := timeline.Entry{TweetID: tweet.ID, Time: tweet.CreatedAt}
+	s.enqueue(func() error { return s.push(tweet.Author.ID, entry) })
+}
+
+// addRetweet pushes the retweet to timelines of the retweeter and followers.
+// Tweet which is already in the timeline as a retweet is moved to the time of
+// this retweet.
+func (s *timelinesStorage) addRetweet(retweet *model.Retweet) {
+	entry := timeline.Entry{TweetID: retweet.TweetID, Retweet: true, Time: retweet.RetweetedAt}
+	s.enqueue(func() error { return s.push(retweet.UserID, entry) })
+}
+
+// removeTweet removes the tweet and its retweets from all timelines which can
+// contain them.
+func (s *timelinesStorage) removeTweet(tweet *model.Tweet) {
+	s.enqueue(func() error { return s.remove(tweet) })
+}
+
+// restoreTweet pushes back the tweet to timelines. Time of the retweet depends
+// on which retweets can be seen in the timeline so timelines which could
+// contain the retweet are built again instead.
+func (s *timelinesStorage) restoreTweet(tweet *model.Tweet) {
+	s.addTweet(tweet)
+	s.enqueue(func() error { return s.resetRetweeters(tweet) })
+}
+
+// enqueue queues the change to be applied in the background so creating
+// tweets does not wait for timelines of all followers. Entries of tweets
+// deleted in the meantime can still be pushed but deleted tweets are never
+// returned in the timelines.
+func (s *timelinesStorage) enqueue(apply func() error) {
+	f := fanout{apply: apply}
+	if s.fanouts == nil {
+		s.run(f)
+		return
+	}
+
+	select {
+	case s.fanouts <- f:
+	default:
+		// workers can not keep up so the caller has to wait
+		s.run(f)
+	}
+}
+
+// runFanout applies queued changes until the workers are stopped. Then it
+// applies changes which are still queued and returns.
+func (s *timelinesStorage) runFanout() {
+	defer s.workers.Done()
+
+	for {
+		select {
+		case f := <-s.fanouts:
+			s.run(f)
+		case <-s.stopChan:
+			for {
+				select {
+				case f := <-s.fanouts:
+					s.run(f)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// run applies the change. Failed change is queued again (or tried again right
+// away if it can not be queued) until it fails `maxFanoutAttempts` times.
+// Then it is dropped and affected timelines are stale until they expire.
+func (s *timelinesStorage) run(f fanout) {
+	for {
+		err := f.apply()
+		if err == nil {
+			return
+		}
+
+		f.attempts++
+		if f.attempts >= maxFanoutAttempts {
+			log.WithField("attempts", f.attempts).WithError(err).Error("Failed to update timelines.")
+			return
+		}
+
+		log.WithField("attempts", f.attempts).WithError(err).Warn("Failed to update timelines, trying again.")
+		if s.fanouts == nil {
+			continue
+		}
+
+		select {
+		case s.fanouts <- f:
+			return
+		default:
+		}
+	}
+}
+
+// push pushes the entry to timelines of the user and followers of the user.
+// If the user has too many followers, the user is marked as popular instead.
+// Users are never unmarked since their older entries would be missing in
+// timelines.
+func (s *timelinesStorage) push(userID int64, entry timeline.Entry) error {
+	followerCount, err := s.followsDAO.GetFollowerCount(userID)
+	if err != nil {
+		return err
+	}
+
+	if followerCount > s.fanoutLimit {
+		s.store.AddPopularAuthor(userID)
+		return nil
+	}
+
+	usersIDs, err := s.withFollowers(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Push(usersIDs, entry); err != nil {
+		// some of the timelines can miss the entry
+		s.store.Delete(usersIDs...)
+	}
+
+	return nil
+}
+
+// remove removes the tweet and its retweets from all timelines which can
+// contain them. Entries of popular users are removed too since they could be
+// pushed before the users became popular.
+func (s *timelinesStorage) remove(tweet *model.Tweet) error {
+	retweetersIDs, err := s.retweetsDAO.GetRetweetersIDs(tweet.ID)
+	if err != nil {
+		return err
+	}
+
+	usersIDs := make([]int64, 0)
+	for _, userID := range append([]int64{tweet.Author.ID}, retweetersIDs...) {
+		ids, err := s.withFollowers(userID)
+		if err != nil {
+			return err
+		}
+
+		usersIDs = append(usersIDs, ids...)
+	}
+
+	err = s.store.Remove(
+		usersIDs,
+		timeline.Entry{TweetID: tweet.ID},
+		timeline.Entry{TweetID: tweet.ID, Retweet: true},
+	)
+	if err != nil {
+		s.store.Delete(usersIDs...)
+	}
+
+	return nil
+}
+
+// resetRetweeters deletes timelines of retweeters of the tweet and their
+// followers.
+func (s *timelinesStorage) resetRetweeters(tweet *model.Tweet) error {
+	retweetersIDs, err := s.retweetsDAO.GetRetweetersIDs(tweet.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, retweeterID := range retweetersIDs {
+		usersIDs, err := s.withFollowers(retweeterID)
+		if err != nil {
+			return err
+		}
+
+		s.store.Delete(usersIDs...)
+	}
+
+	return nil
+}
+
+// reset deletes timeline of the user so it is built again on the next read.
+// It is used when followees of the user change.
+func (s *timelinesStorage) reset(userID int64) {
+	s.store.Delete(userID)
+}
+
+// withFollowers returns ID of the user together with IDs of followers.
+func (s *timelinesStorage) withFollowers(userID int64) ([]int64, error) {
+	followersIDs, err := s.followsDAO.GetAllFollowersIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]int64{userID}, followersIDs...), nil
+}
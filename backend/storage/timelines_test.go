@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/model"
+	"github.com/VirrageS/chirp/backend/storage/timeline"
+)
+
+var _ = Describe("Timelines", func() {
+	var (
+		followsDAO *fakeFollowsDAO
+		store      timeline.Store
+		tweet      *model.Tweet
+	)
+
+	timelineOf := func(userID int64) []timeline.Entry {
+		page, _, _ := store.Get(userID, nil, 10)
+		return page.Entries
+	}
+
+	BeforeEach(func() {
+		followsDAO = &fakeFollowsDAO{
+			followers: map[int64][]int64{1: {2}},
+			release:   make(chan struct{}),
+		}
+
+		store = timeline.NewFakeStore(10)
+		Expect(store.Set(1, nil, true)).To(Succeed())
+		Expect(store.Set(2, nil, true)).To(Succeed())
+
+		tweet = &model.Tweet{
+			ID:        5,
+			Author:    &model.PublicUser{ID: 1},
+			CreatedAt: time.Now().UTC(),
+		}
+	})
+
+	It("should push tweet to timelines of followers in the background", func() {
+		timelines := newTimelinesStorage(nil, nil, followsDAO, store, fakeTimelinesConfig{}, 1)
+
+		// returns although followers can not be read yet
+		timelines.addTweet(tweet)
+		Expect(timelineOf(2)).To(BeEmpty())
+
+		close(followsDAO.release)
+		entry := timeline.Entry{TweetID: tweet.ID, Time: tweet.CreatedAt}
+		Eventually(func() []timeline.Entry { return timelineOf(1) }).Should(Equal([]timeline.Entry{entry}))
+		Eventually(func() []timeline.Entry { return timelineOf(2) }).Should(Equal([]timeline.Entry{entry}))
+	})
+
+	It("should push tweet synchronously without workers", func() {
+		timelines := newTimelinesStorage(nil, nil, followsDAO, store, fakeTimelinesConfig{}, 0)
+		close(followsDAO.release)
+
+		timelines.addTweet(tweet)
+		Expect(timelineOf(2)).To(HaveLen(1))
+	})
+
+	It("should push tweet again when followers could not be read", func() {
+		timelines := newTimelinesStorage(nil, nil, followsDAO, store, fakeTimelinesConfig{}, 0)
+		followsDAO.failures = maxFanoutAttempts - 1
+		close(followsDAO.release)
+
+		timelines.addTweet(tweet)
+		Expect(timelineOf(2)).To(HaveLen(1))
+	})
+
+	It("should drop tweet which could not be pushed too many times", func() {
+		timelines := newTimelinesStorage(nil, nil, followsDAO, store, fakeTimelinesConfig{}, 0)
+		followsDAO.failures = maxFanoutAttempts
+		close(followsDAO.release)
+
+		timelines.addTweet(tweet)
+		Expect(timelineOf(2)).To(BeEmpty())
+	})
+
+	It("should push queued tweets before workers are closed", func() {
+		timelines := newTimelinesStorage(nil, nil, followsDAO, store, fakeTimelinesConfig{}, 1)
+		followsDAO.failures = 1
+
+		timelines.addTweet(tweet)
+		close(followsDAO.release)
+		timelines.Close()
+
+		Expect(timelineOf(1)).To(HaveLen(1))
+		Expect(timelineOf(2)).To(HaveLen(1))
+	})
+})
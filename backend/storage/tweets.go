@@ -1,73 +1,363 @@
 package storage
 
 import (
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
 	"github.com/VirrageS/chirp/backend/async"
 	"github.com/VirrageS/chirp/backend/model"
 	"github.com/VirrageS/chirp/backend/model/errors"
 	"github.com/VirrageS/chirp/backend/storage/cache"
 	"github.com/VirrageS/chirp/backend/storage/database"
 	"github.com/VirrageS/chirp/backend/storage/fulltextsearch"
+	"github.com/VirrageS/chirp/backend/storage/timeline"
 )
 
+// purgeRetryDelay is how long tweets whose media blobs could not be deleted
+// are not purged again.
+const purgeRetryDelay = time.Hour
+
 // Struct that implements TweetDataAccessor using given DAO, cache and full text search provider
 type tweetsStorage struct {
 	tweetsDAO    database.TweetsDAO
 	likesDAO     database.LikesDAO
+	retweetsDAO  database.RetweetsDAO
+	bookmarksDAO database.BookmarksDAO
+	hashtagsDAO  database.HashtagsDAO
+	mentionsDAO  database.MentionsDAO
+	scheduledDAO database.ScheduledTweetsDAO
 	cache        cache.Accessor
 	usersStorage usersDataAccessor
+	mediaStorage mediaDataAccessor
+	pollsStorage pollsDataAccessor
 	fts          fulltextsearch.TweetsSearcher
+	timelines    *timelinesStorage
+	failedPurges *failedPurges
 }
 
-// newTweetsStorage constructs tweetsStorage that uses given likesDAO, tweetsDAO, usersStorage, cache Accessor and TweetSearcher
-func newTweetsStorage(tweetsDAO database.TweetsDAO, likesDAO database.LikesDAO, usersStorage usersDataAccessor, cache cache.Accessor, fts fulltextsearch.TweetsSearcher) tweetsDataAccessor {
+// newTweetsStorage constructs tweetsStorage that uses given tweetsDAO, likesDAO, retweetsDAO, bookmarksDAO, hashtagsDAO, mentionsDAO, scheduledDAO, usersStorage, mediaStorage, pollsStorage, cache Accessor, TweetSearcher and timelinesStorage
+func newTweetsStorage(tweetsDAO database.TweetsDAO, likesDAO database.LikesDAO, retweetsDAO database.RetweetsDAO, bookmarksDAO database.BookmarksDAO, hashtagsDAO database.HashtagsDAO, mentionsDAO database.MentionsDAO, scheduledDAO database.ScheduledTweetsDAO, usersStorage usersDataAccessor, mediaStorage mediaDataAccessor, pollsStorage pollsDataAccessor, cache cache.Accessor, fts fulltextsearch.TweetsSearcher, timelines *timelinesStorage) tweetsDataAccessor {
 	return &tweetsStorage{
 		tweetsDAO:    tweetsDAO,
 		likesDAO:     likesDAO,
+		retweetsDAO:  retweetsDAO,
+		bookmarksDAO: bookmarksDAO,
+		hashtagsDAO:  hashtagsDAO,
+		mentionsDAO:  mentionsDAO,
+		scheduledDAO: scheduledDAO,
 		cache:        cache,
 		usersStorage: usersStorage,
+		mediaStorage: mediaStorage,
+		pollsStorage: pollsStorage,
 		fts:          fts,
+		timelines:    timelines,
+		failedPurges: newFailedPurges(),
 	}
 }
 
-func (s *tweetsStorage) GetUsersTweets(userID, requestingUserID int64) ([]*model.Tweet, error) {
-	tweetsIDs, err := s.getTweetsIDsByAuthorID(userID)
+// GetTweetsByAuthorIDs returns single page of tweets of the authors, newest
+// first. Tweets which can not be seen by the requesting user are skipped so
+// the page can be shorter than `limit`.
+func (s *tweetsStorage) GetTweetsByAuthorIDs(authorsIDs []int64, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	tweetsIDs, nextCursor, err := s.tweetsDAO.GetTweetsIDsByAuthorIDs(authorsIDs, cursor, limit)
 	if err != nil {
-		return nil, err
+		return nil, nil, errors.UnexpectedError
 	}
 
 	tweets, err := s.getTweetsByIDs(tweetsIDs, requestingUserID)
 	if err != nil {
-		return nil, errors.UnexpectedError
+		return nil, nil, err
 	}
 
-	return tweets, nil
+	return sortTweetsByIDs(tweets, tweetsIDs), nextCursor, nil
 }
 
-func (s *tweetsStorage) GetTweetsByAuthorIDs(authorsIDs []int64, requestingUserID int64) ([]*model.Tweet, error) {
-	tweets := make([]*model.Tweet, 0)
+// GetHomeTimeline returns single page of tweets and retweets of the user and
+// users followed by the user, most recent events first. Retweets are copies
+// of the tweets with `RetweetedBy` set to the user who retweeted it most
+// recently. If `sinceID` is not zero, only tweets and retweets newer than the
+// newest position of the tweet with `sinceID` in the timeline are returned.
+func (s *tweetsStorage) GetHomeTimeline(userID, sinceID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	followeesIDs, err := s.usersStorage.GetFolloweesIDs(userID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// This should not be parallel because each GetUsersTweets is arleady
-	// parallel so we do not want overload database/cache.
-	for _, userID := range authorsIDs {
-		usersTweets, err := s.GetUsersTweets(userID, requestingUserID)
+	authorsIDs := append([]int64{userID}, followeesIDs...)
+
+	page, err := s.timelines.getPage(userID, authorsIDs, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.readTimelinePage(page, authorsIDs, userID, sinceID)
+}
+
+// GetTimeline works like `GetHomeTimeline` but returns tweets and retweets of
+// given authors (eg. members of the list). Such timelines are not stored so
+// they are always read from the database.
+func (s *tweetsStorage) GetTimeline(authorsIDs []int64, requestingUserID, sinceID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	page, err := s.timelines.pull(authorsIDs, cursor, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.readTimelinePage(page, authorsIDs, requestingUserID, sinceID)
+}
+
+// readTimelinePage returns tweets of the timeline page of `authorsIDs`
+// without entries which are not newer than the tweet with `sinceID` (if it is
+// not zero).
+func (s *tweetsStorage) readTimelinePage(page *timeline.Page, authorsIDs []int64, requestingUserID, sinceID int64) ([]*model.Tweet, *model.Cursor, error) {
+	if sinceID != 0 {
+		since, err := s.timelines.position(sinceID, authorsIDs)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		tweets = append(tweets, usersTweets...)
+		page = page.Since(*since)
 	}
 
-	return tweets, nil
+	tweetsIDs, retweetedIDs := entriesTweetsIDs(page.Entries)
+
+	tweets, err := s.getTweetsByIDs(tweetsIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	retweeterOf, err := s.getRetweeters(retweetedIDs, authorsIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tweetsByID := make(map[int64]*model.Tweet, len(tweets))
+	for _, tweet := range tweets {
+		tweetsByID[tweet.ID] = tweet
+	}
+
+	// the same tweet can be returned as the tweet and as its retweet
+	timelineTweets := make([]*model.Tweet, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		tweet, ok := tweetsByID[entry.TweetID]
+		if !ok {
+			// tweet was deleted or can not be seen by the user
+			continue
+		}
+
+		if entry.Retweet {
+			retweeter, ok := retweeterOf[entry.TweetID]
+			if !ok {
+				// tweet was unretweeted by all authors
+				continue
+			}
+
+			retweet := *tweet
+			retweet.RetweetedBy = retweeter
+			tweet = &retweet
+		}
+
+		timelineTweets = append(timelineTweets, tweet)
+	}
+
+	return timelineTweets, page.NextCursor, nil
+}
+
+// CountHomeTimelineSince returns number of tweets and retweets in the home
+// timeline of the user which are newer than the tweet with `sinceID` (see
+// `GetHomeTimeline`). Tweets are not read so the count is only approximate.
+func (s *tweetsStorage) CountHomeTimelineSince(userID, sinceID int64) (int, error) {
+	followeesIDs, err := s.usersStorage.GetFolloweesIDs(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	authorsIDs := append([]int64{userID}, followeesIDs...)
+	since, err := s.timelines.position(sinceID, authorsIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.timelines.count(userID, authorsIDs, *since)
+}
+
+// GetHomeTimelineSince returns tweets and retweets in the home timeline of the
+// user which are newer than the tweet with `sinceID`, at most as many as
+// stored timelines keep. Tweets are returned as they are cached: author and
+// retweeter have only IDs and no other data (counters, quoted tweets) is
+// collected so they can be only checked (eg. which of them to count).
+func (s *tweetsStorage) GetHomeTimelineSince(userID, sinceID int64) ([]*model.Tweet, error) {
+	followeesIDs, err := s.usersStorage.GetFolloweesIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	authorsIDs := append([]int64{userID}, followeesIDs...)
+	since, err := s.timelines.position(sinceID, authorsIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.timelines.getPage(userID, authorsIDs, nil, s.timelines.size)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := page.Since(*since).Entries
+	tweetsIDs, retweetedIDs := entriesTweetsIDs(entries)
+
+	tweets, err := s.readTweets(tweetsIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tweets, err = s.filterVisible(tweets, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	retweeterIDOf := make(map[int64]int64, len(retweetedIDs))
+	if len(retweetedIDs) > 0 {
+		retweets, err := s.retweetsDAO.GetLatestRetweets(retweetedIDs, authorsIDs)
+		if err != nil {
+			return nil, errors.UnexpectedError
+		}
+
+		for _, retweet := range retweets {
+			retweeterIDOf[retweet.TweetID] = retweet.UserID
+		}
+	}
+
+	tweetsByID := make(map[int64]*model.Tweet, len(tweets))
+	for _, tweet := range tweets {
+		tweetsByID[tweet.ID] = tweet
+	}
+
+	timelineTweets := make([]*model.Tweet, 0, len(entries))
+	for _, entry := range entries {
+		tweet, ok := tweetsByID[entry.TweetID]
+		if !ok {
+			continue
+		}
+
+		if entry.Retweet {
+			retweeterID, ok := retweeterIDOf[entry.TweetID]
+			if !ok {
+				continue
+			}
+
+			retweet := *tweet
+			retweet.RetweetedBy = &model.PublicUser{ID: retweeterID}
+			tweet = &retweet
+		}
+
+		timelineTweets = append(timelineTweets, tweet)
+	}
+
+	return timelineTweets, nil
+}
+
+// entriesTweetsIDs returns IDs of tweets of the entries (each of them once) and
+// IDs of retweeted tweets.
+func entriesTweetsIDs(entries []timeline.Entry) ([]int64, []int64) {
+	tweetsIDs := make([]int64, 0, len(entries))
+	retweetedIDs := make([]int64, 0)
+	seen := make(map[int64]bool, len(entries))
+	for _, entry := range entries {
+		if !seen[entry.TweetID] {
+			seen[entry.TweetID] = true
+			tweetsIDs = append(tweetsIDs, entry.TweetID)
+		}
+		if entry.Retweet {
+			retweetedIDs = append(retweetedIDs, entry.TweetID)
+		}
+	}
+
+	return tweetsIDs, retweetedIDs
+}
+
+// getRetweeters returns users who retweeted each of the tweets most recently
+// among `usersIDs`.
+func (s *tweetsStorage) getRetweeters(tweetsIDs, usersIDs []int64, requestingUserID int64) (map[int64]*model.PublicUser, error) {
+	retweeterOf := make(map[int64]*model.PublicUser, len(tweetsIDs))
+	if len(tweetsIDs) == 0 {
+		return retweeterOf, nil
+	}
+
+	retweets, err := s.retweetsDAO.GetLatestRetweets(tweetsIDs, usersIDs)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	retweetersIDs := make([]int64, 0, len(retweets))
+	for _, retweet := range retweets {
+		retweetersIDs = append(retweetersIDs, retweet.UserID)
+	}
+
+	retweeters, err := s.usersStorage.GetUsersByIDs(retweetersIDs, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	retweetersByID := make(map[int64]*model.PublicUser, len(retweeters))
+	for _, retweeter := range retweeters {
+		retweetersByID[retweeter.ID] = retweeter
+	}
+
+	for _, retweet := range retweets {
+		if retweeter, ok := retweetersByID[retweet.UserID]; ok {
+			retweeterOf[retweet.TweetID] = retweeter
+		}
+	}
+
+	return retweeterOf, nil
 }
 
 func (s *tweetsStorage) GetTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
+	tweet, err := s.getCachedTweet(tweetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// tweets which can not be seen are indistinguishable from not existing
+	visible, err := s.isVisible(tweet, requestingUserID)
+	if err != nil {
+		return nil, err
+	} else if !visible {
+		return nil, errors.NoResultsError
+	}
+
+	err = s.collectTweetData(tweet, requestingUserID)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	return tweet, nil
+}
+
+// IsTweetVisible checks if the tweet can be seen by the requesting user
+// without reading any other data of the tweet. Returns NoResultsError if the
+// tweet does not exist.
+func (s *tweetsStorage) IsTweetVisible(tweetID, requestingUserID int64) (bool, error) {
+	tweet, err := s.getCachedTweet(tweetID)
+	if err != nil {
+		return false, err
+	}
+
+	return s.isVisible(tweet, requestingUserID)
+}
+
+// getCachedTweet reads the tweet from the cache or the database (and caches
+// it) without collecting any other data.
+func (s *tweetsStorage) getCachedTweet(tweetID int64) (*model.Tweet, error) {
 	var (
 		tweet *model.Tweet
 		err   error
 	)
 
 	key := cache.Key{"tweet", tweetID}
-	if exists, _ := s.cache.GetSingle(key, tweet); !exists {
+	if exists, _ := s.cache.GetSingle(key, &tweet); !exists {
 		tweet, err = s.tweetsDAO.GetTweetByID(tweetID)
 		if err == errors.NoResultsError {
 			return nil, errors.NoResultsError
@@ -78,43 +368,326 @@ func (s *tweetsStorage) GetTweet(tweetID, requestingUserID int64) (*model.Tweet,
 		s.cache.Set(cache.Entry{key, tweet})
 	}
 
-	err = s.collectTweetData(tweet, requestingUserID)
+	return tweet, nil
+}
+
+// InsertTweet inserts the tweet with its media and poll. Referenced tweets and
+// media have to be validated before.
+func (s *tweetsStorage) InsertTweet(tweet *model.NewTweet) (*model.Tweet, error) {
+	insertedTweet, err := s.tweetsDAO.InsertTweet(tweet)
 	if err != nil {
 		return nil, errors.UnexpectedError
 	}
 
-	return tweet, nil
+	err = s.cacheInsertedTweet(insertedTweet)
+	if err != nil {
+		return nil, err
+	}
+
+	return insertedTweet, nil
 }
 
-func (s *tweetsStorage) InsertTweet(tweet *model.NewTweet, requestingUserID int64) (*model.Tweet, error) {
-	insertedTweet, err := s.tweetsDAO.InsertTweet(tweet)
+// cacheInsertedTweet collects data of just inserted tweet and updates the
+// cache and timelines.
+func (s *tweetsStorage) cacheInsertedTweet(insertedTweet *model.Tweet) error {
+	authorID := insertedTweet.Author.ID
+
+	// Tweet is cached before its data is collected so flags of the requesting
+	// user (eg. bookmarked) are never served to other users.
+	s.cache.Set(cache.Entry{cache.Key{"tweet", insertedTweet.ID}, insertedTweet})
+
+	err := s.collectTweetData(insertedTweet, authorID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if insertedTweet.InReplyToID != 0 {
+		s.cache.Incr(cache.Key{"tweet", insertedTweet.InReplyToID, "reply.count"})
+	}
+
+	s.timelines.addTweet(insertedTweet)
+	return nil
+}
+
+func (s *tweetsStorage) InsertScheduledTweet(tweet *model.NewScheduledTweet) (*model.ScheduledTweet, error) {
+	scheduledTweet, err := s.scheduledDAO.InsertScheduledTweet(tweet)
 	if err != nil {
 		return nil, errors.UnexpectedError
 	}
 
-	err = s.collectTweetData(insertedTweet, requestingUserID)
+	return scheduledTweet, nil
+}
+
+func (s *tweetsStorage) GetScheduledTweets(authorID int64) ([]*model.ScheduledTweet, error) {
+	scheduledTweets, err := s.scheduledDAO.GetScheduledTweets(authorID)
 	if err != nil {
 		return nil, errors.UnexpectedError
 	}
 
-	s.cache.Set(cache.Entry{cache.Key{"tweet", insertedTweet.ID}, insertedTweet})
-	s.cache.SAdd(cache.Key{"tweets.ids", requestingUserID}, insertedTweet.ID)
+	return scheduledTweets, nil
+}
 
-	return insertedTweet, nil
+func (s *tweetsStorage) DeleteScheduledTweet(scheduledTweetID, authorID int64) error {
+	err := s.scheduledDAO.DeleteScheduledTweet(scheduledTweetID, authorID)
+	if err == errors.NoResultsError {
+		return errors.NoResultsError
+	} else if err != nil {
+		return errors.UnexpectedError
+	}
+
+	return nil
 }
 
-func (s *tweetsStorage) DeleteTweet(tweetID, requestingUserID int64) error {
-	err := s.tweetsDAO.DeleteTweet(tweetID)
+// PublishScheduledTweets publishes due tweets which pass `validate`. Tweets
+// are returned even if publishing of the others failed since they are
+// already published at this point.
+func (s *tweetsStorage) PublishScheduledTweets(until time.Time, limit int, validate func(*model.NewTweet) error) ([]*model.Tweet, error) {
+	publishedTweets, err := s.scheduledDAO.PublishScheduledTweets(until, limit, validate)
 	if err != nil {
+		err = errors.UnexpectedError
+	}
+
+	for _, tweet := range publishedTweets {
+		if cacheErr := s.cacheInsertedTweet(tweet); cacheErr != nil {
+			err = cacheErr
+		}
+	}
+
+	return publishedTweets, err
+}
+
+func (s *tweetsStorage) DeleteTweet(tweetID int64) error {
+	tweet, err := s.tweetsDAO.GetTweetByID(tweetID)
+	if err == errors.NoResultsError {
+		return errors.NoResultsError
+	} else if err != nil {
 		return errors.UnexpectedError
 	}
 
-	s.cache.Delete(cache.Key{"tweet", tweetID})
-	s.cache.SRemove(cache.Key{"tweets.ids", requestingUserID}, tweetID)
+	// The pin would be cleared by the database only when the tweet is purged
+	// so we have to clear it ourselves.
+	err = s.usersStorage.UnpinTweet(tweet.Author.ID, tweetID)
+	if err != nil {
+		return err
+	}
 
+	err = s.tweetsDAO.DeleteTweet(tweetID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	s.timelines.removeTweet(tweet)
+	s.invalidateTweet(tweet)
 	return nil
 }
 
+// GetDeletedTweet returns tweet which was deleted but was not purged yet.
+// Data of the tweet is not collected since it can not be seen by anyone.
+func (s *tweetsStorage) GetDeletedTweet(tweetID int64) (*model.Tweet, error) {
+	tweet, err := s.tweetsDAO.GetDeletedTweetByID(tweetID)
+	if err == errors.NoResultsError {
+		return nil, errors.NoResultsError
+	} else if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	return tweet, nil
+}
+
+// RestoreTweet restores deleted tweet if it was deleted during the last
+// `window`. Returns false if the tweet could not be restored.
+func (s *tweetsStorage) RestoreTweet(tweet *model.Tweet, window time.Duration) (bool, error) {
+	restored, err := s.tweetsDAO.RestoreTweet(tweet.ID, window)
+	if err != nil {
+		return false, errors.UnexpectedError
+	}
+
+	if !restored {
+		return false, nil
+	}
+
+	s.invalidateTweet(tweet)
+	s.timelines.restoreTweet(tweet)
+	return true, nil
+}
+
+// PurgeDeletedTweets removes for good at most `limit` tweets which were
+// deleted more than `window` ago and returns how many of them were removed.
+// Media blobs are removed before the rows which reference them so no blob is
+// left behind. Tweets whose blobs could not be removed are kept (still
+// deleted) and skipped for `purgeRetryDelay` so they do not take up the next
+// batches.
+func (s *tweetsStorage) PurgeDeletedTweets(window time.Duration, limit int) (int, error) {
+	tweetsIDs, err := s.tweetsDAO.GetTweetsIDsToPurge(window, limit, s.failedPurges.excluded(time.Now()))
+	if err != nil {
+		return 0, errors.UnexpectedError
+	}
+
+	purged := 0
+	for _, tweetID := range tweetsIDs {
+		media, err := s.mediaStorage.GetTweetMedia(tweetID)
+		if err != nil {
+			return purged, err
+		}
+
+		// Tweet can not be restored anymore so its blobs are not needed even
+		// if other replica purges the tweet in the meantime.
+		if err := s.mediaStorage.DeleteMediaBlobs(media); err != nil {
+			log.WithField("tweetID", tweetID).WithError(err).Error("Failed to purge tweet, its media blobs could not be deleted.")
+			s.failedPurges.add(tweetID, time.Now())
+			continue
+		}
+
+		removed, err := s.tweetsDAO.PurgeTweet(tweetID)
+		if err != nil {
+			return purged, errors.UnexpectedError
+		}
+
+		if removed {
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// failedPurges keeps tweets which could not be purged with the time of the
+// failure.
+type failedPurges struct {
+	mutex    sync.Mutex
+	failedAt map[int64]time.Time
+}
+
+func newFailedPurges() *failedPurges {
+	return &failedPurges{failedAt: make(map[int64]time.Time)}
+}
+
+func (p *failedPurges) add(tweetID int64, now time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.failedAt[tweetID] = now
+}
+
+// excluded returns IDs of tweets which failed less than `purgeRetryDelay`
+// before `now`. Other tweets are forgotten so they are purged again.
+func (p *failedPurges) excluded(now time.Time) []int64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	tweetsIDs := make([]int64, 0, len(p.failedAt))
+	for tweetID, failedAt := range p.failedAt {
+		if now.Sub(failedAt) < purgeRetryDelay {
+			tweetsIDs = append(tweetsIDs, tweetID)
+		} else {
+			delete(p.failedAt, tweetID)
+		}
+	}
+
+	return tweetsIDs
+}
+
+// invalidateTweet removes the tweet and reply count of its parent from the
+// cache. It is used when the tweet is deleted or restored.
+func (s *tweetsStorage) invalidateTweet(tweet *model.Tweet) {
+	s.cache.Delete(cache.Key{"tweet", tweet.ID})
+	if tweet.InReplyToID != 0 {
+		s.cache.Delete(cache.Key{"tweet", tweet.InReplyToID, "reply.count"})
+	}
+}
+
+func (s *tweetsStorage) EditTweet(tweetID int64, content string, requestingUserID int64) (*model.Tweet, error) {
+	editedTweet, err := s.tweetsDAO.EditTweet(tweetID, content)
+	if err == errors.NoResultsError {
+		return nil, errors.NoResultsError
+	} else if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	// Search index is also periodically synchronized with database so failing
+	// here is not critical (error is logged by the searcher).
+	s.fts.IndexTweet(tweetID, editedTweet.Content, editedTweet.CreatedAt)
+
+	s.cache.Delete(
+		cache.Key{"tweet", tweetID, "mentions"},
+		cache.Key{"tweet", tweetID, "revisions"},
+	)
+
+	// the same as in cacheInsertedTweet - cache tweet before collecting data
+	s.cache.Set(cache.Entry{cache.Key{"tweet", tweetID}, editedTweet})
+
+	err = s.collectTweetData(editedTweet, requestingUserID)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	return editedTweet, nil
+}
+
+func (s *tweetsStorage) GetTweetRevisions(tweetID int64) ([]*model.TweetRevision, error) {
+	revisions := make([]*model.TweetRevision, 0)
+
+	key := cache.Key{"tweet", tweetID, "revisions"}
+	if exists, _ := s.cache.GetSingle(key, &revisions); !exists {
+		var err error
+
+		revisions, err = s.tweetsDAO.GetTweetRevisions(tweetID)
+		if err != nil {
+			return nil, errors.UnexpectedError
+		}
+
+		s.cache.Set(cache.Entry{key, revisions})
+	}
+
+	return revisions, nil
+}
+
+// GetReplies returns replies to the tweet, oldest first.
+func (s *tweetsStorage) GetReplies(tweetID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	repliesIDs, nextCursor, err := s.tweetsDAO.GetRepliesIDs(tweetID, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
+
+	replies, err := s.getTweetsByIDs(repliesIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// getTweetsByIDs does not preserve order
+	return sortTweetsByIDs(replies, repliesIDs), nextCursor, nil
+}
+
+func (s *tweetsStorage) GetTweetsByHashtag(hashtag string, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	tweetsIDs, nextCursor, err := s.hashtagsDAO.GetTweetsIDsByHashtag(hashtag, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
+
+	tweets, err := s.getTweetsByIDs(tweetsIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// getTweetsByIDs does not preserve order
+	return sortTweetsByIDs(tweets, tweetsIDs), nextCursor, nil
+}
+
+func (s *tweetsStorage) GetMentions(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	tweetsIDs, nextCursor, err := s.mentionsDAO.GetMentioningTweetsIDs(userID, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
+
+	tweets, err := s.getTweetsByIDs(tweetsIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// getTweetsByIDs does not preserve order
+	return sortTweetsByIDs(tweets, tweetsIDs), nextCursor, nil
+}
+
 func (s *tweetsStorage) LikeTweet(tweetID, requestingUserID int64) error {
 	liked, err := s.likesDAO.LikeTweet(tweetID, requestingUserID)
 	if err != nil {
@@ -147,40 +720,143 @@ func (s *tweetsStorage) UnlikeTweet(tweetID, requestingUserID int64) error {
 	return nil
 }
 
-func (s *tweetsStorage) GetTweetsUsingQueryString(querystring string, requestingUserID int64) ([]*model.Tweet, error) {
-	tweetsIDs := make([]int64, 0)
+func (s *tweetsStorage) GetLikers(tweetID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	usersIDs, nextCursor, err := s.likesDAO.GetLikersIDs(tweetID, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
 
-	key := cache.Key{"tweets", "querystring", querystring}
-	if exists, _ := s.cache.GetSingle(key, &tweetsIDs); !exists {
-		var err error
+	users, err := s.usersStorage.GetUsersByIDs(usersIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		tweetsIDs, err = s.fts.GetTweetsIDs(querystring)
-		if err != nil {
-			return nil, errors.UnexpectedError
-		}
+	return sortUsersByIDs(users, usersIDs), nextCursor, nil
+}
 
-		s.cache.Set(cache.Entry{key, tweetsIDs})
+func (s *tweetsStorage) GetLikedTweets(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	tweetsIDs, nextCursor, err := s.likesDAO.GetLikedTweetsIDs(userID, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
 	}
 
-	return s.getTweetsByIDs(tweetsIDs, requestingUserID)
+	tweets, err := s.getTweetsByIDs(tweetsIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// tweets are ordered by time of liking, not creation
+	return sortTweetsByIDs(tweets, tweetsIDs), nextCursor, nil
 }
 
-func (s *tweetsStorage) getTweetsIDsByAuthorID(userID int64) ([]int64, error) {
-	tweetsIDs := make([]int64, 0)
+func (s *tweetsStorage) BookmarkTweet(tweetID, requestingUserID int64) error {
+	bookmarked, err := s.bookmarksDAO.BookmarkTweet(tweetID, requestingUserID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
 
-	key := cache.Key{"tweets.ids", userID}
-	if exists, _ := s.cache.GetSingle(key, &tweetsIDs); !exists {
-		var err error
+	if bookmarked {
+		s.cache.Set(
+			cache.Entry{cache.Key{"tweet", tweetID, "bookmarked.by", requestingUserID}, true},
+		)
+	}
 
-		tweetsIDs, err = s.tweetsDAO.GetTweetsIDsByAuthorID(userID)
+	return nil
+}
+
+func (s *tweetsStorage) UnbookmarkTweet(tweetID, requestingUserID int64) error {
+	unbookmarked, err := s.bookmarksDAO.UnbookmarkTweet(tweetID, requestingUserID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if unbookmarked {
+		s.cache.Set(
+			cache.Entry{cache.Key{"tweet", tweetID, "bookmarked.by", requestingUserID}, false},
+		)
+	}
+
+	return nil
+}
+
+func (s *tweetsStorage) GetBookmarks(requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	tweetsIDs, nextCursor, err := s.bookmarksDAO.GetBookmarkedTweetsIDs(requestingUserID, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
+
+	tweets, err := s.getTweetsByIDs(tweetsIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// tweets are ordered by time of bookmarking, not creation
+	return sortTweetsByIDs(tweets, tweetsIDs), nextCursor, nil
+}
+
+func (s *tweetsStorage) RetweetTweet(tweetID, requestingUserID int64) error {
+	retweeted, err := s.retweetsDAO.RetweetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if retweeted {
+		s.cache.Incr(cache.Key{"tweet", tweetID, "retweet.count"})
+		s.cache.Set(
+			cache.Entry{cache.Key{"tweet", tweetID, "retweeted.by", requestingUserID}, true},
+		)
+
+		retweets, err := s.retweetsDAO.GetLatestRetweets([]int64{tweetID}, []int64{requestingUserID})
 		if err != nil {
-			return nil, errors.UnexpectedError
+			return errors.UnexpectedError
+		}
+		for _, retweet := range retweets {
+			s.timelines.addRetweet(retweet)
 		}
+	}
+
+	return nil
+}
+
+func (s *tweetsStorage) UnretweetTweet(tweetID, requestingUserID int64) error {
+	unretweeted, err := s.retweetsDAO.UnretweetTweet(tweetID, requestingUserID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
 
-		s.cache.Set(cache.Entry{key, tweetsIDs})
+	if unretweeted {
+		s.cache.Decr(cache.Key{"tweet", tweetID, "retweet.count"})
+		s.cache.Set(
+			cache.Entry{cache.Key{"tweet", tweetID, "retweeted.by", requestingUserID}, false},
+		)
 	}
 
-	return tweetsIDs, nil
+	return nil
+}
+
+// GetTweetsUsingQueryString returns single page of tweets matching the
+// `querystring`, newest first.
+func (s *tweetsStorage) GetTweetsUsingQueryString(querystring string, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.Tweet, *model.Cursor, error) {
+	tweetsIDs, nextCursor, err := s.fts.GetTweetsIDs(querystring, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
+
+	tweets, err := s.getTweetsByIDs(tweetsIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sortTweetsByIDs(tweets, tweetsIDs), nextCursor, nil
+}
+
+func (s *tweetsStorage) HasRecentDuplicate(authorID int64, content string, window time.Duration) (bool, error) {
+	hasDuplicate, err := s.tweetsDAO.HasRecentDuplicate(authorID, content, window)
+	if err != nil {
+		return false, errors.UnexpectedError
+	}
+
+	return hasDuplicate, nil
 }
 
 func (s *tweetsStorage) collectTweetsData(tweets []*model.Tweet, requestingUserID int64) error {
@@ -206,9 +882,16 @@ func (s *tweetsStorage) collectTweetsData(tweets []*model.Tweet, requestingUserI
 // Be careful - this is function does SIDE EFFECTS only
 func (s *tweetsStorage) collectTweetData(tweet *model.Tweet, requestingUserID int64) error {
 	var (
-		author    *model.PublicUser
-		likeCount int64
-		isLiked   bool
+		author       *model.PublicUser
+		likeCount    int64
+		isLiked      bool
+		retweetCount int64
+		isRetweeted  bool
+		isBookmarked bool
+		replyCount   int64
+		mentions     []*model.Mention
+		media        []*model.Media
+		poll         *model.Poll
 	)
 
 	author, err := s.usersStorage.GetUserByID(tweet.Author.ID, requestingUserID)
@@ -236,14 +919,149 @@ func (s *tweetsStorage) collectTweetData(tweet *model.Tweet, requestingUserID in
 		s.cache.Set(cache.Entry{key, isLiked})
 	}
 
+	key = cache.Key{"tweet", tweet.ID, "retweet.count"}
+	if exists, _ := s.cache.GetSingle(key, &retweetCount); !exists {
+		retweetCount, err = s.retweetsDAO.GetRetweetCount(tweet.ID)
+		if err != nil {
+			return err
+		}
+
+		s.cache.Set(cache.Entry{key, retweetCount})
+	}
+
+	key = cache.Key{"tweet", tweet.ID, "retweeted.by", requestingUserID}
+	if exists, _ := s.cache.GetSingle(key, &isRetweeted); !exists {
+		isRetweeted, err = s.retweetsDAO.IsRetweeted(tweet.ID, requestingUserID)
+		if err != nil {
+			return err
+		}
+
+		s.cache.Set(cache.Entry{key, isRetweeted})
+	}
+
+	key = cache.Key{"tweet", tweet.ID, "bookmarked.by", requestingUserID}
+	if exists, _ := s.cache.GetSingle(key, &isBookmarked); !exists {
+		isBookmarked, err = s.bookmarksDAO.IsBookmarked(tweet.ID, requestingUserID)
+		if err != nil {
+			return err
+		}
+
+		s.cache.Set(cache.Entry{key, isBookmarked})
+	}
+
+	key = cache.Key{"tweet", tweet.ID, "reply.count"}
+	if exists, _ := s.cache.GetSingle(key, &replyCount); !exists {
+		replyCount, err = s.tweetsDAO.GetReplyCount(tweet.ID)
+		if err != nil {
+			return err
+		}
+
+		s.cache.Set(cache.Entry{key, replyCount})
+	}
+
+	key = cache.Key{"tweet", tweet.ID, "mentions"}
+	if exists, _ := s.cache.GetSingle(key, &mentions); !exists {
+		mentions, err = s.mentionsDAO.GetTweetMentions(tweet.ID)
+		if err != nil {
+			return err
+		}
+
+		s.cache.Set(cache.Entry{key, mentions})
+	}
+
+	media, err = s.mediaStorage.GetTweetMedia(tweet.ID)
+	if err != nil {
+		return err
+	}
+
+	poll, err = s.pollsStorage.GetPoll(tweet.ID, requestingUserID)
+	if err != nil {
+		return err
+	}
+
+	if tweet.QuotedTweetID != 0 {
+		tweet.QuotedTweet, err = s.getQuotedTweet(tweet.QuotedTweetID, requestingUserID)
+		if err != nil {
+			return err
+		}
+	}
+
 	tweet.Author = author
 	tweet.LikeCount = likeCount
 	tweet.Liked = isLiked
+	tweet.RetweetCount = retweetCount
+	tweet.Retweeted = isRetweeted
+	tweet.Bookmarked = isBookmarked
+	tweet.ReplyCount = replyCount
+	tweet.Mentions = mentions
+	tweet.Media = media
+	tweet.Poll = poll
 
 	return nil
 }
 
+// getQuotedTweet returns quoted tweet with hydrated author. Other data of the
+// quoted tweet (counters, nested quotes) is not collected. When quoted tweet
+// no longer exists or can not be seen placeholder is returned instead.
+func (s *tweetsStorage) getQuotedTweet(tweetID, requestingUserID int64) (*model.Tweet, error) {
+	var (
+		tweet *model.Tweet
+		err   error
+	)
+
+	key := cache.Key{"tweet", tweetID}
+	if exists, _ := s.cache.GetSingle(key, &tweet); !exists {
+		tweet, err = s.tweetsDAO.GetTweetByID(tweetID)
+		if err == errors.NoResultsError {
+			return &model.Tweet{ID: tweetID, Unavailable: true}, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		s.cache.Set(cache.Entry{key, tweet})
+	}
+
+	visible, err := s.isVisible(tweet, requestingUserID)
+	if err != nil {
+		return nil, err
+	} else if !visible {
+		return &model.Tweet{ID: tweetID, Unavailable: true}, nil
+	}
+
+	author, err := s.usersStorage.GetUserByID(tweet.Author.ID, requestingUserID)
+	if err == errors.NoResultsError {
+		return &model.Tweet{ID: tweetID, Unavailable: true}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	tweet.Author = author
+	return tweet, nil
+}
+
 func (s *tweetsStorage) getTweetsByIDs(tweetsIDs []int64, requestingUserID int64) ([]*model.Tweet, error) {
+	tweets, err := s.readTweets(tweetsIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tweets, err = s.filterVisible(tweets, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// fill tweets with missing data
+	err = s.collectTweetsData(tweets, requestingUserID)
+	if err != nil {
+		return nil, errors.UnexpectedError
+	}
+
+	return tweets, nil
+}
+
+// readTweets reads tweets with given IDs from the cache or the database
+// without collecting any other data. Tweets which no longer exist are skipped.
+func (s *tweetsStorage) readTweets(tweetsIDs []int64) ([]*model.Tweet, error) {
 	tweets := make([]*model.Tweet, 0, len(tweetsIDs))
 
 	pool := async.NewWorkerPool(func(task async.Task) *async.Result {
@@ -251,7 +1069,7 @@ func (s *tweetsStorage) getTweetsByIDs(tweetsIDs []int64, requestingUserID int64
 		id := task.(int64)
 
 		key := cache.Key{"tweet", id}
-		if exists, _ := s.cache.GetSingle(key, tweet); !exists {
+		if exists, _ := s.cache.GetSingle(key, &tweet); !exists {
 			var err error
 
 			tweet, err = s.tweetsDAO.GetTweetByID(id)
@@ -272,18 +1090,45 @@ func (s *tweetsStorage) getTweetsByIDs(tweetsIDs []int64, requestingUserID int64
 
 	for range tweetsIDs {
 		result := pool.GetResult()
-		if result.Error != nil {
+		if result.Error == errors.NoResultsError {
+			// tweet was deleted after the list of IDs was fetched
+			// (eg. search index is refreshed periodically)
+			continue
+		} else if result.Error != nil {
 			return nil, errors.UnexpectedError
 		}
 
 		tweets = append(tweets, result.Value.(*model.Tweet))
 	}
 
-	// fill tweets with missing data
-	err := s.collectTweetsData(tweets, requestingUserID)
-	if err != nil {
-		return nil, errors.UnexpectedError
+	return tweets, nil
+}
+
+// isVisible checks if the requesting user can see the tweet. It uses only
+// data of the cached tweet and cached follows so it does not hit the database
+// for recently seen tweets.
+func (s *tweetsStorage) isVisible(tweet *model.Tweet, requestingUserID int64) (bool, error) {
+	if tweet.Visibility != model.VisibilityFollowers || tweet.Author.ID == requestingUserID {
+		return true, nil
 	}
 
-	return tweets, nil
+	return s.usersStorage.IsFollowing(requestingUserID, tweet.Author.ID)
+}
+
+// filterVisible returns tweets which can be seen by the requesting user
+// preserving their order.
+func (s *tweetsStorage) filterVisible(tweets []*model.Tweet, requestingUserID int64) ([]*model.Tweet, error) {
+	visibleTweets := make([]*model.Tweet, 0, len(tweets))
+	for _, tweet := range tweets {
+		visible, err := s.isVisible(tweet, requestingUserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if visible {
+			visibleTweets = append(visibleTweets, tweet)
+		}
+	}
+
+	return visibleTweets, nil
 }
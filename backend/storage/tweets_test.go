@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Tweets", func() {
+	var (
+		tweetsDAO *fakeTweetsDAO
+		storage   *tweetsStorage
+
+		publicTweet    *model.Tweet
+		followersTweet *model.Tweet
+		quoteTweet     *model.Tweet
+	)
+
+	BeforeEach(func() {
+		publicTweet = &model.Tweet{
+			ID:         1,
+			Author:     &model.PublicUser{ID: 10},
+			Content:    "public",
+			Visibility: model.VisibilityPublic,
+		}
+		followersTweet = &model.Tweet{
+			ID:         2,
+			Author:     &model.PublicUser{ID: 10},
+			Content:    "followers only",
+			Visibility: model.VisibilityFollowers,
+		}
+
+		quoteTweet = &model.Tweet{
+			ID:            3,
+			Author:        &model.PublicUser{ID: 20},
+			Content:       "quote",
+			Visibility:    model.VisibilityPublic,
+			QuotedTweetID: publicTweet.ID,
+		}
+
+		tweetsDAO = newFakeTweetsDAO(publicTweet, followersTweet, quoteTweet)
+		storage = &tweetsStorage{
+			tweetsDAO:    tweetsDAO,
+			likesDAO:     &fakeLikesDAO{},
+			retweetsDAO:  &fakeRetweetsDAO{},
+			bookmarksDAO: &fakeBookmarksDAO{},
+			mentionsDAO:  &fakeMentionsDAO{},
+			cache:        newMemoryCache(),
+			usersStorage: &fakeUsersStorage{followees: map[int64][]int64{20: {10}}},
+			mediaStorage: &fakeMediaStorage{},
+			pollsStorage: &fakePollsStorage{},
+			failedPurges: newFailedPurges(),
+		}
+	})
+
+	It("should read tweet from the database only once", func() {
+		for i := 0; i < 2; i++ {
+			tweet, err := storage.GetTweet(publicTweet.ID, 20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tweet.Content).To(Equal("public"))
+			Expect(tweet.Author.ID).To(Equal(int64(10)))
+		}
+
+		Expect(tweetsDAO.readCount(publicTweet.ID)).To(Equal(1))
+	})
+
+	It("should check visibility of cached tweet without the database", func() {
+		tweet, err := storage.GetTweet(followersTweet.ID, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tweet.Visibility).To(Equal(model.VisibilityFollowers))
+
+		_, err = storage.GetTweet(followersTweet.ID, 30)
+		Expect(err).To(HaveOccurred())
+
+		Expect(tweetsDAO.readCount(followersTweet.ID)).To(Equal(1))
+	})
+
+	It("should read listed and quoted tweets from the database only once", func() {
+		for i := 0; i < 2; i++ {
+			tweets, err := storage.getTweetsByIDs([]int64{quoteTweet.ID, followersTweet.ID}, 30)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tweets).To(HaveLen(1))
+			Expect(tweets[0].QuotedTweet.Content).To(Equal("public"))
+		}
+
+		Expect(tweetsDAO.readCount(quoteTweet.ID)).To(Equal(1))
+		Expect(tweetsDAO.readCount(publicTweet.ID)).To(Equal(1))
+		Expect(tweetsDAO.readCount(followersTweet.ID)).To(Equal(1))
+	})
+
+	It("should keep tweet whose media blobs could not be deleted", func() {
+		mediaStorage := &fakeMediaStorage{
+			media: map[int64][]*model.Media{
+				publicTweet.ID:    {{TweetID: publicTweet.ID, Key: "public"}},
+				followersTweet.ID: {{TweetID: followersTweet.ID, Key: "followers"}},
+			},
+			blobs:   map[string]bool{"public": true, "followers": true},
+			failing: map[string]bool{"followers": true},
+		}
+		storage.mediaStorage = mediaStorage
+
+		purged, err := storage.PurgeDeletedTweets(time.Hour, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(Equal(2))
+		Expect(mediaStorage.blobs).To(Equal(map[string]bool{"followers": true}))
+
+		Expect(tweetsDAO.tweets).To(HaveKey(followersTweet.ID))
+		Expect(tweetsDAO.tweets).NotTo(HaveKey(publicTweet.ID))
+		Expect(tweetsDAO.tweets).NotTo(HaveKey(quoteTweet.ID))
+
+		delete(mediaStorage.failing, "followers")
+		purged, err = storage.PurgeDeletedTweets(time.Hour, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(BeZero())
+		Expect(tweetsDAO.tweets).To(HaveKey(followersTweet.ID))
+
+		storage.failedPurges.failedAt[followersTweet.ID] = time.Now().Add(-purgeRetryDelay)
+		purged, err = storage.PurgeDeletedTweets(time.Hour, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(purged).To(Equal(1))
+		Expect(mediaStorage.blobs).To(BeEmpty())
+		Expect(tweetsDAO.tweets).To(BeEmpty())
+	})
+
+	It("should purge other tweets when media blobs of one could not be deleted", func() {
+		storage.mediaStorage = &fakeMediaStorage{
+			media: map[int64][]*model.Media{
+				followersTweet.ID: {{TweetID: followersTweet.ID, Key: "followers"}},
+			},
+			blobs:   map[string]bool{"followers": true},
+			failing: map[string]bool{"followers": true},
+		}
+
+		for i := 0; i < 3; i++ {
+			_, err := storage.PurgeDeletedTweets(time.Hour, 1)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(tweetsDAO.tweets).To(HaveLen(1))
+		Expect(tweetsDAO.tweets).To(HaveKey(followersTweet.ID))
+	})
+})
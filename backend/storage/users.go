@@ -19,15 +19,17 @@ type usersStorage struct {
 	followsDAO database.FollowsDAO
 	cache      cache.Accessor
 	fts        fulltextsearch.UsersSearcher
+	timelines  *timelinesStorage
 }
 
-// newUsersStorage constructs usersStorage that uses given usersDAO, followsDAO, Accessor and UsersSearcher
-func newUsersStorage(usersDAO database.UsersDAO, followsDAO database.FollowsDAO, cache cache.Accessor, fts fulltextsearch.UsersSearcher) usersDataAccessor {
+// newUsersStorage constructs usersStorage that uses given usersDAO, followsDAO, Accessor, UsersSearcher and timelinesStorage
+func newUsersStorage(usersDAO database.UsersDAO, followsDAO database.FollowsDAO, cache cache.Accessor, fts fulltextsearch.UsersSearcher, timelines *timelinesStorage) usersDataAccessor {
 	return &usersStorage{
 		usersDAO:   usersDAO,
 		followsDAO: followsDAO,
 		cache:      cache,
 		fts:        fts,
+		timelines:  timelines,
 	}
 }
 
@@ -38,7 +40,7 @@ func (s *usersStorage) GetUserByID(userID, requestingUserID int64) (*model.Publi
 	)
 
 	key := cache.Key{"user", userID}
-	if exists, _ := s.cache.GetSingle(key, user); !exists {
+	if exists, _ := s.cache.GetSingle(key, &user); !exists {
 		user, err = s.usersDAO.GetPublicUserByID(userID)
 		if err == errors.NoResultsError {
 			return nil, err
@@ -99,6 +101,32 @@ func (s *usersStorage) UpdateUserLastLoginTime(userID int64, lastLoginTime *time
 	return nil
 }
 
+func (s *usersStorage) PinTweet(userID, tweetID int64) error {
+	err := s.usersDAO.PinTweet(userID, tweetID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	s.cache.Delete(cache.Key{"user", userID})
+
+	return nil
+}
+
+// UnpinTweet clears pinned tweet of the user if it is the given tweet. It is
+// no-op when user has pinned other tweet or has not pinned anything.
+func (s *usersStorage) UnpinTweet(userID, tweetID int64) error {
+	unpinned, err := s.usersDAO.UnpinTweet(userID, tweetID)
+	if err != nil {
+		return errors.UnexpectedError
+	}
+
+	if unpinned {
+		s.cache.Delete(cache.Key{"user", userID})
+	}
+
+	return nil
+}
+
 func (s *usersStorage) FollowUser(followeeID, followerID int64) error {
 	followed, err := s.followsDAO.FollowUser(followeeID, followerID)
 	if err != nil {
@@ -106,8 +134,10 @@ func (s *usersStorage) FollowUser(followeeID, followerID int64) error {
 	}
 
 	if followed {
-		s.cache.SAdd(cache.Key{"user", followeeID, "followers.ids"}, followerID)
-		s.cache.SAdd(cache.Key{"user", followeeID, "followees.ids"}, followeeID)
+		s.cache.Delete(cache.Key{"user", followerID, "followees.ids"})
+		// visibility of followers-only tweets depends on it
+		s.cache.Set(cache.Entry{cache.Key{"user", followeeID, "is.followed.by", followerID}, true})
+		s.timelines.reset(followerID)
 	}
 
 	return nil
@@ -120,48 +150,64 @@ func (s *usersStorage) UnfollowUser(followeeID, followerID int64) error {
 	}
 
 	if unfollowed {
-		s.cache.SAdd(cache.Key{"user", followeeID, "followers.ids"}, followerID)
-		s.cache.SAdd(cache.Key{"user", followeeID, "followees.ids"}, followeeID)
+		s.cache.Delete(cache.Key{"user", followerID, "followees.ids"})
+		s.cache.Set(cache.Entry{cache.Key{"user", followeeID, "is.followed.by", followerID}, false})
+		s.timelines.reset(followerID)
 	}
 
 	return nil
 }
 
-func (s *usersStorage) GetFollowers(userID, requestingUserID int64) ([]*model.PublicUser, error) {
-	followersIDs := make([]int64, 0)
+// IsFollowing checks if follower follows followee. The result is shared with
+// `following` flag of the followee so it is usually already cached.
+func (s *usersStorage) IsFollowing(followerID, followeeID int64) (bool, error) {
+	var following bool
 
-	key := cache.Key{"user", userID, "followers.ids"}
-	if exists, _ := s.cache.GetSingle(key, &followersIDs); !exists {
+	key := cache.Key{"user", followeeID, "is.followed.by", followerID}
+	if exists, _ := s.cache.GetSingle(key, &following); !exists {
 		var err error
 
-		followersIDs, err = s.followsDAO.GetFollowersIDs(userID)
+		following, err = s.followsDAO.IsFollowing(followerID, followeeID)
 		if err != nil {
-			return nil, errors.UnexpectedError
+			return false, errors.UnexpectedError
 		}
 
-		s.cache.Set(cache.Entry{key, followersIDs})
+		s.cache.Set(cache.Entry{key, following})
 	}
 
-	followers, err := s.getUsersByIDs(followersIDs, requestingUserID)
+	return following, nil
+}
+
+// GetFollowers returns single page of followers of the user, most recent
+// followers first.
+func (s *usersStorage) GetFollowers(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	followersIDs, nextCursor, err := s.followsDAO.GetFollowersIDs(userID, cursor, limit)
 	if err != nil {
-		return nil, errors.UnexpectedError
+		return nil, nil, errors.UnexpectedError
 	}
 
-	return followers, nil
+	followers, err := s.GetUsersByIDs(followersIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
+
+	return sortUsersByIDs(followers, followersIDs), nextCursor, nil
 }
 
-func (s *usersStorage) GetFollowees(userID, requestingUserID int64) ([]*model.PublicUser, error) {
-	followeesIDs, err := s.GetFolloweesIDs(userID)
+// GetFollowees returns single page of users followed by the user, most
+// recently followed first.
+func (s *usersStorage) GetFollowees(userID, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	followeesIDs, nextCursor, err := s.followsDAO.GetFolloweesIDs(userID, cursor, limit)
 	if err != nil {
-		return nil, err
+		return nil, nil, errors.UnexpectedError
 	}
 
-	followees, err := s.getUsersByIDs(followeesIDs, requestingUserID)
+	followees, err := s.GetUsersByIDs(followeesIDs, requestingUserID)
 	if err != nil {
-		return nil, errors.UnexpectedError
+		return nil, nil, errors.UnexpectedError
 	}
 
-	return followees, nil
+	return sortUsersByIDs(followees, followeesIDs), nextCursor, nil
 }
 
 func (s *usersStorage) GetFolloweesIDs(userID int64) ([]int64, error) {
@@ -171,7 +217,7 @@ func (s *usersStorage) GetFolloweesIDs(userID int64) ([]int64, error) {
 	if exists, _ := s.cache.SMembers(key, &followeesIDs); !exists {
 		var err error
 
-		followeesIDs, err = s.followsDAO.GetFolloweesIDs(userID)
+		followeesIDs, err = s.followsDAO.GetAllFolloweesIDs(userID)
 		if err != nil {
 			return nil, errors.UnexpectedError
 		}
@@ -182,22 +228,20 @@ func (s *usersStorage) GetFolloweesIDs(userID int64) ([]int64, error) {
 	return followeesIDs, nil
 }
 
-func (s *usersStorage) GetUsersUsingQueryString(querystring string, requestingUserID int64) ([]*model.PublicUser, error) {
-	usersIDs := make([]int64, 0)
-
-	key := cache.Key{"users", "querystring", querystring}
-	if exists, _ := s.cache.GetSingle(key, &usersIDs); !exists {
-		var err error
-
-		usersIDs, err = s.fts.GetUsersIDs(querystring)
-		if err != nil {
-			return nil, errors.UnexpectedError
-		}
+// GetUsersUsingQueryString returns single page of users matching the
+// `querystring`, newest first.
+func (s *usersStorage) GetUsersUsingQueryString(querystring string, requestingUserID int64, cursor *model.Cursor, limit int) ([]*model.PublicUser, *model.Cursor, error) {
+	usersIDs, nextCursor, err := s.fts.GetUsersIDs(querystring, cursor, limit)
+	if err != nil {
+		return nil, nil, errors.UnexpectedError
+	}
 
-		s.cache.Set(cache.Entry{key, usersIDs})
+	users, err := s.GetUsersByIDs(usersIDs, requestingUserID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return s.getUsersByIDs(usersIDs, requestingUserID)
+	return sortUsersByIDs(users, usersIDs), nextCursor, nil
 }
 
 func (s *usersStorage) collectPublicUsersData(users []*model.PublicUser, requestingUserID int64) error {
@@ -266,7 +310,9 @@ func (s *usersStorage) collectPublicUserData(user *model.PublicUser, requestingU
 	return nil
 }
 
-func (s *usersStorage) getUsersByIDs(usersIDs []int64, requestingUserID int64) ([]*model.PublicUser, error) {
+// GetUsersByIDs returns users with given IDs. Users are not returned in the
+// same order as IDs.
+func (s *usersStorage) GetUsersByIDs(usersIDs []int64, requestingUserID int64) ([]*model.PublicUser, error) {
 	users := make([]*model.PublicUser, 0, len(usersIDs))
 
 	pool := async.NewWorkerPool(func(task async.Task) *async.Result {
@@ -274,7 +320,7 @@ func (s *usersStorage) getUsersByIDs(usersIDs []int64, requestingUserID int64) (
 		id := task.(int64)
 
 		key := cache.Key{"user", id}
-		if exists, _ := s.cache.GetSingle(key, user); !exists {
+		if exists, _ := s.cache.GetSingle(key, &user); !exists {
 			var err error
 
 			user, err = s.usersDAO.GetPublicUserByID(id)
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("Users", func() {
+	var (
+		usersDAO *fakeUsersDAO
+		storage  *usersStorage
+	)
+
+	BeforeEach(func() {
+		followsDAO := &fakeFollowsDAO{release: make(chan struct{})}
+		close(followsDAO.release)
+
+		usersDAO = newFakeUsersDAO(&model.PublicUser{ID: 10, Username: "ala", PinnedTweetID: 1})
+		storage = &usersStorage{
+			usersDAO:   usersDAO,
+			followsDAO: followsDAO,
+			cache:      newMemoryCache(),
+		}
+	})
+
+	It("should read user from the database only once", func() {
+		for i := 0; i < 2; i++ {
+			user, err := storage.GetUserByID(10, 20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Username).To(Equal("ala"))
+			Expect(user.PinnedTweetID).To(Equal(int64(1)))
+
+			users, err := storage.GetUsersByIDs([]int64{10}, 20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(users).To(HaveLen(1))
+			Expect(users[0].Username).To(Equal("ala"))
+		}
+
+		Expect(usersDAO.readCount(10)).To(Equal(1))
+	})
+
+	It("should invalidate cached user when pinned tweet is deleted", func() {
+		for i := 0; i < 2; i++ {
+			user, err := storage.GetUserByID(10, 20)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.PinnedTweetID).To(Equal(int64(1)))
+		}
+		Expect(usersDAO.readCount(10)).To(Equal(1))
+
+		err := storage.UnpinTweet(10, 1)
+		Expect(err).NotTo(HaveOccurred())
+
+		user, err := storage.GetUserByID(10, 20)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(user.PinnedTweetID).To(BeZero())
+		Expect(usersDAO.readCount(10)).To(Equal(2))
+	})
+})
@@ -0,0 +1,38 @@
+package storage
+
+import "github.com/VirrageS/chirp/backend/model"
+
+// sortTweetsByIDs sorts tweets in the same order as given IDs. Some of the
+// IDs can be missing from the tweets (eg. tweet was deleted or is hidden).
+func sortTweetsByIDs(tweets []*model.Tweet, ids []int64) []*model.Tweet {
+	tweetsByIDs := make(map[int64]*model.Tweet, len(tweets))
+	for _, tweet := range tweets {
+		tweetsByIDs[tweet.ID] = tweet
+	}
+
+	sorted := make([]*model.Tweet, 0, len(tweets))
+	for _, id := range ids {
+		if tweet, ok := tweetsByIDs[id]; ok {
+			sorted = append(sorted, tweet)
+		}
+	}
+
+	return sorted
+}
+
+// sortUsersByIDs sorts users in the same order as given IDs.
+func sortUsersByIDs(users []*model.PublicUser, ids []int64) []*model.PublicUser {
+	usersByIDs := make(map[int64]*model.PublicUser, len(users))
+	for _, user := range users {
+		usersByIDs[user.ID] = user
+	}
+
+	sorted := make([]*model.PublicUser, 0, len(users))
+	for _, id := range ids {
+		if user, ok := usersByIDs[id]; ok {
+			sorted = append(sorted, user)
+		}
+	}
+
+	return sorted
+}
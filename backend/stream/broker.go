@@ -0,0 +1,32 @@
+package stream
+
+// Broker is interface which defines functions used by the hub to exchange
+// events with hubs of all processes.
+type Broker interface {
+	// Publish sends event published on the topic to all listeners.
+	Publish(topic string, event *Event) error
+	// Listen starts calling `dispatch` for each of the published events.
+	Listen(dispatch func(topic string, event *Event))
+}
+
+type localBroker struct {
+	dispatch func(topic string, event *Event)
+}
+
+// NewLocalBroker creates broker which delivers events only inside the
+// process. It should be used when there is single instance of the server.
+func NewLocalBroker() Broker {
+	return &localBroker{}
+}
+
+func (broker *localBroker) Publish(topic string, event *Event) error {
+	if broker.dispatch != nil {
+		broker.dispatch(topic, event)
+	}
+
+	return nil
+}
+
+func (broker *localBroker) Listen(dispatch func(topic string, event *Event)) {
+	broker.dispatch = dispatch
+}
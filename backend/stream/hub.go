@@ -0,0 +1,223 @@
+package stream
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Types of events sent to the clients.
+const (
+	TweetCreated     = "tweet"
+	TweetDeleted     = "delete"
+	LikeCountChanged = "like_count"
+	Notification     = "notification"
+	Typing           = "typing"
+)
+
+// Kinds of notifications.
+const (
+	LikeNotification    = "like"
+	RetweetNotification = "retweet"
+	FollowNotification  = "follow"
+	MentionNotification = "mention"
+	ReplyNotification   = "reply"
+)
+
+// Event is a single update sent to the clients. Data is encoded as JSON.
+type Event struct {
+	Type string
+	Data interface{}
+}
+
+// TweetDeletedData is data of the `TweetDeleted` event.
+type TweetDeletedData struct {
+	TweetID int64 `json:"tweet_id"`
+}
+
+// LikeCountChangedData is data of the `LikeCountChanged` event.
+type LikeCountChangedData struct {
+	TweetID   int64 `json:"tweet_id"`
+	LikeCount int64 `json:"like_count"`
+}
+
+// NotificationData is data of the `Notification` event. `UserID` is ID of the
+// user who caused the notification.
+type NotificationData struct {
+	Kind    string `json:"kind"`
+	UserID  int64  `json:"user_id"`
+	TweetID int64  `json:"tweet_id,omitempty"`
+}
+
+// TypingData is data of the `Typing` event.
+type TypingData struct {
+	UserID int64 `json:"user_id"`
+}
+
+// UserTopic returns topic of events about tweets of the user.
+func UserTopic(userID int64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// InboxTopic returns topic of events addressed to the user (eg. notifications).
+func InboxTopic(userID int64) string {
+	return fmt.Sprintf("inbox:%d", userID)
+}
+
+// Hub delivers events published on topics to subscriptions of this process.
+// Events are published through the broker so subscriptions in all processes
+// sharing the broker receive them.
+type Hub struct {
+	mutex         sync.Mutex
+	broker        Broker
+	bufferSize    int
+	subscriptions map[string]map[*Subscription]bool
+	closed        bool
+}
+
+// Subscription receives events published on any of its topics.
+type Subscription struct {
+	hub    *Hub
+	topics []string
+	filter func(*Event) bool
+	events chan *Event
+	closed bool
+}
+
+// NewHub creates new hub which publishes events through `broker`. Each
+// subscription buffers at most `bufferSize` events.
+func NewHub(broker Broker, bufferSize int) *Hub {
+	hub := &Hub{
+		broker:        broker,
+		bufferSize:    bufferSize,
+		subscriptions: make(map[string]map[*Subscription]bool),
+	}
+
+	broker.Listen(hub.dispatch)
+	return hub
+}
+
+// Publish publishes event on the topic. Events are delivered on best effort
+// basis so errors are only logged by the broker.
+func (hub *Hub) Publish(topic string, event *Event) {
+	hub.broker.Publish(topic, event)
+}
+
+// Subscribe creates subscription of events published on any of the topics.
+// Subscription has to be closed when it is no longer used. Subscriptions
+// created after the hub was closed are already closed.
+func (hub *Hub) Subscribe(topics ...string) *Subscription {
+	return hub.SubscribeFiltered(nil, topics...)
+}
+
+// SubscribeFiltered works like `Subscribe` but delivers only events accepted
+// by `filter`. Filter is called while events are dispatched so it should not
+// block. Events received from other processes carry data as JSON, except
+// created tweets which are decoded into `*model.Tweet`.
+func (hub *Hub) SubscribeFiltered(filter func(*Event) bool, topics ...string) *Subscription {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	subscription := &Subscription{
+		hub:    hub,
+		topics: topics,
+		filter: filter,
+		events: make(chan *Event, hub.bufferSize),
+	}
+
+	if hub.closed {
+		subscription.closed = true
+		close(subscription.events)
+		return subscription
+	}
+
+	for _, topic := range topics {
+		if hub.subscriptions[topic] == nil {
+			hub.subscriptions[topic] = make(map[*Subscription]bool)
+		}
+		hub.subscriptions[topic][subscription] = true
+	}
+
+	return subscription
+}
+
+// Close closes all subscriptions so their subscribers stop waiting for
+// events. It is used when the server shuts down.
+func (hub *Hub) Close() {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	hub.closed = true
+	for _, subscriptions := range hub.subscriptions {
+		for subscription := range subscriptions {
+			hub.unsubscribe(subscription)
+		}
+	}
+}
+
+// dispatch delivers event to subscriptions of the topic. Subscriptions which
+// can not keep up with the events are closed instead of blocking the others.
+func (hub *Hub) dispatch(topic string, event *Event) {
+	hub.mutex.Lock()
+	subscriptions := make([]*Subscription, 0, len(hub.subscriptions[topic]))
+	for subscription := range hub.subscriptions[topic] {
+		subscriptions = append(subscriptions, subscription)
+	}
+	hub.mutex.Unlock()
+
+	// filters are called without the lock so they do not hold up
+	// dispatching of other events nor (un)subscribing
+	accepted := subscriptions[:0]
+	for _, subscription := range subscriptions {
+		if subscription.filter == nil || subscription.filter(event) {
+			accepted = append(accepted, subscription)
+		}
+	}
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for _, subscription := range accepted {
+		// subscription could be closed while the filters were called
+		if subscription.closed {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			hub.unsubscribe(subscription)
+		}
+	}
+}
+
+func (hub *Hub) unsubscribe(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+
+	for _, topic := range subscription.topics {
+		delete(hub.subscriptions[topic], subscription)
+		if len(hub.subscriptions[topic]) == 0 {
+			delete(hub.subscriptions, topic)
+		}
+	}
+
+	subscription.closed = true
+	close(subscription.events)
+}
+
+// Events returns channel of the events. The channel is closed when the
+// subscription is closed (also when the subscriber was too slow).
+func (subscription *Subscription) Events() <-chan *Event {
+	return subscription.events
+}
+
+// Close stops delivering events to the subscription.
+func (subscription *Subscription) Close() {
+	hub := subscription.hub
+
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	hub.unsubscribe(subscription)
+}
@@ -0,0 +1,110 @@
+package stream
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hub", func() {
+	var hub *Hub
+
+	event := func(tweetID int64) *Event {
+		return &Event{Type: TweetDeleted, Data: &TweetDeletedData{TweetID: tweetID}}
+	}
+
+	BeforeEach(func() {
+		hub = NewHub(NewLocalBroker(), 2)
+	})
+
+	It("should deliver events published on subscribed topics", func() {
+		subscription := hub.Subscribe(UserTopic(1), UserTopic(2))
+		defer subscription.Close()
+
+		hub.Publish(UserTopic(2), event(1))
+		hub.Publish(UserTopic(3), event(2))
+
+		Expect(subscription.Events()).To(Receive(Equal(event(1))))
+		Expect(subscription.Events()).NotTo(Receive())
+	})
+
+	It("should deliver events to all subscriptions of the topic", func() {
+		first := hub.Subscribe(UserTopic(1))
+		defer first.Close()
+		second := hub.Subscribe(UserTopic(1))
+		defer second.Close()
+
+		hub.Publish(UserTopic(1), event(1))
+
+		Expect(first.Events()).To(Receive(Equal(event(1))))
+		Expect(second.Events()).To(Receive(Equal(event(1))))
+	})
+
+	It("should stop delivering events after subscription is closed", func() {
+		subscription := hub.Subscribe(UserTopic(1))
+		subscription.Close()
+		subscription.Close()
+
+		hub.Publish(UserTopic(1), event(1))
+
+		Expect(subscription.Events()).To(BeClosed())
+	})
+
+	It("should close subscription which can not keep up with events", func() {
+		slow := hub.Subscribe(UserTopic(1))
+		defer slow.Close()
+		fast := hub.Subscribe(UserTopic(1))
+		defer fast.Close()
+
+		for i := int64(1); i <= 3; i++ {
+			hub.Publish(UserTopic(1), event(i))
+			Eventually(fast.Events()).Should(Receive())
+		}
+
+		Expect(slow.Events()).To(Receive(Equal(event(1))))
+		Expect(slow.Events()).To(Receive(Equal(event(2))))
+		Expect(slow.Events()).To(BeClosed())
+	})
+
+	It("should deliver only events accepted by filter of subscription", func() {
+		subscription := hub.SubscribeFiltered(func(e *Event) bool {
+			return e.Data.(*TweetDeletedData).TweetID != 2
+		}, UserTopic(1))
+		defer subscription.Close()
+
+		for i := int64(1); i <= 3; i++ {
+			hub.Publish(UserTopic(1), event(i))
+		}
+
+		Expect(subscription.Events()).To(Receive(Equal(event(1))))
+		Expect(subscription.Events()).To(Receive(Equal(event(3))))
+		Expect(subscription.Events()).NotTo(Receive())
+	})
+
+	It("should not hold the hub while filtering events", func() {
+		subscription := hub.SubscribeFiltered(func(e *Event) bool {
+			// subscribing would block if the hub was held by dispatch
+			hub.Subscribe(UserTopic(2)).Close()
+			return true
+		}, UserTopic(1))
+		defer subscription.Close()
+
+		hub.Publish(UserTopic(1), event(1))
+
+		Expect(subscription.Events()).To(Receive(Equal(event(1))))
+	})
+
+	It("should close all subscriptions when hub is closed", func() {
+		first := hub.Subscribe(UserTopic(1), UserTopic(2))
+		defer first.Close()
+		second := hub.Subscribe(UserTopic(2))
+		defer second.Close()
+
+		hub.Close()
+		late := hub.Subscribe(UserTopic(1))
+		defer late.Close()
+
+		Expect(first.Events()).To(BeClosed())
+		Expect(second.Events()).To(BeClosed())
+		Expect(late.Events()).To(BeClosed())
+	})
+})
@@ -0,0 +1,132 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"gopkg.in/redis.v5"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+const (
+	eventsChannel = "stream:events"
+
+	// retryDelay is time to wait before receiving again after Redis error.
+	retryDelay = time.Second
+)
+
+// message is the form in which events are sent through Redis.
+type message struct {
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type redisBroker struct {
+	client *redis.Client
+}
+
+// NewRedisBroker constructs broker which sends events through Redis pub/sub
+// so they are delivered to hubs of all servers using the same Redis.
+func NewRedisBroker(config config.RedisConfigProvider) Broker {
+	address := fmt.Sprintf("%s:%s", config.GetHost(), config.GetPort())
+
+	client := redis.NewClient(&redis.Options{
+		Addr:       address,
+		Password:   config.GetPassword(),
+		DB:         config.GetDB(),
+		MaxRetries: 3,
+	})
+
+	if _, err := client.Ping().Result(); err != nil {
+		log.WithError(err).Error("Error connecting to stream instance.")
+		return nil
+	}
+
+	return &redisBroker{
+		client: client,
+	}
+}
+
+func (broker *redisBroker) Publish(topic string, event *Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		log.WithField("event", event).WithError(err).Error("Publish: failed to encode event data")
+		return err
+	}
+
+	payload, err := json.Marshal(&message{Topic: topic, Type: event.Type, Data: data})
+	if err != nil {
+		log.WithField("event", event).WithError(err).Error("Publish: failed to encode event")
+		return err
+	}
+
+	if err := broker.client.Publish(eventsChannel, string(payload)).Err(); err != nil {
+		log.WithField("topic", topic).WithError(err).Error("Publish: failed to publish event")
+		return err
+	}
+
+	return nil
+}
+
+// Listen subscribes to the channel of events and receives them in the
+// background. Events published while the connection to Redis is broken are
+// lost.
+func (broker *redisBroker) Listen(dispatch func(topic string, event *Event)) {
+	pubsub, err := broker.client.Subscribe(eventsChannel)
+
+	go func() {
+		for {
+			if err != nil {
+				log.WithError(err).Error("Listen: failed to subscribe to events")
+				time.Sleep(retryDelay)
+			} else {
+				broker.receive(pubsub, dispatch)
+				pubsub.Close()
+			}
+
+			pubsub, err = broker.client.Subscribe(eventsChannel)
+		}
+	}()
+}
+
+// receive dispatches messages until the subscription fails.
+func (broker *redisBroker) receive(pubsub *redis.PubSub, dispatch func(topic string, event *Event)) {
+	for {
+		msg, err := pubsub.ReceiveMessage()
+		if err != nil {
+			log.WithError(err).Error("Listen: failed to receive event")
+			time.Sleep(retryDelay)
+			return
+		}
+
+		var m message
+		if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+			log.WithField("payload", msg.Payload).WithError(err).Error("Listen: invalid event")
+			continue
+		}
+
+		dispatch(m.Topic, decodeEvent(&m))
+	}
+}
+
+// decodeEvent creates event from the message. Created tweets are decoded
+// here, once per event, so subscriptions can filter them without decoding.
+// Data of other events is left as JSON.
+func decodeEvent(m *message) *Event {
+	if m.Type != TweetCreated {
+		return &Event{Type: m.Type, Data: m.Data}
+	}
+
+	var tweet model.Tweet
+	if err := json.Unmarshal(m.Data, &tweet); err != nil {
+		log.WithField("data", string(m.Data)).WithError(err).Error("Listen: invalid data of created tweet")
+		return &Event{Type: m.Type, Data: m.Data}
+	}
+
+	return &Event{Type: m.Type, Data: &tweet}
+}
@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/VirrageS/chirp/backend/config"
+	"github.com/VirrageS/chirp/backend/model"
+)
+
+var _ = Describe("RedisBroker", func() {
+	var (
+		conf *config.Configuration = config.New()
+		hub                        = NewHub(NewRedisBroker(conf.Redis), 10)
+		// other hub has its own connection like a hub of another server
+		otherHub = NewHub(NewRedisBroker(conf.Redis), 10)
+	)
+
+	It("should deliver events to hubs of all servers", func() {
+		subscription := hub.Subscribe(UserTopic(1))
+		defer subscription.Close()
+		otherSubscription := otherHub.Subscribe(UserTopic(1))
+		defer otherSubscription.Close()
+
+		data := &LikeCountChangedData{TweetID: 1, LikeCount: 2}
+		expectedData, _ := json.Marshal(data)
+
+		for _, s := range []*Subscription{subscription, otherSubscription} {
+			// subscribing to Redis is asynchronous so the first events can be lost
+			var event *Event
+			Eventually(func() *Event {
+				hub.Publish(UserTopic(1), &Event{Type: LikeCountChanged, Data: data})
+
+				select {
+				case event = <-s.Events():
+				case <-time.After(100 * time.Millisecond):
+				}
+				return event
+			}).ShouldNot(BeNil())
+
+			Expect(event.Type).To(Equal(LikeCountChanged))
+			Expect(event.Data).To(BeAssignableToTypeOf(json.RawMessage{}))
+			Expect([]byte(event.Data.(json.RawMessage))).To(MatchJSON(expectedData))
+		}
+	})
+
+	It("should decode data of created tweets", func() {
+		subscription := otherHub.Subscribe(UserTopic(2))
+		defer subscription.Close()
+
+		tweet := &model.Tweet{ID: 1, Content: "tweet", Author: &model.PublicUser{ID: 2}}
+
+		var event *Event
+		Eventually(func() *Event {
+			hub.Publish(UserTopic(2), &Event{Type: TweetCreated, Data: tweet})
+
+			select {
+			case event = <-subscription.Events():
+			case <-time.After(100 * time.Millisecond):
+			}
+			return event
+		}).ShouldNot(BeNil())
+
+		Expect(event.Data).To(BeAssignableToTypeOf(&model.Tweet{}))
+		Expect(event.Data.(*model.Tweet).ID).To(Equal(tweet.ID))
+		Expect(event.Data.(*model.Tweet).Author.ID).To(Equal(tweet.Author.ID))
+	})
+})
@@ -6,13 +6,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/VirrageS/chirp/backend/entities"
 	"github.com/VirrageS/chirp/backend/model"
 	"github.com/VirrageS/chirp/backend/server"
+	"github.com/VirrageS/chirp/backend/storage"
 	"github.com/VirrageS/chirp/backend/storage/database"
 	"github.com/VirrageS/chirp/backend/token"
 	"github.com/VirrageS/chirp/backend/utils"
@@ -22,6 +27,7 @@ var _ = Describe("ServerTest", func() {
 	var (
 		router       *gin.Engine
 		db           *database.Connection
+		accessor     storage.Accessor
 		tokenManager token.Manager
 
 		ala             *model.User
@@ -40,6 +46,7 @@ var _ = Describe("ServerTest", func() {
 		fakeServer := server.NewFakeServer()
 		router = fakeServer.Server
 		db = fakeServer.Storage.Database
+		accessor = fakeServer.Storage.Storage
 		tokenManager = fakeServer.TokenManager
 
 		// create users
@@ -63,6 +70,16 @@ var _ = Describe("ServerTest", func() {
 			DELETE FROM follows;
 			DELETE FROM likes;
 			DELETE FROM retweets;
+			DELETE FROM tweet_hashtags;
+			DELETE FROM mentions;
+			DELETE FROM media;
+			DELETE FROM scheduled_tweets;
+			DELETE FROM polls;
+			DELETE FROM bookmarks;
+			DELETE FROM tweet_stats;
+			DELETE FROM lists;
+			DELETE FROM mutes;
+			DELETE FROM muted_words;
 		`)
 	})
 
@@ -384,6 +401,21 @@ var _ = Describe("ServerTest", func() {
 
 			Expect(actualFollowers).To(ConsistOf(expectedFollowers))
 		})
+
+		It("should get followers page by page, most recent first", func() {
+			followUser(router, toor.ID, alaToken)
+			followUser(router, toor.ID, bobToken)
+
+			page := retrieveFollowersPage(router, toor.ID, "", 1, alaToken)
+			Expect(page.Users).To(HaveLen(1))
+			Expect(page.Users[0].ID).To(Equal(bob.ID))
+			Expect(page.NextCursor).NotTo(BeEmpty())
+
+			page = retrieveFollowersPage(router, toor.ID, page.NextCursor, 1, alaToken)
+			Expect(page.Users).To(HaveLen(1))
+			Expect(page.Users[0].ID).To(Equal(ala.ID))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
 	})
 
 	Describe("Get followees", func() {
@@ -421,6 +453,21 @@ var _ = Describe("ServerTest", func() {
 
 			Expect(actualFollowers).To(ConsistOf(expectedFollowees))
 		})
+
+		It("should get followees page by page, most recently followed first", func() {
+			followUser(router, toor.ID, alaToken)
+			followUser(router, ernest.ID, alaToken)
+
+			page := retrieveFolloweesPage(router, ala.ID, "", 1, alaToken)
+			Expect(page.Users).To(HaveLen(1))
+			Expect(page.Users[0].ID).To(Equal(ernest.ID))
+			Expect(page.NextCursor).NotTo(BeEmpty())
+
+			page = retrieveFolloweesPage(router, ala.ID, page.NextCursor, 1, alaToken)
+			Expect(page.Users).To(HaveLen(1))
+			Expect(page.Users[0].ID).To(Equal(toor.ID))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
 	})
 
 	Describe("Get users tweets", func() {
@@ -442,6 +489,237 @@ var _ = Describe("ServerTest", func() {
 			Expect(alaActualTweets).To(ConsistOf(alaExpectedTweets))
 			Expect(bobActualTweets).To(ConsistOf(bobExpectedTweets))
 		})
+
+		It("should get tweets page by page, newest first", func() {
+			first := createTweet(router, "first", alaToken)
+			second := createTweet(router, "second", alaToken)
+			third := createTweet(router, "third", alaToken)
+
+			page := retrieveUserTweetsPage(router, ala.ID, "", 2, bobToken)
+			Expect(page.Tweets).To(HaveLen(2))
+			Expect(page.Tweets[0].ID).To(Equal(third.ID))
+			Expect(page.Tweets[1].ID).To(Equal(second.ID))
+			Expect(page.NextCursor).NotTo(BeEmpty())
+
+			page = retrieveUserTweetsPage(router, ala.ID, page.NextCursor, 2, bobToken)
+			Expect(page.Tweets).To(HaveLen(1))
+			Expect(page.Tweets[0].ID).To(Equal(first.ID))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("should show pinned tweet only at the top of the first page", func() {
+			first := createTweet(router, "first", alaToken)
+			second := createTweet(router, "second", alaToken)
+			third := createTweet(router, "third", alaToken)
+			pinTweet(router, first.ID, alaToken)
+
+			page := retrieveUserTweetsPage(router, ala.ID, "", 2, bobToken)
+			Expect(page.Tweets).To(HaveLen(3))
+			Expect(page.Tweets[0].ID).To(Equal(first.ID))
+			Expect(page.Tweets[1].ID).To(Equal(third.ID))
+			Expect(page.Tweets[2].ID).To(Equal(second.ID))
+
+			page = retrieveUserTweetsPage(router, ala.ID, page.NextCursor, 2, bobToken)
+			Expect(page.Tweets).To(BeEmpty())
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("should reject invalid cursor", func() {
+			req := request("GET", fmt.Sprintf("/users/%v/tweets", ala.ID), nil).authorize(alaToken).
+				urlQuery("cursor", "invalid").build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Tweet visibility", func() {
+		It("should create public tweets by default", func() {
+			tweet := createTweet(router, "tweet", alaToken)
+			Expect(tweet.Visibility).To(Equal(model.VisibilityPublic))
+		})
+
+		It("should show followers-only tweet only to author and followers", func() {
+			tweet := createTweetWithVisibility(router, "secret", model.VisibilityFollowers, alaToken)
+			Expect(tweet.Visibility).To(Equal(model.VisibilityFollowers))
+
+			req := request("GET", fmt.Sprintf("/tweets/%v", tweet.ID), nil).authorize(bobToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+
+			Expect(retrieveTweet(router, tweet.ID, alaToken).ID).To(Equal(tweet.ID))
+
+			followUser(router, ala.ID, bobToken)
+			Expect(retrieveTweet(router, tweet.ID, bobToken).ID).To(Equal(tweet.ID))
+		})
+
+		It("should hide followers-only tweets from user tweets of non-followers", func() {
+			public := createTweet(router, "public", alaToken)
+			createTweetWithVisibility(router, "secret", model.VisibilityFollowers, alaToken)
+
+			tweets := retrieveUserTweets(router, bobToken, ala.ID)
+			Expect(tweets).To(HaveLen(1))
+			Expect(tweets[0].ID).To(Equal(public.ID))
+
+			Expect(retrieveUserTweets(router, alaToken, ala.ID)).To(HaveLen(2))
+		})
+
+		It("should hide followers-only tweets after unfollowing", func() {
+			followUser(router, ala.ID, bobToken)
+			tweet := createTweetWithVisibility(router, "secret", model.VisibilityFollowers, alaToken)
+			Expect(retrieveFeed(router, bobToken)).To(HaveLen(1))
+
+			unfollowUser(router, ala.ID, bobToken)
+
+			Expect(retrieveUserTweets(router, bobToken, ala.ID)).To(BeEmpty())
+			req := request("GET", fmt.Sprintf("/tweets/%v", tweet.ID), nil).authorize(bobToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should hide retweets of followers-only tweets from non-followers feed", func() {
+			toorToken, _ := loginUser(router, toor)
+
+			followUser(router, ala.ID, bobToken)
+			followUser(router, bob.ID, toorToken)
+
+			tweet := createTweetWithVisibility(router, "secret", model.VisibilityFollowers, alaToken)
+			retweetTweet(router, tweet.ID, bobToken)
+
+			Expect(retrieveFeed(router, toorToken)).To(BeEmpty())
+		})
+
+		It("should return placeholder when quoted tweet can not be seen", func() {
+			tweet := createTweetWithVisibility(router, "secret", model.VisibilityFollowers, alaToken)
+			followUser(router, ala.ID, bobToken)
+			quote := createQuote(router, "quote", tweet.ID, bobToken)
+
+			toorToken, _ := loginUser(router, toor)
+			quote = retrieveTweet(router, quote.ID, toorToken)
+			Expect(quote.QuotedTweet.Unavailable).To(BeTrue())
+			Expect(quote.QuotedTweet.Content).To(BeEmpty())
+		})
+
+		It("should return bad request code for unknown visibility", func() {
+			newTweet := &model.NewTweet{Content: "tweet", Visibility: "friends"}
+			req := request("POST", "/tweets", body(newTweet)).json().authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Pinned tweet", func() {
+		It("should return pinned tweet with user", func() {
+			tweet := createTweet(router, "pinned", alaToken)
+
+			Expect(pinTweet(router, tweet.ID, alaToken).PinnedTweet).To(Equal(tweet))
+			Expect(retrieveUser(router, ala.ID, bobToken).PinnedTweet.ID).To(Equal(tweet.ID))
+		})
+
+		It("should put pinned tweet first in user tweets", func() {
+			pinned := createTweet(router, "pinned", alaToken)
+			createTweet(router, "tweet1", alaToken)
+			createTweet(router, "tweet2", alaToken)
+
+			pinTweet(router, pinned.ID, alaToken)
+
+			tweets := retrieveUserTweets(router, bobToken, ala.ID)
+			Expect(tweets).To(HaveLen(3))
+			Expect(tweets[0].ID).To(Equal(pinned.ID))
+		})
+
+		It("should replace previously pinned tweet", func() {
+			first := createTweet(router, "first", alaToken)
+			second := createTweet(router, "second", alaToken)
+
+			pinTweet(router, first.ID, alaToken)
+			pinTweet(router, second.ID, alaToken)
+
+			Expect(retrieveUser(router, ala.ID, alaToken).PinnedTweet.ID).To(Equal(second.ID))
+		})
+
+		It("should unpin tweet", func() {
+			tweet := createTweet(router, "pinned", alaToken)
+			pinTweet(router, tweet.ID, alaToken)
+
+			Expect(unpinTweet(router, tweet.ID, alaToken).PinnedTweet).To(BeNil())
+			Expect(retrieveUser(router, ala.ID, alaToken).PinnedTweet).To(BeNil())
+		})
+
+		It("should clear pin when pinned tweet is deleted", func() {
+			tweet := createTweet(router, "pinned", alaToken)
+			pinTweet(router, tweet.ID, alaToken)
+
+			deleteTweet(router, tweet.ID, alaToken)
+
+			Expect(retrieveUser(router, ala.ID, alaToken).PinnedTweet).To(BeNil())
+			Expect(retrieveUserTweets(router, alaToken, ala.ID)).To(BeEmpty())
+		})
+
+		It("should return forbidden code when pinning tweet of other user", func() {
+			tweet := createTweet(router, "tweet", bobToken)
+
+			req := request("POST", fmt.Sprintf("/tweets/%v/pin", tweet.ID), nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(retrieveUser(router, bob.ID, bobToken).PinnedTweet).To(BeNil())
+		})
+	})
+
+	Describe("Get user mentions", func() {
+		It("should resolve mentions of existing users", func() {
+			content := fmt.Sprintf("hello @%v and @%v and @nobody", ala.Username, bob.Username)
+			tweet := createTweet(router, content, alaToken)
+
+			expectedMentions := []*model.Mention{
+				{UserID: ala.ID, Username: ala.Username},
+				{UserID: bob.ID, Username: bob.Username},
+			}
+			Expect(tweet.Mentions).To(ConsistOf(expectedMentions))
+			Expect(tweet.Content).To(Equal(content))
+			Expect(retrieveTweet(router, tweet.ID, bobToken).Mentions).To(ConsistOf(expectedMentions))
+		})
+
+		It("should get newest tweets mentioning user", func() {
+			firstTweet := createTweet(router, "hi @"+bob.Username, alaToken)
+			createTweet(router, "hi @"+toor.Username, alaToken)
+			secondTweet := createTweet(router, "hello @"+bob.Username, alaToken)
+
+			expectedTweets := []*model.Tweet{
+				retrieveTweet(router, secondTweet.ID, bobToken),
+				retrieveTweet(router, firstTweet.ID, bobToken),
+			}
+
+			actualTweets := retrieveUserMentions(router, bobToken, bob.ID)
+
+			Expect(actualTweets).To(Equal(expectedTweets))
+		})
+
+		It("should not return deleted tweets", func() {
+			tweet := createTweet(router, "hi @"+bob.Username, alaToken)
+			Expect(retrieveUserMentions(router, bobToken, bob.ID)).To(HaveLen(1))
+
+			deleteTweet(router, tweet.ID, alaToken)
+
+			Expect(retrieveUserMentions(router, bobToken, bob.ID)).To(BeEmpty())
+		})
+
+		It("should return not found code for not existing user", func() {
+			req := request("GET", "/users/123456/mentions", nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
 	})
 
 	Describe("Create and get tweet", func() {
@@ -474,175 +752,1645 @@ var _ = Describe("ServerTest", func() {
 
 			Expect(actualTweet).To(Equal(expectedTweet))
 		})
+		It("should return entities with code point positions", func() {
+			content := fmt.Sprintf("🎉 #chirp @%v http://chirp.com", bob.Username)
+			createdTweet := createTweet(router, content, alaToken)
+
+			mentionEnd := 10 + len(bob.Username)
+			expectedEntities := &entities.Entities{
+				Hashtags: []*entities.Hashtag{{"chirp", 2, 8}},
+				Mentions: []*entities.Mention{{bob.Username, 9, mentionEnd}},
+				URLs:     []*entities.URL{{"http://chirp.com", mentionEnd + 1, mentionEnd + 17}},
+			}
+			Expect(createdTweet.Entities).To(Equal(expectedEntities))
+			Expect(retrieveTweet(router, createdTweet.ID, alaToken).Entities).To(Equal(expectedEntities))
+		})
 	})
 
-	Describe("Delete tweet", func() {
-		BeforeEach(func() {})
-
-		It("should delete existing tweet", func() {
-			createdTweet := createTweet(router, "new tweet", alaToken)
-			deleteTweet(router, createdTweet.ID, alaToken)
-
-			path := fmt.Sprintf("/tweets/%v", createdTweet.ID)
-			req := request("GET", path, nil).authorize(alaToken).build()
+	Describe("Content policy", func() {
+		postTweet := func(content string, authToken string) int {
+			newTweet := &model.NewTweet{Content: content}
+			req := request("POST", "/tweets", body(newTweet)).json().authorize(authToken).build()
 			w := httptest.NewRecorder()
 
 			router.ServeHTTP(w, req)
 
-			Expect(w.Code).To(Equal(http.StatusNotFound))
+			return w.Code
+		}
+
+		It("should store trimmed content", func() {
+			tweet := createTweet(router, "  \n tweet \t", alaToken)
+			Expect(tweet.Content).To(Equal("tweet"))
 		})
 
-		It("should return not found code when trying to delete not existing tweet", func() {
-			req := request("DELETE", "/tweets/123", nil).authorize(alaToken).build()
-			w := httptest.NewRecorder()
+		It("should reject blank content", func() {
+			Expect(postTweet(" \n\t ", alaToken)).To(Equal(http.StatusBadRequest))
+		})
 
-			router.ServeHTTP(w, req)
+		It("should reject too long content", func() {
+			Expect(postTweet(strings.Repeat("a", 151), alaToken)).To(Equal(http.StatusUnprocessableEntity))
+		})
 
-			Expect(w.Code).To(Equal(http.StatusNotFound))
+		It("should count emoji as single characters", func() {
+			family := "\U0001F469\u200D\U0001F469\u200D\U0001F467"
+			Expect(postTweet(strings.Repeat(family, 150), alaToken)).To(Equal(http.StatusCreated))
 		})
 
-		It("should not allow to delete tweet created by someone else", func() {
-			createdTweet := createTweet(router, "new tweet", bobToken)
+		It("should reject the same tweet posted again by the same user", func() {
+			createTweet(router, "hello world", alaToken)
 
-			path := fmt.Sprintf("/tweets/%v", createdTweet.ID)
-			req := request("DELETE", path, nil).authorize(alaToken).build()
+			Expect(postTweet("hello world", alaToken)).To(Equal(http.StatusUnprocessableEntity))
+			Expect(postTweet(" hello world ", alaToken)).To(Equal(http.StatusUnprocessableEntity))
+			Expect(postTweet("hello world", bobToken)).To(Equal(http.StatusCreated))
+		})
+
+		It("should reject too long content of edited tweet", func() {
+			tweet := createTweet(router, "tweet", alaToken)
+
+			req := request("PATCH", fmt.Sprintf("/tweets/%v", tweet.ID), body(&model.NewTweetContent{Content: strings.Repeat("a", 151)})).json().authorize(alaToken).build()
 			w := httptest.NewRecorder()
 
 			router.ServeHTTP(w, req)
 
-			Expect(w.Code).To(Equal(http.StatusForbidden))
+			Expect(w.Code).To(Equal(http.StatusUnprocessableEntity))
 		})
 	})
 
-	Describe("Get home feed", func() {
+	Describe("Reply to tweet", func() {
 		var (
-			alaTweet  *model.Tweet
-			bobTweet  *model.Tweet
-			toorToken string
+			alaTweet *model.Tweet
 		)
 
 		BeforeEach(func() {
 			alaTweet = createTweet(router, "new ala tweet", alaToken)
-			bobTweet = createTweet(router, "new bob tweet", bobToken)
-			toorToken, _ = loginUser(router, toor)
-			followUser(router, ala.ID, toorToken)
-			followUser(router, bob.ID, toorToken)
 		})
 
-		It("should get users feed", func() {
-			expectedFeed := []*model.Tweet{
-				retrieveTweet(router, alaTweet.ID, toorToken),
-				retrieveTweet(router, bobTweet.ID, toorToken),
-			}
-			sort.Sort(utils.TweetsByCreationDateDesc(expectedFeed))
+		It("should create reply and populate thread fields", func() {
+			reply := createReply(router, "bob reply", alaTweet.ID, bobToken)
 
-			actualFeed := retrieveFeed(router, toorToken)
+			Expect(reply.InReplyToID).To(Equal(alaTweet.ID))
+			Expect(reply.RootID).To(Equal(alaTweet.ID))
+		})
 
-			Expect(actualFeed).To(Equal(expectedFeed))
-			Expect(len(actualFeed)).To(Equal(len(expectedFeed)))
+		It("should inherit root of the thread from parent", func() {
+			reply := createReply(router, "bob reply", alaTweet.ID, bobToken)
+			nestedReply := createReply(router, "ala reply", reply.ID, alaToken)
+
+			Expect(nestedReply.InReplyToID).To(Equal(reply.ID))
+			Expect(nestedReply.RootID).To(Equal(alaTweet.ID))
+		})
+
+		It("should update reply count of the parent", func() {
+			createReply(router, "bob reply", alaTweet.ID, bobToken)
+			createReply(router, "ala reply", alaTweet.ID, alaToken)
+
+			actualTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+
+			Expect(actualTweet.ReplyCount).To(BeEquivalentTo(2))
+		})
+
+		It("should return not found code when replying to not existing tweet", func() {
+			newTweet := &model.NewTweet{Content: "reply", InReplyToID: alaTweet.ID + 1000}
+			req := request("POST", "/tweets", body(newTweet)).json().authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
 		})
 	})
 
-	Describe("Like tweet", func() {
+	Describe("Quote tweet", func() {
 		var (
 			alaTweet *model.Tweet
-			bobTweet *model.Tweet
 		)
 
 		BeforeEach(func() {
 			alaTweet = createTweet(router, "new ala tweet", alaToken)
-			bobTweet = createTweet(router, "new bob tweet", bobToken)
-		})
-
-		It("should like tweet and return new liked tweet with populated data", func() {
-			actualTweet := likeTweet(router, alaTweet.ID, alaToken)
-
-			Expect(actualTweet.LikeCount).To(BeEquivalentTo(1))
-			Expect(actualTweet.Liked).To(Equal(true))
 		})
 
-		It("tweet returned by like should match real tweet", func() {
-			actualTweet := likeTweet(router, alaTweet.ID, alaToken)
+		It("should create quote with embedded quoted tweet", func() {
+			quote := createQuote(router, "bob quote", alaTweet.ID, bobToken)
 
-			expectedTweet := retrieveTweet(router, alaTweet.ID, alaToken)
-			Expect(actualTweet).To(Equal(expectedTweet))
+			Expect(quote.QuotedTweetID).To(Equal(alaTweet.ID))
+			Expect(quote.QuotedTweet.ID).To(Equal(alaTweet.ID))
+			Expect(quote.QuotedTweet.Content).To(Equal(alaTweet.Content))
+			Expect(quote.QuotedTweet.Author.ID).To(Equal(ala.ID))
+			Expect(quote.QuotedTweet.Unavailable).To(BeFalse())
 		})
 
-		It("should tweet be liked only once after consecutive likes", func() {
-			likeTweet(router, alaTweet.ID, alaToken)
-			likeTweet(router, alaTweet.ID, alaToken)
+		It("should get quote after creating", func() {
+			expectedTweet := createQuote(router, "bob quote", alaTweet.ID, bobToken)
 
-			actualTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+			actualTweet := retrieveTweet(router, expectedTweet.ID, bobToken)
 
-			Expect(actualTweet.LikeCount).To(BeEquivalentTo(1))
-			Expect(actualTweet.Liked).To(Equal(true))
+			Expect(actualTweet).To(Equal(expectedTweet))
 		})
 
-		It("should return tweet liked twice after multiple (two) likes", func() {
-			likeTweet(router, alaTweet.ID, alaToken)
-			likeTweet(router, alaTweet.ID, bobToken)
+		It("should return placeholder when quoted tweet was deleted", func() {
+			quote := createQuote(router, "bob quote", alaTweet.ID, bobToken)
+			deleteTweet(router, alaTweet.ID, alaToken)
 
-			actualTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+			actualTweet := retrieveTweet(router, quote.ID, bobToken)
 
-			Expect(actualTweet.LikeCount).To(BeEquivalentTo(2))
+			Expect(actualTweet.QuotedTweet.ID).To(Equal(alaTweet.ID))
+			Expect(actualTweet.QuotedTweet.Unavailable).To(BeTrue())
+			Expect(actualTweet.QuotedTweet.Author).To(BeNil())
 		})
 
-		It("should return tweet with false `liked` field when tweet was not liked by user", func() {
-			likeTweet(router, bobTweet.ID, bobToken)
+		It("should return not found code when quoting not existing tweet", func() {
+			newTweet := &model.NewTweet{Content: "quote", QuotedTweetID: alaTweet.ID + 1000}
+			req := request("POST", "/tweets", body(newTweet)).json().authorize(alaToken).build()
+			w := httptest.NewRecorder()
 
-			actualTweet := retrieveTweet(router, bobTweet.ID, alaToken)
+			router.ServeHTTP(w, req)
 
-			Expect(actualTweet.Liked).To(Equal(false))
+			Expect(w.Code).To(Equal(http.StatusNotFound))
 		})
 	})
 
-	Describe("Unlike tweet", func() {
+	Describe("Get conversation", func() {
 		var (
-			alaTweet *model.Tweet
+			alaTweet    *model.Tweet
+			bobReply    *model.Tweet
+			alaReply    *model.Tweet
+			toorReply   *model.Tweet
+			ernestReply *model.Tweet
 		)
 
 		BeforeEach(func() {
+			toorToken, _ := loginUser(router, toor)
+			ernestToken, _ := loginUser(router, ernest)
+
 			alaTweet = createTweet(router, "new ala tweet", alaToken)
+			bobReply = createReply(router, "bob reply", alaTweet.ID, bobToken)
+			alaReply = createReply(router, "ala reply", bobReply.ID, alaToken)
+			toorReply = createReply(router, "toor reply", alaTweet.ID, toorToken)
+			ernestReply = createReply(router, "ernest reply", alaReply.ID, ernestToken)
 		})
 
-		It("should unlike tweet and return unliked tweet with fresh data", func() {
-			likeTweet(router, alaTweet.ID, alaToken)
+		It("should return replies tree of the root tweet", func() {
+			conversation := retrieveConversation(router, alaTweet.ID, alaToken)
+
+			Expect(conversation.Ancestors).To(BeEmpty())
+			Expect(conversation.Tweet.ID).To(Equal(alaTweet.ID))
+			Expect(conversation.Replies).To(HaveLen(2))
+			Expect(conversation.Replies[0].ID).To(Equal(bobReply.ID))
+			Expect(conversation.Replies[0].Replies).To(HaveLen(1))
+			Expect(conversation.Replies[0].Replies[0].ID).To(Equal(alaReply.ID))
+			Expect(conversation.Replies[0].Replies[0].Replies[0].ID).To(Equal(ernestReply.ID))
+			Expect(conversation.Replies[1].ID).To(Equal(toorReply.ID))
+			Expect(conversation.Replies[1].Replies).To(BeEmpty())
+		})
 
-			path := fmt.Sprintf("/tweets/%v/unlike", alaTweet.ID)
-			req := request("POST", path, nil).authorize(alaToken).build()
+		It("should return ancestors from the root of the thread", func() {
+			conversation := retrieveConversation(router, ernestReply.ID, alaToken)
+
+			Expect(conversation.Ancestors).To(HaveLen(3))
+			Expect(conversation.Ancestors[0].ID).To(Equal(alaTweet.ID))
+			Expect(conversation.Ancestors[1].ID).To(Equal(bobReply.ID))
+			Expect(conversation.Ancestors[2].ID).To(Equal(alaReply.ID))
+			Expect(conversation.Replies).To(BeEmpty())
+		})
+
+		It("should paginate direct replies", func() {
+			conversation := retrieveConversationPage(router, alaTweet.ID, "", 1, alaToken)
+			Expect(conversation.Replies).To(HaveLen(1))
+			Expect(conversation.Replies[0].ID).To(Equal(bobReply.ID))
+			Expect(conversation.NextCursor).NotTo(BeEmpty())
+
+			conversation = retrieveConversationPage(router, alaTweet.ID, conversation.NextCursor, 1, alaToken)
+			Expect(conversation.Replies).To(HaveLen(1))
+			Expect(conversation.Replies[0].ID).To(Equal(toorReply.ID))
+			Expect(conversation.NextCursor).To(BeEmpty())
+		})
+
+		It("should skip deleted ancestors", func() {
+			deleteTweet(router, bobReply.ID, bobToken)
+
+			conversation := retrieveConversation(router, alaReply.ID, alaToken)
+
+			Expect(conversation.Ancestors).To(BeEmpty())
+		})
+	})
+
+	Describe("Edit tweet", func() {
+		It("should edit tweet and keep previous versions", func() {
+			tweet := createTweet(router, "first #old", alaToken)
+			editTweet(router, tweet.ID, "second", alaToken)
+			editedTweet := editTweet(router, tweet.ID, "third #new", alaToken)
+
+			Expect(editedTweet.Content).To(Equal("third #new"))
+			Expect(editedTweet.EditedAt).NotTo(BeNil())
+			Expect(editedTweet.CreatedAt).To(Equal(tweet.CreatedAt))
+			Expect(retrieveTweet(router, tweet.ID, bobToken).Content).To(Equal("third #new"))
+
+			revisions := retrieveTweetRevisions(router, tweet.ID, bobToken)
+			Expect(revisions).To(HaveLen(2))
+			Expect(revisions[0].Content).To(Equal("second"))
+			Expect(revisions[1].Content).To(Equal("first #old"))
+			Expect(revisions[1].CreatedAt).To(Equal(tweet.CreatedAt))
+
+			Expect(retrieveHashtagTweets(router, "old", alaToken)).To(BeEmpty())
+			Expect(retrieveHashtagTweets(router, "new", alaToken)).To(HaveLen(1))
+		})
+
+		It("should return empty revisions for not edited tweet", func() {
+			tweet := createTweet(router, "tweet", alaToken)
+
+			Expect(tweet.EditedAt).To(BeNil())
+			Expect(retrieveTweetRevisions(router, tweet.ID, alaToken)).To(BeEmpty())
+		})
+
+		It("should not allow to edit tweet created by someone else", func() {
+			tweet := createTweet(router, "tweet", bobToken)
+
+			path := fmt.Sprintf("/tweets/%v", tweet.ID)
+			req := request("PATCH", path, body(&model.NewTweetContent{Content: "edit"})).json().authorize(alaToken).build()
 			w := httptest.NewRecorder()
 
 			router.ServeHTTP(w, req)
 
-			var actualTweet model.Tweet
-			err := json.Unmarshal(w.Body.Bytes(), &actualTweet)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
 
+		It("should not allow to edit tweet after edit window", func() {
+			tweet := createTweet(router, "tweet", alaToken)
+			_, err := db.Exec(`UPDATE tweets SET created_at = created_at - interval '1 day' WHERE id = $1`, tweet.ID)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(w.Code).To(Equal(http.StatusOK))
-			Expect(actualTweet.LikeCount).To(BeEquivalentTo(0))
-			Expect(actualTweet.Liked).To(Equal(false))
-		})
 
-		It("tweet returned by unlike should match real tweet", func() {
-			likeTweet(router, alaTweet.ID, alaToken)
+			path := fmt.Sprintf("/tweets/%v", tweet.ID)
+			req := request("PATCH", path, body(&model.NewTweetContent{Content: "edit"})).json().authorize(alaToken).build()
+			w := httptest.NewRecorder()
 
-			actualTweet := unlikeTweet(router, alaTweet.ID, alaToken)
+			router.ServeHTTP(w, req)
 
-			expectedTweet := retrieveTweet(router, alaTweet.ID, alaToken)
-			Expect(actualTweet).To(Equal(expectedTweet))
+			Expect(w.Code).To(Equal(http.StatusForbidden))
 		})
 
-		It("should not perform any unexpected actions when trying to unlike not liked tweet", func() {
-			expectedTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+		It("should return not found code when trying to edit not existing tweet", func() {
+			req := request("PATCH", "/tweets/123", body(&model.NewTweetContent{Content: "edit"})).json().authorize(alaToken).build()
+			w := httptest.NewRecorder()
 
-			actualTweet := unlikeTweet(router, alaTweet.ID, alaToken)
+			router.ServeHTTP(w, req)
 
-			Expect(actualTweet).To(Equal(expectedTweet))
+			Expect(w.Code).To(Equal(http.StatusNotFound))
 		})
+	})
 
-		It("should not unlike tweet which is liked by someone else", func() {
-			likeTweet(router, alaTweet.ID, alaToken)
-			unlikeTweet(router, alaTweet.ID, bobToken)
+	Describe("Bookmarks", func() {
+		It("should bookmark and unbookmark tweet", func() {
+			tweet := createTweet(router, "tweet", bobToken)
+
+			Expect(tweet.Bookmarked).To(BeFalse())
+			Expect(bookmarkTweet(router, tweet.ID, alaToken).Bookmarked).To(BeTrue())
+			Expect(retrieveTweet(router, tweet.ID, alaToken).Bookmarked).To(BeTrue())
+			Expect(unbookmarkTweet(router, tweet.ID, alaToken).Bookmarked).To(BeFalse())
+			Expect(retrieveBookmarks(router, "", 20, alaToken).Tweets).To(BeEmpty())
+		})
+
+		It("should keep bookmarks private", func() {
+			tweet := createTweet(router, "tweet", bobToken)
+			bookmarkTweet(router, tweet.ID, alaToken)
+
+			Expect(retrieveTweet(router, tweet.ID, bobToken).Bookmarked).To(BeFalse())
+			Expect(retrieveBookmarks(router, "", 20, bobToken).Tweets).To(BeEmpty())
+		})
+
+		It("should return bookmarks from the most recently bookmarked", func() {
+			first := createTweet(router, "first", bobToken)
+			second := createTweet(router, "second", bobToken)
+			third := createTweet(router, "third", bobToken)
+
+			bookmarkTweet(router, second.ID, alaToken)
+			bookmarkTweet(router, first.ID, alaToken)
+			bookmarkTweet(router, third.ID, alaToken)
+
+			page := retrieveBookmarks(router, "", 2, alaToken)
+			Expect(page.Tweets).To(HaveLen(2))
+			Expect(page.Tweets[0].ID).To(Equal(third.ID))
+			Expect(page.Tweets[1].ID).To(Equal(first.ID))
+			Expect(page.NextCursor).NotTo(BeEmpty())
+
+			page = retrieveBookmarks(router, page.NextCursor, 2, alaToken)
+			Expect(page.Tweets).To(HaveLen(1))
+			Expect(page.Tweets[0].ID).To(Equal(second.ID))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("should return bad request code when cursor is invalid", func() {
+			req := request("GET", "/bookmarks", nil).authorize(alaToken).urlQuery("cursor", "invalid").build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return not found code when trying to bookmark not existing tweet", func() {
+			req := request("POST", "/tweets/123456/bookmark", nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Polls", func() {
+		vote := func(tweetID int64, option int, authToken string) int {
+			path := fmt.Sprintf("/tweets/%v/poll/vote", tweetID)
+			req := request("POST", path, body(&model.PollVote{Option: &option})).json().authorize(authToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			return w.Code
+		}
+
+		It("should create tweet with poll", func() {
+			tweet := createTweetWithPoll(router, "poll", []string{"yes", "no"}, alaToken)
+
+			Expect(tweet.Poll).NotTo(BeNil())
+			Expect(tweet.Poll.Options).To(HaveLen(2))
+			Expect(tweet.Poll.Options[0].Text).To(Equal("yes"))
+			Expect(tweet.Poll.Options[1].Text).To(Equal("no"))
+			Expect(tweet.Poll.Closed).To(BeFalse())
+			Expect(tweet.Poll.Vote).To(BeNil())
+			Expect(createTweet(router, "no poll", alaToken).Poll).To(BeNil())
+		})
+
+		It("should count votes and return vote of requesting user", func() {
+			tweet := createTweetWithPoll(router, "poll", []string{"a", "b", "c"}, alaToken)
+
+			votePoll(router, tweet.ID, 1, alaToken)
+			votedTweet := votePoll(router, tweet.ID, 1, bobToken)
+
+			Expect(votedTweet.Poll.Options[0].VoteCount).To(BeZero())
+			Expect(votedTweet.Poll.Options[1].VoteCount).To(Equal(int64(2)))
+			Expect(votedTweet.Poll.Options[2].VoteCount).To(BeZero())
+			Expect(*votedTweet.Poll.Vote).To(Equal(1))
+
+			_, toorToken := loginUser(router, toor)
+			Expect(retrieveTweet(router, tweet.ID, toorToken).Poll.Vote).To(BeNil())
+		})
+
+		It("should not allow to vote twice", func() {
+			tweet := createTweetWithPoll(router, "poll", []string{"yes", "no"}, alaToken)
+			votePoll(router, tweet.ID, 0, bobToken)
+
+			Expect(vote(tweet.ID, 1, bobToken)).To(Equal(http.StatusConflict))
+			Expect(retrieveTweet(router, tweet.ID, bobToken).Poll.Options[1].VoteCount).To(BeZero())
+		})
+
+		It("should not allow to vote for not existing option", func() {
+			tweet := createTweetWithPoll(router, "poll", []string{"yes", "no"}, alaToken)
+
+			Expect(vote(tweet.ID, 2, bobToken)).To(Equal(http.StatusBadRequest))
+			Expect(vote(tweet.ID, -1, bobToken)).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should not allow to vote in closed poll", func() {
+			tweet := createTweetWithPoll(router, "poll", []string{"yes", "no"}, alaToken)
+			_, err := db.Exec(`UPDATE polls SET closes_at = now() - interval '1 day' WHERE tweet_id = $1`, tweet.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(retrieveTweet(router, tweet.ID, bobToken).Poll.Closed).To(BeTrue())
+			Expect(vote(tweet.ID, 0, bobToken)).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return not found code when tweet does not have poll", func() {
+			tweet := createTweet(router, "no poll", alaToken)
+
+			Expect(vote(tweet.ID, 0, bobToken)).To(Equal(http.StatusNotFound))
+		})
+
+		It("should not create tweet with invalid poll", func() {
+			polls := []*model.NewPoll{
+				{Options: []string{"only one"}, ClosesAt: time.Now().Add(time.Hour)},
+				{Options: []string{"a", "b", "c", "d", "e"}, ClosesAt: time.Now().Add(time.Hour)},
+				{Options: []string{"a", " "}, ClosesAt: time.Now().Add(time.Hour)},
+				{Options: []string{"a", "option which is definitely too long"}, ClosesAt: time.Now().Add(time.Hour)},
+				{Options: []string{"a", "b"}, ClosesAt: time.Now().Add(-time.Hour)},
+			}
+
+			for _, poll := range polls {
+				newTweet := &model.NewTweet{Content: "poll", Poll: poll}
+				req := request("POST", "/tweets", body(newTweet)).json().authorize(alaToken).build()
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			}
+		})
+	})
+
+	Describe("Scheduled tweets", func() {
+		It("should publish scheduled tweet only once", func() {
+			publishAt := time.Now().Add(time.Hour)
+			scheduledTweet := scheduleTweet(router, "scheduled #tweet", publishAt, alaToken)
+
+			Expect(scheduledTweet.Content).To(Equal("scheduled #tweet"))
+			Expect(scheduledTweet.PublishAt.Unix()).To(Equal(publishAt.Unix()))
+			Expect(retrieveScheduledTweets(router, alaToken)).To(HaveLen(1))
+			Expect(retrieveScheduledTweets(router, bobToken)).To(BeEmpty())
+			Expect(retrieveUserTweets(router, alaToken, ala.ID)).To(BeEmpty())
+
+			// nothing is due yet
+			published, err := accessor.PublishScheduledTweets(time.Now(), 10, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(published).To(BeEmpty())
+
+			published, err = accessor.PublishScheduledTweets(publishAt.Add(time.Minute), 10, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(published).To(HaveLen(1))
+			Expect(published[0].Author.ID).To(Equal(ala.ID))
+
+			published, err = accessor.PublishScheduledTweets(publishAt.Add(time.Minute), 10, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(published).To(BeEmpty())
+
+			Expect(retrieveScheduledTweets(router, alaToken)).To(BeEmpty())
+			tweets := retrieveUserTweets(router, alaToken, ala.ID)
+			Expect(tweets).To(HaveLen(1))
+			Expect(tweets[0].Content).To(Equal("scheduled #tweet"))
+			Expect(retrieveHashtagTweets(router, "tweet", alaToken)).To(HaveLen(1))
+		})
+
+		It("should cancel scheduled tweet", func() {
+			scheduledTweet := scheduleTweet(router, "scheduled", time.Now().Add(time.Hour), alaToken)
+
+			path := fmt.Sprintf("/scheduled_tweets/%v", scheduledTweet.ID)
+			req := request("DELETE", path, nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+			Expect(retrieveScheduledTweets(router, alaToken)).To(BeEmpty())
+
+			published, err := accessor.PublishScheduledTweets(time.Now().Add(2*time.Hour), 10, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(published).To(BeEmpty())
+		})
+
+		It("should not allow to cancel scheduled tweet of someone else", func() {
+			scheduledTweet := scheduleTweet(router, "scheduled", time.Now().Add(time.Hour), bobToken)
+
+			path := fmt.Sprintf("/scheduled_tweets/%v", scheduledTweet.ID)
+			req := request("DELETE", path, nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+			Expect(retrieveScheduledTweets(router, bobToken)).To(HaveLen(1))
+		})
+
+		It("should not allow to schedule tweet in the past", func() {
+			newTweet := &model.NewScheduledTweet{
+				NewTweet:  model.NewTweet{Content: "scheduled"},
+				PublishAt: time.Now().Add(-time.Minute),
+			}
+			req := request("POST", "/scheduled_tweets", body(newTweet)).json().authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Delete tweet", func() {
+		BeforeEach(func() {})
+
+		It("should delete existing tweet", func() {
+			createdTweet := createTweet(router, "new tweet", alaToken)
+			deleteTweet(router, createdTweet.ID, alaToken)
+
+			path := fmt.Sprintf("/tweets/%v", createdTweet.ID)
+			req := request("GET", path, nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should return not found code when trying to delete not existing tweet", func() {
+			req := request("DELETE", "/tweets/123", nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should not allow to delete tweet created by someone else", func() {
+			createdTweet := createTweet(router, "new tweet", bobToken)
+
+			path := fmt.Sprintf("/tweets/%v", createdTweet.ID)
+			req := request("DELETE", path, nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should hide deleted tweet from lists", func() {
+			createdTweet := createTweet(router, "new #tweet", alaToken)
+			reply := createReply(router, "reply", createdTweet.ID, bobToken)
+			deleteTweet(router, reply.ID, bobToken)
+			deleteTweet(router, createdTweet.ID, alaToken)
+
+			Expect(retrieveUserTweets(router, alaToken, ala.ID)).To(BeEmpty())
+			Expect(retrieveHashtagTweets(router, "tweet", alaToken)).To(BeEmpty())
+		})
+
+		It("should restore deleted tweet", func() {
+			createdTweet := createTweet(router, "new tweet", alaToken)
+			likeTweet(router, createdTweet.ID, bobToken)
+			deleteTweet(router, createdTweet.ID, alaToken)
+
+			restoredTweet := restoreTweet(router, createdTweet.ID, alaToken)
+			Expect(restoredTweet.ID).To(Equal(createdTweet.ID))
+			Expect(restoredTweet.Content).To(Equal("new tweet"))
+			Expect(restoredTweet.LikeCount).To(BeEquivalentTo(1))
+
+			Expect(retrieveTweet(router, createdTweet.ID, bobToken).ID).To(Equal(createdTweet.ID))
+			Expect(retrieveUserTweets(router, alaToken, ala.ID)).To(HaveLen(1))
+		})
+
+		It("should not allow to restore tweet which was not deleted or created by someone else", func() {
+			createdTweet := createTweet(router, "new tweet", alaToken)
+
+			path := fmt.Sprintf("/tweets/%v/restore", createdTweet.ID)
+			req := request("POST", path, nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+
+			deleteTweet(router, createdTweet.ID, alaToken)
+
+			req = request("POST", path, nil).authorize(bobToken).build()
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should not allow to restore tweet after restore window", func() {
+			createdTweet := createTweet(router, "new tweet", alaToken)
+			deleteTweet(router, createdTweet.ID, alaToken)
+
+			_, err := db.Exec(
+				`UPDATE tweets SET deleted_at = now() - interval '1 year' WHERE id = $1`,
+				createdTweet.ID,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			path := fmt.Sprintf("/tweets/%v/restore", createdTweet.ID)
+			req := request("POST", path, nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should purge tweets deleted before restore window together with likes", func() {
+			oldTweet := createTweet(router, "old tweet", alaToken)
+			newTweet := createTweet(router, "new tweet", alaToken)
+			likeTweet(router, oldTweet.ID, bobToken)
+			deleteTweet(router, oldTweet.ID, alaToken)
+			deleteTweet(router, newTweet.ID, alaToken)
+
+			_, err := db.Exec(
+				`UPDATE tweets SET deleted_at = now() - interval '1 year' WHERE id = $1`,
+				oldTweet.ID,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			purged, err := accessor.PurgeDeletedTweets(24*time.Hour, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(purged).To(Equal(1))
+
+			var count int
+			err = db.QueryRow(`SELECT COUNT(*) FROM likes WHERE tweet_id = $1`, oldTweet.ID).Scan(&count)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(BeZero())
+
+			restoreTweet(router, newTweet.ID, alaToken)
+		})
+	})
+
+	Describe("Media", func() {
+		It("should upload image", func() {
+			media := uploadMedia(router, pngImage(30, 20), alaToken)
+
+			Expect(media.ID).NotTo(BeZero())
+			Expect(media.URL).NotTo(BeEmpty())
+			Expect(media.MimeType).To(Equal("image/png"))
+			Expect(media.Width).To(Equal(30))
+			Expect(media.Height).To(Equal(20))
+		})
+
+		It("should reject unsupported and too large files", func() {
+			largeImage := append(pngImage(1, 1), make([]byte, 5*1024*1024)...)
+			hugeImage := append(pngImage(1, 1), make([]byte, 6*1024*1024)...)
+			testCases := []struct {
+				data         []byte
+				expectedCode int
+			}{
+				{[]byte("just a text"), http.StatusUnsupportedMediaType},
+				{pngImage(1, 1)[:20], http.StatusUnsupportedMediaType},
+				{largeImage, http.StatusRequestEntityTooLarge},
+				{hugeImage, http.StatusRequestEntityTooLarge},
+			}
+
+			for _, testCase := range testCases {
+				mediaBody, contentType := multipartBody("media", testCase.data)
+				req := request("POST", "/media", mediaBody).contentType(contentType).authorize(alaToken).build()
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(testCase.expectedCode))
+			}
+		})
+
+		It("should return bad request when file is missing", func() {
+			req := request("POST", "/media", nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should attach media to tweet in given order", func() {
+			first := uploadMedia(router, pngImage(1, 2), alaToken)
+			second := uploadMedia(router, pngImage(3, 4), alaToken)
+
+			tweet := createTweetWithMedia(router, "tweet", []int64{second.ID, first.ID}, alaToken)
+
+			Expect(tweet.Media).To(Equal([]*model.Media{second, first}))
+			Expect(retrieveTweet(router, tweet.ID, bobToken).Media).To(Equal([]*model.Media{second, first}))
+		})
+
+		It("should serve media only to users who can see it", func() {
+			media := uploadMedia(router, pngImage(1, 2), alaToken)
+			Expect(retrieveMediaStatus(router, media, alaToken)).To(Equal(http.StatusOK))
+			Expect(retrieveMediaStatus(router, media, bobToken)).To(Equal(http.StatusNotFound))
+
+			newTweet := &model.NewTweet{
+				Content:    "tweet",
+				Visibility: model.VisibilityFollowers,
+				MediaIDs:   []int64{media.ID},
+			}
+			req := request("POST", "/tweets", body(newTweet)).json().authorize(alaToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusCreated))
+
+			Expect(retrieveMediaStatus(router, media, bobToken)).To(Equal(http.StatusNotFound))
+
+			followUser(router, ala.ID, bobToken)
+			Expect(retrieveMediaStatus(router, media, bobToken)).To(Equal(http.StatusOK))
+
+			req = request("GET", media.URL, nil).build()
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("should return empty media for tweet without attachments", func() {
+			tweet := createTweet(router, "tweet", alaToken)
+			Expect(tweet.Media).NotTo(BeNil())
+			Expect(tweet.Media).To(BeEmpty())
+		})
+
+		It("should not allow to attach invalid media", func() {
+			alaMedia := uploadMedia(router, pngImage(1, 1), alaToken)
+			bobMedia := uploadMedia(router, pngImage(1, 1), bobToken)
+			createTweetWithMedia(router, "tweet", []int64{alaMedia.ID}, alaToken)
+
+			manyMedia := make([]int64, 0)
+			for i := 0; i < 5; i++ {
+				manyMedia = append(manyMedia, uploadMedia(router, pngImage(1, 1), alaToken).ID)
+			}
+
+			testCases := [][]int64{
+				{alaMedia.ID},                // already attached
+				{bobMedia.ID},                // someone else's
+				{123456},                     // not existing
+				{manyMedia[0], manyMedia[0]}, // duplicated
+				manyMedia,                    // too many
+			}
+
+			for _, mediaIDs := range testCases {
+				newTweet := &model.NewTweet{Content: "tweet", MediaIDs: mediaIDs}
+				req := request("POST", "/tweets", body(newTweet)).json().authorize(alaToken).build()
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			}
+		})
+	})
+
+	Describe("Get hashtag tweets", func() {
+		It("should get newest tweets with given hashtag", func() {
+			firstTweet := createTweet(router, "first #golang tweet", alaToken)
+			createTweet(router, "tweet about #rust", alaToken)
+			secondTweet := createTweet(router, "second #GoLang tweet", bobToken)
+
+			expectedTweets := []*model.Tweet{
+				retrieveTweet(router, secondTweet.ID, alaToken),
+				retrieveTweet(router, firstTweet.ID, alaToken),
+			}
+
+			actualTweets := retrieveHashtagTweets(router, "golang", alaToken)
+
+			Expect(actualTweets).To(Equal(expectedTweets))
+		})
+
+		It("should ignore case and leading '#' of the hashtag", func() {
+			tweet := createTweet(router, "#Chirp is great", alaToken)
+
+			Expect(retrieveHashtagTweets(router, "CHIRP", alaToken)).To(HaveLen(1))
+			Expect(retrieveHashtagTweets(router, "%23chirp", alaToken)[0].ID).To(Equal(tweet.ID))
+		})
+
+		It("should not return deleted tweets", func() {
+			tweet := createTweet(router, "#chirp", alaToken)
+			Expect(retrieveHashtagTweets(router, "chirp", alaToken)).To(HaveLen(1))
+
+			deleteTweet(router, tweet.ID, alaToken)
+
+			Expect(retrieveHashtagTweets(router, "chirp", alaToken)).To(BeEmpty())
+		})
+
+		It("should paginate tweets", func() {
+			createTweet(router, "#chirp 1", alaToken)
+			createTweet(router, "#chirp 2", alaToken)
+			createTweet(router, "#chirp 3", alaToken)
+
+			page := retrieveHashtagTweetsPage(router, "chirp", "", 2, alaToken)
+			Expect(page.Tweets).To(HaveLen(2))
+			Expect(page.Tweets[0].Content).To(Equal("#chirp 3"))
+			Expect(page.Tweets[1].Content).To(Equal("#chirp 2"))
+			Expect(page.NextCursor).NotTo(BeEmpty())
+
+			page = retrieveHashtagTweetsPage(router, "chirp", page.NextCursor, 2, alaToken)
+			Expect(page.Tweets).To(HaveLen(1))
+			Expect(page.Tweets[0].Content).To(Equal("#chirp 1"))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("should return empty list for unknown hashtag", func() {
+			Expect(retrieveHashtagTweets(router, "unknown", alaToken)).To(BeEmpty())
+		})
+	})
+
+	Describe("Get home feed", func() {
+		var (
+			alaTweet  *model.Tweet
+			bobTweet  *model.Tweet
+			toorToken string
+		)
+
+		BeforeEach(func() {
+			alaTweet = createTweet(router, "new ala tweet", alaToken)
+			bobTweet = createTweet(router, "new bob tweet", bobToken)
+			toorToken, _ = loginUser(router, toor)
+			followUser(router, ala.ID, toorToken)
+			followUser(router, bob.ID, toorToken)
+		})
+
+		It("should get users feed", func() {
+			expectedFeed := []*model.Tweet{
+				retrieveTweet(router, alaTweet.ID, toorToken),
+				retrieveTweet(router, bobTweet.ID, toorToken),
+			}
+			sort.Sort(utils.TweetsByCreationDateDesc(expectedFeed))
+
+			actualFeed := retrieveFeed(router, toorToken)
+
+			Expect(actualFeed).To(Equal(expectedFeed))
+			Expect(len(actualFeed)).To(Equal(len(expectedFeed)))
+		})
+
+		It("should get feed page by page", func() {
+			ernestToken, _ := loginUser(router, ernest)
+			ernestTweet := createTweet(router, "new ernest tweet", ernestToken)
+			retweetTweet(router, ernestTweet.ID, bobToken)
+
+			page := retrieveFeedPage(router, "", 2, toorToken)
+			Expect(page.Tweets).To(HaveLen(2))
+			Expect(page.Tweets[0].ID).To(Equal(ernestTweet.ID))
+			Expect(page.Tweets[0].RetweetedBy.ID).To(Equal(bob.ID))
+			Expect(page.Tweets[1].ID).To(Equal(bobTweet.ID))
+			Expect(page.NextCursor).NotTo(BeEmpty())
+
+			page = retrieveFeedPage(router, page.NextCursor, 2, toorToken)
+			Expect(page.Tweets).To(HaveLen(1))
+			Expect(page.Tweets[0].ID).To(Equal(alaTweet.ID))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("should order tweets and retweets by time of the event", func() {
+			retweetTweet(router, alaTweet.ID, bobToken)
+
+			page := retrieveFeedPage(router, "", 1, toorToken)
+			Expect(page.Tweets).To(HaveLen(1))
+			Expect(page.Tweets[0].ID).To(Equal(alaTweet.ID))
+			Expect(page.Tweets[0].RetweetedBy.ID).To(Equal(bob.ID))
+
+			page = retrieveFeedPage(router, page.NextCursor, 2, toorToken)
+			Expect(page.Tweets).To(HaveLen(2))
+			Expect(page.Tweets[0].ID).To(Equal(bobTweet.ID))
+			Expect(page.Tweets[1].ID).To(Equal(alaTweet.ID))
+			Expect(page.Tweets[1].RetweetedBy).To(BeNil())
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("should include own tweets and retweets of the user", func() {
+			ernestToken, _ := loginUser(router, ernest)
+			ernestTweet := createTweet(router, "new ernest tweet", ernestToken)
+			toorTweet := createTweet(router, "new toor tweet", toorToken)
+			retweetTweet(router, ernestTweet.ID, toorToken)
+
+			feed := retrieveFeed(router, toorToken)
+			Expect(feed).To(HaveLen(4))
+			Expect(feed[0].ID).To(Equal(ernestTweet.ID))
+			Expect(feed[0].RetweetedBy.ID).To(Equal(toor.ID))
+			Expect(feed[1].ID).To(Equal(toorTweet.ID))
+			Expect(feed[2].ID).To(Equal(bobTweet.ID))
+			Expect(feed[3].ID).To(Equal(alaTweet.ID))
+		})
+
+		It("should show tweet retweeted by several users once", func() {
+			ernestToken, _ := loginUser(router, ernest)
+			ernestTweet := createTweet(router, "new ernest tweet", ernestToken)
+			retweetTweet(router, ernestTweet.ID, bobToken)
+			retweetTweet(router, ernestTweet.ID, alaToken)
+
+			feed := retrieveFeed(router, toorToken)
+			Expect(feed).To(HaveLen(3))
+			Expect(feed[0].ID).To(Equal(ernestTweet.ID))
+			Expect(feed[0].RetweetedBy.ID).To(Equal(ala.ID))
+
+			unretweetTweet(router, ernestTweet.ID, alaToken)
+
+			feed = retrieveFeed(router, toorToken)
+			Expect(feed).To(HaveLen(3))
+			Expect(feed[0].ID).To(Equal(ernestTweet.ID))
+			Expect(feed[0].RetweetedBy.ID).To(Equal(bob.ID))
+		})
+
+		It("should show tweets posted after the feed was read", func() {
+			retrieveFeed(router, toorToken)
+
+			newTweet := createTweet(router, "newest ala tweet", alaToken)
+			retweetTweet(router, alaTweet.ID, bobToken)
+
+			feed := retrieveFeed(router, toorToken)
+			Expect(feed).To(HaveLen(4))
+			Expect(feed[0].ID).To(Equal(alaTweet.ID))
+			Expect(feed[0].RetweetedBy.ID).To(Equal(bob.ID))
+			Expect(feed[1].ID).To(Equal(newTweet.ID))
+			Expect(feed[3].ID).To(Equal(alaTweet.ID))
+			Expect(feed[3].RetweetedBy).To(BeNil())
+		})
+
+		It("should remove deleted tweets from the feed", func() {
+			retrieveFeed(router, toorToken)
+
+			deleteTweet(router, alaTweet.ID, alaToken)
+
+			feed := retrieveFeed(router, toorToken)
+			Expect(feed).To(HaveLen(1))
+			Expect(feed[0].ID).To(Equal(bobTweet.ID))
+		})
+
+		It("should update the feed after unfollowing", func() {
+			retrieveFeed(router, toorToken)
+
+			unfollowUser(router, ala.ID, toorToken)
+
+			feed := retrieveFeed(router, toorToken)
+			Expect(feed).To(HaveLen(1))
+			Expect(feed[0].ID).To(Equal(bobTweet.ID))
+		})
+
+		It("should show tweets of authors with too many followers", func() {
+			// fanout limit in the test configuration is 2
+			ernestToken, _ := loginUser(router, ernest)
+			followUser(router, ala.ID, bobToken)
+			followUser(router, ala.ID, ernestToken)
+			retrieveFeed(router, toorToken)
+
+			newTweet := createTweet(router, "popular ala tweet", alaToken)
+
+			feed := retrieveFeed(router, toorToken)
+			Expect(feed).To(HaveLen(3))
+			Expect(feed[0].ID).To(Equal(newTweet.ID))
+			Expect(feed[1].ID).To(Equal(bobTweet.ID))
+			Expect(feed[2].ID).To(Equal(alaTweet.ID))
+		})
+
+		It("should return only tweets newer than since_id", func() {
+			newTweet := createTweet(router, "newest ala tweet", alaToken)
+
+			feed := retrieveFeedSince(router, bobTweet.ID, toorToken)
+			Expect(feed).To(HaveLen(1))
+			Expect(feed[0].ID).To(Equal(newTweet.ID))
+
+			Expect(retrieveFeedSince(router, newTweet.ID, toorToken)).To(BeEmpty())
+		})
+
+		It("should use position of the latest retweet as since_id", func() {
+			retweetTweet(router, alaTweet.ID, bobToken)
+			Expect(retrieveFeedSince(router, alaTweet.ID, toorToken)).To(BeEmpty())
+
+			newTweet := createTweet(router, "newest bob tweet", bobToken)
+
+			feed := retrieveFeedSince(router, alaTweet.ID, toorToken)
+			Expect(feed).To(HaveLen(1))
+			Expect(feed[0].ID).To(Equal(newTweet.ID))
+		})
+
+		It("should count tweets newer than since_id", func() {
+			Expect(retrieveFeedNewCount(router, alaTweet.ID, toorToken)).To(Equal(1))
+
+			createTweet(router, "newest ala tweet", alaToken)
+			retweetTweet(router, alaTweet.ID, bobToken)
+
+			Expect(retrieveFeedNewCount(router, bobTweet.ID, toorToken)).To(Equal(2))
+		})
+
+		It("should return bad request when since_id is invalid or missing", func() {
+			req := request("GET", "/feed", nil).authorize(toorToken).urlQuery("since_id", "abc").build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+			req = request("GET", "/feed/new_count", nil).authorize(toorToken).build()
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should return not found when since_id tweet does not exist", func() {
+			req := request("GET", "/feed/new_count", nil).authorize(toorToken).urlQuery("since_id", int64(123456)).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Stream feed", func() {
+		var (
+			testServer *httptest.Server
+			events     <-chan *streamEvent
+			closeFeed  func()
+		)
+
+		BeforeEach(func() {
+			followUser(router, ala.ID, bobToken)
+
+			testServer = httptest.NewServer(router)
+			events, closeFeed = openFeedStream(testServer.URL, bobToken)
+		})
+
+		AfterEach(func() {
+			closeFeed()
+			testServer.Close()
+		})
+
+		It("should send new tweets of followees", func() {
+			tweet := createTweet(router, "new ala tweet", alaToken)
+
+			var event *streamEvent
+			Eventually(events).Should(Receive(&event))
+			Expect(event.Type).To(Equal("tweet"))
+
+			var streamedTweet model.Tweet
+			Expect(json.Unmarshal(event.Data, &streamedTweet)).To(Succeed())
+			Expect(streamedTweet.ID).To(Equal(tweet.ID))
+			Expect(streamedTweet.Content).To(Equal(tweet.Content))
+		})
+
+		It("should not send tweets of users who are not followed", func() {
+			toorToken, _ := loginUser(router, toor)
+			createTweet(router, "new toor tweet", toorToken)
+
+			Consistently(events, "100ms").ShouldNot(Receive())
+		})
+
+		It("should not send muted tweets", func() {
+			muteWord(router, "spoilers", bobToken)
+			mutedEvents, closeMutedFeed := openFeedStream(testServer.URL, bobToken)
+			defer closeMutedFeed()
+
+			createTweet(router, "new ala tweet with spoilers", alaToken)
+			tweet := createTweet(router, "new ala tweet", alaToken)
+
+			var event *streamEvent
+			Eventually(mutedEvents).Should(Receive(&event))
+
+			var streamedTweet model.Tweet
+			Expect(json.Unmarshal(event.Data, &streamedTweet)).To(Succeed())
+			Expect(streamedTweet.ID).To(Equal(tweet.ID))
+			Consistently(mutedEvents, "100ms").ShouldNot(Receive())
+		})
+
+		It("should send like count changes and deletions", func() {
+			tweet := createTweet(router, "new ala tweet", alaToken)
+			Eventually(events).Should(Receive())
+
+			likeTweet(router, tweet.ID, bobToken)
+
+			var event *streamEvent
+			Eventually(events).Should(Receive(&event))
+			Expect(event.Type).To(Equal("like_count"))
+			Expect(event.Data).To(MatchJSON(fmt.Sprintf(`{"tweet_id": %d, "like_count": 1}`, tweet.ID)))
+
+			deleteTweet(router, tweet.ID, alaToken)
+
+			Eventually(events).Should(Receive(&event))
+			Expect(event.Type).To(Equal("delete"))
+			Expect(event.Data).To(MatchJSON(fmt.Sprintf(`{"tweet_id": %d}`, tweet.ID)))
+		})
+
+		It("should reject stream without valid token", func() {
+			req := request("GET", "/stream/feed", nil).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("Lists", func() {
+		var (
+			toorToken string
+			list      *model.List
+		)
+
+		BeforeEach(func() {
+			toorToken, _ = loginUser(router, toor)
+			list = createList(router, "friends", false, alaToken)
+		})
+
+		It("should create list and manage its members", func() {
+			Expect(list.Name).To(Equal("friends"))
+			Expect(list.Owner.ID).To(Equal(ala.ID))
+			Expect(list.Private).To(BeFalse())
+			Expect(list.MemberCount).To(Equal(int64(0)))
+
+			Expect(addListMember(router, list.ID, bob.ID, alaToken).MemberCount).To(Equal(int64(1)))
+			Expect(addListMember(router, list.ID, bob.ID, alaToken).MemberCount).To(Equal(int64(1)))
+			Expect(addListMember(router, list.ID, toor.ID, alaToken).MemberCount).To(Equal(int64(2)))
+
+			members := retrieveListMembers(router, list.ID, bobToken)
+			Expect(members).To(HaveLen(2))
+			Expect(members[0].ID).To(Equal(toor.ID))
+			Expect(members[1].ID).To(Equal(bob.ID))
+
+			Expect(removeListMember(router, list.ID, toor.ID, alaToken).MemberCount).To(Equal(int64(1)))
+			Expect(retrieveListMembers(router, list.ID, alaToken)).To(HaveLen(1))
+		})
+
+		It("should return timeline of members of the list", func() {
+			addListMember(router, list.ID, bob.ID, alaToken)
+			addListMember(router, list.ID, toor.ID, alaToken)
+
+			bobTweet := createTweet(router, "bob tweet", bobToken)
+			createTweet(router, "ala tweet", alaToken)
+			toorTweet := createTweet(router, "toor tweet", toorToken)
+			retweetTweet(router, bobTweet.ID, toorToken)
+
+			timeline := retrieveListTimeline(router, list.ID, alaToken)
+			Expect(timeline).To(HaveLen(3))
+			Expect(timeline[0].ID).To(Equal(bobTweet.ID))
+			Expect(timeline[0].RetweetedBy.ID).To(Equal(toor.ID))
+			Expect(timeline[1].ID).To(Equal(toorTweet.ID))
+			Expect(timeline[2].ID).To(Equal(bobTweet.ID))
+			Expect(timeline[2].RetweetedBy).To(BeNil())
+
+			// list timeline does not depend on who is followed
+			Expect(retrieveFeed(router, alaToken)).To(HaveLen(1))
+
+			page := retrieveListTimelinePage(router, list.ID, "", 2, alaToken)
+			Expect(page.Tweets).To(HaveLen(2))
+			Expect(page.NextCursor).NotTo(BeEmpty())
+
+			page = retrieveListTimelinePage(router, list.ID, page.NextCursor, 2, alaToken)
+			Expect(page.Tweets).To(HaveLen(1))
+			Expect(page.Tweets[0].ID).To(Equal(bobTweet.ID))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("should not show followers-only tweets of members who are not followed", func() {
+			addListMember(router, list.ID, bob.ID, alaToken)
+			createTweetWithVisibility(router, "for followers", model.VisibilityFollowers, bobToken)
+
+			Expect(retrieveListTimeline(router, list.ID, alaToken)).To(BeEmpty())
+
+			followUser(router, bob.ID, alaToken)
+			Expect(retrieveListTimeline(router, list.ID, alaToken)).To(HaveLen(1))
+		})
+
+		It("should let other users subscribe to public lists", func() {
+			subscribedList := subscribeList(router, list.ID, bobToken)
+			Expect(subscribedList.Subscribed).To(BeTrue())
+			Expect(subscribedList.SubscriberCount).To(Equal(int64(1)))
+			Expect(retrieveList(router, list.ID, alaToken).Subscribed).To(BeFalse())
+
+			bobList := createList(router, "bob list", false, bobToken)
+			lists := retrieveLists(router, "/lists", bobToken)
+			Expect(lists).To(HaveLen(2))
+			Expect(lists[0].ID).To(Equal(bobList.ID))
+			Expect(lists[1].ID).To(Equal(list.ID))
+
+			unsubscribedList := unsubscribeList(router, list.ID, bobToken)
+			Expect(unsubscribedList.Subscribed).To(BeFalse())
+			Expect(unsubscribedList.SubscriberCount).To(Equal(int64(0)))
+			Expect(retrieveLists(router, "/lists", bobToken)).To(HaveLen(1))
+		})
+
+		It("should hide private lists from other users", func() {
+			privateList := createList(router, "secret", true, alaToken)
+			Expect(privateList.Private).To(BeTrue())
+			Expect(retrieveList(router, privateList.ID, alaToken).ID).To(Equal(privateList.ID))
+
+			paths := []string{
+				fmt.Sprintf("/lists/%v", privateList.ID),
+				fmt.Sprintf("/lists/%v/members", privateList.ID),
+				fmt.Sprintf("/lists/%v/timeline", privateList.ID),
+			}
+			for _, path := range paths {
+				req := request("GET", path, nil).authorize(bobToken).build()
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			}
+
+			req := request("POST", fmt.Sprintf("/lists/%v/subscribe", privateList.ID), nil).authorize(bobToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+
+			Expect(retrieveLists(router, fmt.Sprintf("/users/%v/lists", ala.ID), alaToken)).To(HaveLen(2))
+
+			lists := retrieveLists(router, fmt.Sprintf("/users/%v/lists", ala.ID), bobToken)
+			Expect(lists).To(HaveLen(1))
+			Expect(lists[0].ID).To(Equal(list.ID))
+		})
+
+		It("should let only the owner modify the list", func() {
+			paths := map[string]string{
+				fmt.Sprintf("/lists/%v/members/%v", list.ID, bob.ID): "POST",
+				fmt.Sprintf("/lists/%v/members/%v", list.ID, ala.ID): "DELETE",
+				fmt.Sprintf("/lists/%v", list.ID):                    "DELETE",
+			}
+			for path, method := range paths {
+				req := request(method, path, nil).authorize(bobToken).build()
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusForbidden))
+			}
+
+			req := request("POST", fmt.Sprintf("/lists/%v/subscribe", list.ID), nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+
+			req = request("DELETE", fmt.Sprintf("/lists/%v", list.ID), nil).authorize(alaToken).build()
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+
+			req = request("GET", fmt.Sprintf("/lists/%v", list.ID), nil).authorize(alaToken).build()
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should reject invalid names and members", func() {
+			for _, name := range []string{"", "   ", strings.Repeat("a", 26)} {
+				req := request("POST", "/lists", body(&model.NewList{Name: name})).json().authorize(alaToken).build()
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			}
+
+			req := request("POST", fmt.Sprintf("/lists/%v/members/%v", list.ID, 0), nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Mutes", func() {
+		var (
+			toorToken   string
+			ernestToken string
+		)
+
+		BeforeEach(func() {
+			toorToken, _ = loginUser(router, toor)
+			ernestToken, _ = loginUser(router, ernest)
+		})
+
+		It("should mute and unmute users", func() {
+			muteUser(router, bob.ID, alaToken)
+			muteUser(router, toor.ID, alaToken)
+			muteUser(router, bob.ID, alaToken)
+
+			mutedUsers := retrieveMutedUsers(router, alaToken)
+			Expect(mutedUsers).To(HaveLen(2))
+			Expect(mutedUsers[0].ID).To(Equal(toor.ID))
+			Expect(mutedUsers[1].ID).To(Equal(bob.ID))
+			Expect(retrieveMutedUsers(router, bobToken)).To(BeEmpty())
+
+			unmuteUser(router, toor.ID, alaToken)
+			mutedUsers = retrieveMutedUsers(router, alaToken)
+			Expect(mutedUsers).To(HaveLen(1))
+			Expect(mutedUsers[0].ID).To(Equal(bob.ID))
+		})
+
+		It("should not mute himself or user which does not exist", func() {
+			req := request("POST", fmt.Sprintf("/users/%v/mute", ala.ID), nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+			req = request("POST", fmt.Sprintf("/users/%v/mute", ernest.ID+1000), nil).authorize(alaToken).build()
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should filter tweets and retweets of muted users out of the feed", func() {
+			followUser(router, bob.ID, alaToken)
+			followUser(router, toor.ID, alaToken)
+
+			bobTweet := createTweet(router, "bob tweet", bobToken)
+			toorTweet := createTweet(router, "toor tweet", toorToken)
+			retweetTweet(router, bobTweet.ID, toorToken)
+			alaTweet := createTweet(router, "ala tweet", alaToken)
+			Expect(retrieveFeed(router, alaToken)).To(HaveLen(4))
+
+			muteUser(router, bob.ID, alaToken)
+			feed := retrieveFeed(router, alaToken)
+			Expect(feed).To(HaveLen(2))
+			Expect(feed[0].ID).To(Equal(alaTweet.ID))
+			Expect(feed[1].ID).To(Equal(toorTweet.ID))
+
+			// mutes affect only the muting user
+			followUser(router, bob.ID, ernestToken)
+			Expect(retrieveFeed(router, ernestToken)).To(HaveLen(1))
+
+			unmuteUser(router, bob.ID, alaToken)
+			Expect(retrieveFeed(router, alaToken)).To(HaveLen(4))
+		})
+
+		It("should filter tweets with muted words out of the feed", func() {
+			followUser(router, bob.ID, alaToken)
+
+			createTweet(router, "I love #Go", bobToken)
+			createTweet(router, "Spoilers ahead!", bobToken)
+			helloTweet := createTweet(router, "hello google", bobToken)
+			alaTweet := createTweet(router, "go go go", alaToken)
+
+			muteWord(router, "go", alaToken)
+			muteWord(router, "  spoilers   AHEAD ", alaToken)
+			Expect(retrieveMutedWords(router, alaToken)).To(Equal([]string{"go", "spoilers ahead"}))
+
+			feed := retrieveFeed(router, alaToken)
+			Expect(feed).To(HaveLen(2))
+			Expect(feed[0].ID).To(Equal(alaTweet.ID))
+			Expect(feed[1].ID).To(Equal(helloTweet.ID))
+
+			unmuteWord(router, "go", alaToken)
+			muteWord(router, "#go", alaToken)
+			Expect(retrieveFeed(router, alaToken)).To(HaveLen(2))
+
+			unmuteWord(router, "#GO", alaToken)
+			unmuteWord(router, "spoilers ahead", alaToken)
+			Expect(retrieveMutedWords(router, alaToken)).To(BeEmpty())
+			Expect(retrieveFeed(router, alaToken)).To(HaveLen(4))
+		})
+
+		It("should not mute empty or too long words", func() {
+			for _, word := range []string{"   ", "!?", strings.Repeat("a", 51)} {
+				req := request("POST", "/mutes/words", body(&model.NewMutedWord{Word: word})).json().authorize(alaToken).build()
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			}
+		})
+
+		It("should filter muted replies out of the conversation", func() {
+			tweet := createTweet(router, "bob tweet", bobToken)
+			toorReply := createReply(router, "toor reply", tweet.ID, toorToken)
+			createReply(router, "reply to toor", toorReply.ID, ernestToken)
+			ernestReply := createReply(router, "ernest reply about spoilers", tweet.ID, ernestToken)
+
+			Expect(retrieveConversation(router, tweet.ID, alaToken).Replies).To(HaveLen(2))
+
+			muteUser(router, toor.ID, alaToken)
+			replies := retrieveConversation(router, tweet.ID, alaToken).Replies
+			Expect(replies).To(HaveLen(1))
+			Expect(replies[0].Tweet.ID).To(Equal(ernestReply.ID))
+
+			muteWord(router, "spoilers", alaToken)
+			Expect(retrieveConversation(router, tweet.ID, alaToken).Replies).To(BeEmpty())
+
+			// muted tweet itself is still shown when it is opened
+			conversation := retrieveConversation(router, toorReply.ID, alaToken)
+			Expect(conversation.Tweet.ID).To(Equal(toorReply.ID))
+			Expect(conversation.Replies).To(HaveLen(1))
+		})
+
+		It("should filter muted tweets out of list timeline", func() {
+			list := createList(router, "friends", false, alaToken)
+			addListMember(router, list.ID, bob.ID, alaToken)
+			addListMember(router, list.ID, toor.ID, alaToken)
+
+			createTweet(router, "bob tweet", bobToken)
+			toorTweet := createTweet(router, "toor tweet", toorToken)
+			createTweet(router, "toor tweet with spoilers", toorToken)
+			Expect(retrieveListTimeline(router, list.ID, alaToken)).To(HaveLen(3))
+
+			muteUser(router, bob.ID, alaToken)
+			muteWord(router, "spoilers", alaToken)
+			timeline := retrieveListTimeline(router, list.ID, alaToken)
+			Expect(timeline).To(HaveLen(1))
+			Expect(timeline[0].ID).To(Equal(toorTweet.ID))
+		})
+
+		It("should not count muted tweets as new in the feed", func() {
+			followUser(router, bob.ID, alaToken)
+			followUser(router, toor.ID, alaToken)
+
+			alaTweet := createTweet(router, "ala tweet", alaToken)
+			bobTweet := createTweet(router, "bob tweet", bobToken)
+			createTweet(router, "toor tweet", toorToken)
+			retweetTweet(router, bobTweet.ID, toorToken)
+			createTweet(router, "toor tweet with spoilers", toorToken)
+
+			muteUser(router, bob.ID, alaToken)
+			Expect(retrieveFeedNewCount(router, alaTweet.ID, alaToken)).To(Equal(2))
+
+			muteWord(router, "spoilers", alaToken)
+			Expect(retrieveFeedNewCount(router, alaTweet.ID, alaToken)).To(Equal(1))
+		})
+	})
+
+	Describe("Gateway", func() {
+		var (
+			testServer *httptest.Server
+			messages   <-chan *gatewayMessage
+			conn       *websocket.Conn
+		)
+
+		expectNotification := func(kind string, userID, tweetID int64) {
+			var message *gatewayMessage
+			Eventually(messages).Should(Receive(&message))
+			Expect(message.Type).To(Equal("notification"))
+
+			expected := fmt.Sprintf(`{"kind": %q, "user_id": %d}`, kind, userID)
+			if tweetID != 0 {
+				expected = fmt.Sprintf(`{"kind": %q, "user_id": %d, "tweet_id": %d}`, kind, userID, tweetID)
+			}
+			Expect([]byte(message.Data)).To(MatchJSON(expected))
+		}
+
+		BeforeEach(func() {
+			testServer = httptest.NewServer(router)
+			messages, conn = openGateway(testServer.URL, bobToken)
+		})
+
+		AfterEach(func() {
+			conn.Close()
+			testServer.Close()
+		})
+
+		It("should notify about likes, retweets and follows", func() {
+			tweet := createTweet(router, "new bob tweet", bobToken)
+
+			likeTweet(router, tweet.ID, alaToken)
+			expectNotification("like", ala.ID, tweet.ID)
+
+			retweetTweet(router, tweet.ID, alaToken)
+			expectNotification("retweet", ala.ID, tweet.ID)
+
+			followUser(router, bob.ID, alaToken)
+			expectNotification("follow", ala.ID, 0)
+		})
+
+		It("should notify about mentions and replies", func() {
+			tweet := createTweet(router, "new bob tweet", bobToken)
+
+			mention := createTweet(router, "hi @"+bob.Username, alaToken)
+			expectNotification("mention", ala.ID, mention.ID)
+
+			reply := createReply(router, "reply", tweet.ID, alaToken)
+			expectNotification("reply", ala.ID, reply.ID)
+		})
+
+		It("should not notify about own and repeated actions", func() {
+			tweet := createTweet(router, "new bob tweet @"+bob.Username, bobToken)
+			likeTweet(router, tweet.ID, bobToken)
+
+			alaTweet := createTweet(router, "new ala tweet", alaToken)
+			likeTweet(router, alaTweet.ID, bobToken)
+
+			likeTweet(router, tweet.ID, alaToken)
+			expectNotification("like", ala.ID, tweet.ID)
+
+			likeTweet(router, tweet.ID, alaToken)
+			followUser(router, bob.ID, alaToken)
+			expectNotification("follow", ala.ID, 0)
+
+			followUser(router, bob.ID, alaToken)
+			Consistently(messages, "100ms").ShouldNot(Receive())
+		})
+
+		It("should not notify about mentions in tweets the user can not see", func() {
+			createTweetWithVisibility(router, "hi @"+bob.Username, model.VisibilityFollowers, alaToken)
+			Consistently(messages, "100ms").ShouldNot(Receive())
+
+			followUser(router, ala.ID, bobToken)
+			tweet := createTweetWithVisibility(router, "hi @"+bob.Username, model.VisibilityFollowers, alaToken)
+			expectNotification("mention", ala.ID, tweet.ID)
+		})
+
+		It("should not notify about muting nor about actions of muted users", func() {
+			muteUser(router, bob.ID, alaToken)
+			Consistently(messages, "100ms").ShouldNot(Receive())
+
+			muteUser(router, ala.ID, bobToken)
+			tweet := createTweet(router, "new bob tweet", bobToken)
+			likeTweet(router, tweet.ID, alaToken)
+			createTweet(router, "hi @"+bob.Username, alaToken)
+			Consistently(messages, "100ms").ShouldNot(Receive())
+
+			unmuteUser(router, ala.ID, bobToken)
+			followUser(router, bob.ID, alaToken)
+			expectNotification("follow", ala.ID, 0)
+		})
+
+		It("should relay typing indicators and acknowledge messages", func() {
+			alaMessages, alaConn := openGateway(testServer.URL, alaToken)
+			defer alaConn.Close()
+
+			err := alaConn.WriteJSON(map[string]interface{}{"id": "1", "type": "typing", "recipient_id": bob.ID})
+			Expect(err).NotTo(HaveOccurred())
+
+			var message *gatewayMessage
+			Eventually(alaMessages).Should(Receive(&message))
+			Expect(message.ID).To(Equal("1"))
+			Expect(message.Type).To(Equal("ack"))
+
+			Eventually(messages).Should(Receive(&message))
+			Expect(message.Type).To(Equal("typing"))
+			Expect([]byte(message.Data)).To(MatchJSON(fmt.Sprintf(`{"user_id": %d}`, ala.ID)))
+
+			err = alaConn.WriteJSON(map[string]interface{}{"id": "2", "type": "typing", "recipient_id": ala.ID})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(alaMessages).Should(Receive(&message))
+			Expect(message.ID).To(Equal("2"))
+			Expect(message.Type).To(Equal("error"))
+		})
+
+		It("should not relay typing indicators of muted users", func() {
+			alaMessages, alaConn := openGateway(testServer.URL, alaToken)
+			defer alaConn.Close()
+
+			muteUser(router, ala.ID, bobToken)
+			err := alaConn.WriteJSON(map[string]interface{}{"id": "1", "type": "typing", "recipient_id": bob.ID})
+			Expect(err).NotTo(HaveOccurred())
+
+			// muted user can not tell that the indicator was dropped
+			var message *gatewayMessage
+			Eventually(alaMessages).Should(Receive(&message))
+			Expect(message.Type).To(Equal("ack"))
+			Consistently(messages, "100ms").ShouldNot(Receive())
+		})
+
+		It("should reject connection without valid token", func() {
+			req := request("GET", "/gateway", nil).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("Like tweet", func() {
+		var (
+			alaTweet *model.Tweet
+			bobTweet *model.Tweet
+		)
+
+		BeforeEach(func() {
+			alaTweet = createTweet(router, "new ala tweet", alaToken)
+			bobTweet = createTweet(router, "new bob tweet", bobToken)
+		})
+
+		It("should like tweet and return new liked tweet with populated data", func() {
+			actualTweet := likeTweet(router, alaTweet.ID, alaToken)
+
+			Expect(actualTweet.LikeCount).To(BeEquivalentTo(1))
+			Expect(actualTweet.Liked).To(Equal(true))
+		})
+
+		It("tweet returned by like should match real tweet", func() {
+			actualTweet := likeTweet(router, alaTweet.ID, alaToken)
+
+			expectedTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+			Expect(actualTweet).To(Equal(expectedTweet))
+		})
+
+		It("should tweet be liked only once after consecutive likes", func() {
+			likeTweet(router, alaTweet.ID, alaToken)
+			likeTweet(router, alaTweet.ID, alaToken)
+
+			actualTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+
+			Expect(actualTweet.LikeCount).To(BeEquivalentTo(1))
+			Expect(actualTweet.Liked).To(Equal(true))
+		})
+
+		It("should return tweet liked twice after multiple (two) likes", func() {
+			likeTweet(router, alaTweet.ID, alaToken)
+			likeTweet(router, alaTweet.ID, bobToken)
+
+			actualTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+
+			Expect(actualTweet.LikeCount).To(BeEquivalentTo(2))
+		})
+
+		It("should return tweet with false `liked` field when tweet was not liked by user", func() {
+			likeTweet(router, bobTweet.ID, bobToken)
+
+			actualTweet := retrieveTweet(router, bobTweet.ID, alaToken)
+
+			Expect(actualTweet.Liked).To(Equal(false))
+		})
+	})
+
+	Describe("Unlike tweet", func() {
+		var (
+			alaTweet *model.Tweet
+		)
+
+		BeforeEach(func() {
+			alaTweet = createTweet(router, "new ala tweet", alaToken)
+		})
+
+		It("should unlike tweet and return unliked tweet with fresh data", func() {
+			likeTweet(router, alaTweet.ID, alaToken)
+
+			path := fmt.Sprintf("/tweets/%v/unlike", alaTweet.ID)
+			req := request("POST", path, nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			var actualTweet model.Tweet
+			err := json.Unmarshal(w.Body.Bytes(), &actualTweet)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(actualTweet.LikeCount).To(BeEquivalentTo(0))
+			Expect(actualTweet.Liked).To(Equal(false))
+		})
+
+		It("tweet returned by unlike should match real tweet", func() {
+			likeTweet(router, alaTweet.ID, alaToken)
+
+			actualTweet := unlikeTweet(router, alaTweet.ID, alaToken)
+
+			expectedTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+			Expect(actualTweet).To(Equal(expectedTweet))
+		})
+
+		It("should not perform any unexpected actions when trying to unlike not liked tweet", func() {
+			expectedTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+
+			actualTweet := unlikeTweet(router, alaTweet.ID, alaToken)
+
+			Expect(actualTweet).To(Equal(expectedTweet))
+		})
+
+		It("should not unlike tweet which is liked by someone else", func() {
+			likeTweet(router, alaTweet.ID, alaToken)
+			unlikeTweet(router, alaTweet.ID, bobToken)
 
 			alaActualTweet := retrieveTweet(router, alaTweet.ID, alaToken)
 			bobActualTweet := retrieveTweet(router, alaTweet.ID, bobToken)
@@ -654,6 +2402,227 @@ var _ = Describe("ServerTest", func() {
 		})
 	})
 
+	Describe("Likes", func() {
+		It("should list users who liked the tweet page by page", func() {
+			toorToken, _ := loginUser(router, toor)
+			tweet := createTweet(router, "new tweet", alaToken)
+			likeTweet(router, tweet.ID, alaToken)
+			likeTweet(router, tweet.ID, bobToken)
+			likeTweet(router, tweet.ID, toorToken)
+			followUser(router, toor.ID, alaToken)
+
+			page := retrieveTweetLikers(router, tweet.ID, "", 2, alaToken)
+			Expect(page.Users).To(HaveLen(2))
+			Expect(page.Users[0].ID).To(Equal(toor.ID))
+			Expect(page.Users[0].Following).To(BeTrue())
+			Expect(page.Users[1].ID).To(Equal(bob.ID))
+			Expect(page.Users[1].Following).To(BeFalse())
+			Expect(page.NextCursor).NotTo(BeEmpty())
+
+			page = retrieveTweetLikers(router, tweet.ID, page.NextCursor, 2, alaToken)
+			Expect(page.Users).To(HaveLen(1))
+			Expect(page.Users[0].ID).To(Equal(ala.ID))
+			Expect(page.NextCursor).To(BeEmpty())
+		})
+
+		It("should list tweets liked by the user ordered by time of liking", func() {
+			first := createTweet(router, "first tweet", alaToken)
+			second := createTweet(router, "second tweet", bobToken)
+			likeTweet(router, second.ID, bobToken)
+			likeTweet(router, first.ID, bobToken)
+
+			page := retrieveUserLikes(router, bob.ID, "", 10, alaToken)
+			Expect(page.Tweets).To(HaveLen(2))
+			Expect(page.Tweets[0].ID).To(Equal(first.ID))
+			Expect(page.Tweets[0].Liked).To(BeFalse())
+			Expect(page.Tweets[1].ID).To(Equal(second.ID))
+			Expect(page.NextCursor).To(BeEmpty())
+
+			deleteTweet(router, first.ID, alaToken)
+
+			page = retrieveUserLikes(router, bob.ID, "", 10, alaToken)
+			Expect(page.Tweets).To(HaveLen(1))
+			Expect(page.Tweets[0].ID).To(Equal(second.ID))
+		})
+
+		It("should return not found for not existing tweet or user", func() {
+			for _, path := range []string{"/tweets/123456/likes", "/users/123456/likes"} {
+				req := request("GET", path, nil).authorize(alaToken).build()
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			}
+		})
+	})
+
+	Describe("Tweet analytics", func() {
+		var tweet *model.Tweet
+
+		BeforeEach(func() {
+			tweet = createTweet(router, "new tweet", alaToken)
+		})
+
+		It("should count impressions and profile clicks after they are flushed", func() {
+			followUser(router, ala.ID, bobToken)
+			retrieveTweet(router, tweet.ID, bobToken)
+			retrieveFeed(router, bobToken)
+			likeTweet(router, tweet.ID, bobToken)
+
+			path := fmt.Sprintf("/users/%v", ala.ID)
+			req := request("GET", path, nil).authorize(bobToken).urlQuery("from_tweet", tweet.ID).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusOK))
+
+			analytics := retrieveTweetAnalytics(router, tweet.ID, alaToken)
+			Expect(analytics.Impressions).To(BeZero())
+			Expect(analytics.Likes).To(BeEquivalentTo(1))
+
+			_, err := accessor.FlushTweetStats()
+			Expect(err).NotTo(HaveOccurred())
+
+			analytics = retrieveTweetAnalytics(router, tweet.ID, alaToken)
+			Expect(analytics.TweetID).To(Equal(tweet.ID))
+			Expect(analytics.Granularity).To(Equal(model.GranularityDay))
+			Expect(analytics.Impressions).To(BeEquivalentTo(2))
+			Expect(analytics.Likes).To(BeEquivalentTo(1))
+			Expect(analytics.Retweets).To(BeZero())
+			Expect(analytics.ProfileClicks).To(BeEquivalentTo(1))
+			Expect(analytics.Timeline).To(HaveLen(1))
+		})
+
+		It("should not count impressions of tweets which are only created or liked", func() {
+			likeTweet(router, tweet.ID, bobToken)
+
+			_, err := accessor.FlushTweetStats()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(retrieveTweetAnalytics(router, tweet.ID, alaToken).Impressions).To(BeZero())
+		})
+
+		It("should allow only the author to see analytics", func() {
+			path := fmt.Sprintf("/tweets/%v/analytics", tweet.ID)
+			req := request("GET", path, nil).authorize(bobToken).build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should return bad request for invalid granularity", func() {
+			path := fmt.Sprintf("/tweets/%v/analytics", tweet.ID)
+			req := request("GET", path, nil).authorize(alaToken).urlQuery("granularity", "year").build()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("Retweet tweet", func() {
+		var (
+			alaTweet *model.Tweet
+		)
+
+		BeforeEach(func() {
+			alaTweet = createTweet(router, "new ala tweet", alaToken)
+		})
+
+		It("should retweet tweet and return retweeted tweet with populated data", func() {
+			actualTweet := retweetTweet(router, alaTweet.ID, bobToken)
+
+			Expect(actualTweet.RetweetCount).To(BeEquivalentTo(1))
+			Expect(actualTweet.Retweeted).To(Equal(true))
+		})
+
+		It("tweet returned by retweet should match real tweet", func() {
+			actualTweet := retweetTweet(router, alaTweet.ID, bobToken)
+
+			expectedTweet := retrieveTweet(router, alaTweet.ID, bobToken)
+			Expect(actualTweet).To(Equal(expectedTweet))
+		})
+
+		It("should tweet be retweeted only once after consecutive retweets", func() {
+			retweetTweet(router, alaTweet.ID, bobToken)
+			retweetTweet(router, alaTweet.ID, bobToken)
+
+			actualTweet := retrieveTweet(router, alaTweet.ID, bobToken)
+
+			Expect(actualTweet.RetweetCount).To(BeEquivalentTo(1))
+			Expect(actualTweet.Retweeted).To(Equal(true))
+		})
+
+		It("should return tweet with false `retweeted` field when tweet was not retweeted by user", func() {
+			retweetTweet(router, alaTweet.ID, bobToken)
+
+			actualTweet := retrieveTweet(router, alaTweet.ID, alaToken)
+
+			Expect(actualTweet.RetweetCount).To(BeEquivalentTo(1))
+			Expect(actualTweet.Retweeted).To(Equal(false))
+		})
+
+		It("should return not found code when trying to retweet not existing tweet", func() {
+			req := request("POST", "/tweets/123/retweet", nil).authorize(alaToken).build()
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should show retweet in followers feed", func() {
+			toorToken, _ := loginUser(router, toor)
+			followUser(router, bob.ID, toorToken)
+			retweetTweet(router, alaTweet.ID, bobToken)
+
+			actualFeed := retrieveFeed(router, toorToken)
+
+			Expect(actualFeed).To(HaveLen(1))
+			Expect(actualFeed[0].ID).To(Equal(alaTweet.ID))
+			Expect(actualFeed[0].RetweetedBy.ID).To(Equal(bob.ID))
+		})
+	})
+
+	Describe("Unretweet tweet", func() {
+		var (
+			alaTweet *model.Tweet
+		)
+
+		BeforeEach(func() {
+			alaTweet = createTweet(router, "new ala tweet", alaToken)
+		})
+
+		It("should unretweet tweet and return unretweeted tweet with fresh data", func() {
+			retweetTweet(router, alaTweet.ID, bobToken)
+
+			actualTweet := unretweetTweet(router, alaTweet.ID, bobToken)
+
+			Expect(actualTweet.RetweetCount).To(BeEquivalentTo(0))
+			Expect(actualTweet.Retweeted).To(Equal(false))
+		})
+
+		It("should not unretweet tweet which is retweeted by someone else", func() {
+			retweetTweet(router, alaTweet.ID, bobToken)
+			unretweetTweet(router, alaTweet.ID, alaToken)
+
+			actualTweet := retrieveTweet(router, alaTweet.ID, bobToken)
+
+			Expect(actualTweet.RetweetCount).To(BeEquivalentTo(1))
+			Expect(actualTweet.Retweeted).To(Equal(true))
+		})
+
+		It("should remove retweet from followers feed", func() {
+			toorToken, _ := loginUser(router, toor)
+			followUser(router, bob.ID, toorToken)
+			retweetTweet(router, alaTweet.ID, bobToken)
+			unretweetTweet(router, alaTweet.ID, bobToken)
+
+			actualFeed := retrieveFeed(router, toorToken)
+
+			Expect(actualFeed).To(BeEmpty())
+		})
+	})
+
 	Describe("Refresh auth token", func() {
 		It("should refresh auth token", func() {
 			refreshTokenRequest := &model.RefreshAuthTokenRequest{
@@ -1,15 +1,23 @@
 package integration
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/png"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	. "github.com/onsi/gomega"
 
 	"github.com/VirrageS/chirp/backend/model"
@@ -105,32 +113,40 @@ func unfollowUser(s *gin.Engine, userID int64, authToken string) *model.PublicUs
 
 // Followers
 func retrieveFollowers(s *gin.Engine, userID int64, authToken string) []*model.PublicUser {
+	return retrieveFollowersPage(s, userID, "", 20, authToken).Users
+}
+
+func retrieveFollowersPage(s *gin.Engine, userID int64, cursor string, limit int, authToken string) *model.UsersPage {
 	path := fmt.Sprintf("/users/%v/followers", userID)
-	req := request("GET", path, nil).authorize(authToken).build()
+	req := request("GET", path, nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
 	w := httptest.NewRecorder()
 	s.ServeHTTP(w, req)
 	Expect(w.Code).To(Equal(http.StatusOK))
 
-	var followers []*model.PublicUser
-	err := json.Unmarshal(w.Body.Bytes(), &followers)
+	var page model.UsersPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
 	Expect(err).NotTo(HaveOccurred())
 
-	return followers
+	return &page
 }
 
 // Followees
 func retrieveFollowees(s *gin.Engine, userID int64, authToken string) []*model.PublicUser {
+	return retrieveFolloweesPage(s, userID, "", 20, authToken).Users
+}
+
+func retrieveFolloweesPage(s *gin.Engine, userID int64, cursor string, limit int, authToken string) *model.UsersPage {
 	path := fmt.Sprintf("/users/%v/followees", userID)
-	req := request("GET", path, nil).authorize(authToken).build()
+	req := request("GET", path, nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
 	w := httptest.NewRecorder()
 	s.ServeHTTP(w, req)
 	Expect(w.Code).To(Equal(http.StatusOK))
 
-	var followees []*model.PublicUser
-	err := json.Unmarshal(w.Body.Bytes(), &followees)
+	var page model.UsersPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
 	Expect(err).NotTo(HaveOccurred())
 
-	return followees
+	return &page
 }
 
 // Tweet
@@ -151,6 +167,216 @@ func createTweet(s *gin.Engine, content string, authToken string) *model.Tweet {
 	return &tweet
 }
 
+func createReply(s *gin.Engine, content string, inReplyToID int64, authToken string) *model.Tweet {
+	newTweet := &model.NewTweet{
+		Content:     content,
+		InReplyToID: inReplyToID,
+	}
+
+	req := request("POST", "/tweets", body(newTweet)).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusCreated))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func createQuote(s *gin.Engine, content string, quotedTweetID int64, authToken string) *model.Tweet {
+	newTweet := &model.NewTweet{
+		Content:       content,
+		QuotedTweetID: quotedTweetID,
+	}
+
+	req := request("POST", "/tweets", body(newTweet)).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusCreated))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func retrieveConversation(s *gin.Engine, tweetID int64, authToken string) *model.Conversation {
+	return retrieveConversationPage(s, tweetID, "", 20, authToken)
+}
+
+func retrieveConversationPage(s *gin.Engine, tweetID int64, cursor string, limit int, authToken string) *model.Conversation {
+	path := fmt.Sprintf("/tweets/%v/conversation", tweetID)
+	req := request("GET", path, nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var conversation model.Conversation
+	err := json.Unmarshal(w.Body.Bytes(), &conversation)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &conversation
+}
+
+func createTweetWithVisibility(s *gin.Engine, content string, visibility string, authToken string) *model.Tweet {
+	newTweet := &model.NewTweet{
+		Content:    content,
+		Visibility: visibility,
+	}
+
+	req := request("POST", "/tweets", body(newTweet)).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusCreated))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func createTweetWithMedia(s *gin.Engine, content string, mediaIDs []int64, authToken string) *model.Tweet {
+	newTweet := &model.NewTweet{
+		Content:  content,
+		MediaIDs: mediaIDs,
+	}
+
+	req := request("POST", "/tweets", body(newTweet)).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusCreated))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+// retrieveMediaStatus returns status code of the request for data of the media.
+func retrieveMediaStatus(s *gin.Engine, media *model.Media, authToken string) int {
+	req := request("GET", media.URL, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w.Code
+}
+
+func createTweetWithPoll(s *gin.Engine, content string, options []string, authToken string) *model.Tweet {
+	newTweet := &model.NewTweet{
+		Content: content,
+		Poll: &model.NewPoll{
+			Options:  options,
+			ClosesAt: time.Now().Add(time.Hour),
+		},
+	}
+
+	req := request("POST", "/tweets", body(newTweet)).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusCreated))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func votePoll(s *gin.Engine, tweetID int64, option int, authToken string) *model.Tweet {
+	path := fmt.Sprintf("/tweets/%v/poll/vote", tweetID)
+	req := request("POST", path, body(&model.PollVote{Option: &option})).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func editTweet(s *gin.Engine, tweetID int64, content string, authToken string) *model.Tweet {
+	path := fmt.Sprintf("/tweets/%v", tweetID)
+	req := request("PATCH", path, body(&model.NewTweetContent{Content: content})).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func retrieveTweetRevisions(s *gin.Engine, tweetID int64, authToken string) []*model.TweetRevision {
+	path := fmt.Sprintf("/tweets/%v/revisions", tweetID)
+	req := request("GET", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var revisions []*model.TweetRevision
+	err := json.Unmarshal(w.Body.Bytes(), &revisions)
+	Expect(err).NotTo(HaveOccurred())
+
+	return revisions
+}
+
+func scheduleTweet(s *gin.Engine, content string, publishAt time.Time, authToken string) *model.ScheduledTweet {
+	newTweet := &model.NewScheduledTweet{
+		NewTweet:  model.NewTweet{Content: content},
+		PublishAt: publishAt,
+	}
+	req := request("POST", "/scheduled_tweets", body(newTweet)).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusCreated))
+
+	var scheduledTweet model.ScheduledTweet
+	err := json.Unmarshal(w.Body.Bytes(), &scheduledTweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &scheduledTweet
+}
+
+func retrieveScheduledTweets(s *gin.Engine, authToken string) []*model.ScheduledTweet {
+	req := request("GET", "/scheduled_tweets", nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var scheduledTweets []*model.ScheduledTweet
+	err := json.Unmarshal(w.Body.Bytes(), &scheduledTweets)
+	Expect(err).NotTo(HaveOccurred())
+
+	return scheduledTweets
+}
+
+func retrieveHashtagTweets(s *gin.Engine, hashtag string, authToken string) []*model.Tweet {
+	return retrieveHashtagTweetsPage(s, hashtag, "", 20, authToken).Tweets
+}
+
+func retrieveHashtagTweetsPage(s *gin.Engine, hashtag string, cursor string, limit int, authToken string) *model.TweetsPage {
+	path := fmt.Sprintf("/hashtags/%v/tweets", hashtag)
+	req := request("GET", path, nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.TweetsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &page
+}
+
 func deleteTweet(s *gin.Engine, tweetID int64, authToken string) {
 	path := fmt.Sprintf("/tweets/%v", tweetID)
 	req := request("DELETE", path, nil).authorize(authToken).build()
@@ -159,6 +385,34 @@ func deleteTweet(s *gin.Engine, tweetID int64, authToken string) {
 	Expect(w.Code).To(Equal(http.StatusNoContent))
 }
 
+func restoreTweet(s *gin.Engine, tweetID int64, authToken string) *model.Tweet {
+	path := fmt.Sprintf("/tweets/%v/restore", tweetID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func retrieveTweetAnalytics(s *gin.Engine, tweetID int64, authToken string) *model.TweetAnalytics {
+	path := fmt.Sprintf("/tweets/%v/analytics", tweetID)
+	req := request("GET", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var analytics model.TweetAnalytics
+	err := json.Unmarshal(w.Body.Bytes(), &analytics)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &analytics
+}
+
 func retrieveTweet(s *gin.Engine, tweetID int64, authToken string) *model.Tweet {
 	path := fmt.Sprintf("/tweets/%v", tweetID)
 	req := request("GET", path, nil).authorize(authToken).build()
@@ -201,34 +455,523 @@ func unlikeTweet(s *gin.Engine, tweetID int64, authToken string) *model.Tweet {
 	return &tweet
 }
 
+func retweetTweet(s *gin.Engine, tweetID int64, authToken string) *model.Tweet {
+	path := fmt.Sprintf("/tweets/%v/retweet", tweetID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func unretweetTweet(s *gin.Engine, tweetID int64, authToken string) *model.Tweet {
+	path := fmt.Sprintf("/tweets/%v/unretweet", tweetID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func bookmarkTweet(s *gin.Engine, tweetID int64, authToken string) *model.Tweet {
+	path := fmt.Sprintf("/tweets/%v/bookmark", tweetID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func unbookmarkTweet(s *gin.Engine, tweetID int64, authToken string) *model.Tweet {
+	path := fmt.Sprintf("/tweets/%v/bookmark", tweetID)
+	req := request("DELETE", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var tweet model.Tweet
+	err := json.Unmarshal(w.Body.Bytes(), &tweet)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &tweet
+}
+
+func pinTweet(s *gin.Engine, tweetID int64, authToken string) *model.PublicUser {
+	path := fmt.Sprintf("/tweets/%v/pin", tweetID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var user model.PublicUser
+	err := json.Unmarshal(w.Body.Bytes(), &user)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &user
+}
+
+func unpinTweet(s *gin.Engine, tweetID int64, authToken string) *model.PublicUser {
+	path := fmt.Sprintf("/tweets/%v/pin", tweetID)
+	req := request("DELETE", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var user model.PublicUser
+	err := json.Unmarshal(w.Body.Bytes(), &user)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &user
+}
+
+func retrieveBookmarks(s *gin.Engine, cursor string, limit int, authToken string) *model.TweetsPage {
+	req := request("GET", "/bookmarks", nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.TweetsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &page
+}
+
+func retrieveTweetLikers(s *gin.Engine, tweetID int64, cursor string, limit int, authToken string) *model.UsersPage {
+	path := fmt.Sprintf("/tweets/%v/likes", tweetID)
+	req := request("GET", path, nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.UsersPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &page
+}
+
+func retrieveUserLikes(s *gin.Engine, userID int64, cursor string, limit int, authToken string) *model.TweetsPage {
+	path := fmt.Sprintf("/users/%v/likes", userID)
+	req := request("GET", path, nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.TweetsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &page
+}
+
 func retrieveUserTweets(s *gin.Engine, authToken string, userID int64) []*model.Tweet {
-	req := request("GET", fmt.Sprintf("/users/%v/tweets", userID), nil).authorize(authToken).build()
+	return retrieveUserTweetsPage(s, userID, "", 20, authToken).Tweets
+}
+
+func retrieveUserTweetsPage(s *gin.Engine, userID int64, cursor string, limit int, authToken string) *model.TweetsPage {
+	path := fmt.Sprintf("/users/%v/tweets", userID)
+	req := request("GET", path, nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
 	w := httptest.NewRecorder()
 	s.ServeHTTP(w, req)
 	Expect(w.Code).To(Equal(http.StatusOK))
 
-	var tweets []*model.Tweet
-	err := json.Unmarshal(w.Body.Bytes(), &tweets)
+	var page model.TweetsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
 	Expect(err).NotTo(HaveOccurred())
 
-	return tweets
+	return &page
+}
+
+func retrieveUserMentions(s *gin.Engine, authToken string, userID int64) []*model.Tweet {
+	req := request("GET", fmt.Sprintf("/users/%v/mentions", userID), nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.TweetsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return page.Tweets
 }
 
 // Home feed
 func retrieveFeed(s *gin.Engine, authToken string) []*model.Tweet {
-	req := request("GET", "/feed", nil).authorize(authToken).build()
+	return retrieveFeedPage(s, "", 20, authToken).Tweets
+}
+
+func retrieveFeedPage(s *gin.Engine, cursor string, limit int, authToken string) *model.TweetsPage {
+	req := request("GET", "/feed", nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.TweetsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &page
+}
+
+func retrieveFeedSince(s *gin.Engine, sinceID int64, authToken string) []*model.Tweet {
+	req := request("GET", "/feed", nil).authorize(authToken).urlQuery("since_id", sinceID).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.TweetsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return page.Tweets
+}
+
+func retrieveFeedNewCount(s *gin.Engine, sinceID int64, authToken string) int {
+	req := request("GET", "/feed/new_count", nil).authorize(authToken).urlQuery("since_id", sinceID).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var count model.NewTweetsCount
+	err := json.Unmarshal(w.Body.Bytes(), &count)
+	Expect(err).NotTo(HaveOccurred())
+
+	return count.Count
+}
+
+// Lists
+func createList(s *gin.Engine, name string, private bool, authToken string) *model.List {
+	newList := &model.NewList{Name: name, Private: private}
+	req := request("POST", "/lists", body(newList)).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusCreated))
+
+	var list model.List
+	err := json.Unmarshal(w.Body.Bytes(), &list)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &list
+}
+
+func retrieveList(s *gin.Engine, listID int64, authToken string) *model.List {
+	path := fmt.Sprintf("/lists/%v", listID)
+	req := request("GET", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var list model.List
+	err := json.Unmarshal(w.Body.Bytes(), &list)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &list
+}
+
+func retrieveLists(s *gin.Engine, path string, authToken string) []*model.List {
+	req := request("GET", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var lists []*model.List
+	err := json.Unmarshal(w.Body.Bytes(), &lists)
+	Expect(err).NotTo(HaveOccurred())
+
+	return lists
+}
+
+func addListMember(s *gin.Engine, listID, userID int64, authToken string) *model.List {
+	path := fmt.Sprintf("/lists/%v/members/%v", listID, userID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var list model.List
+	err := json.Unmarshal(w.Body.Bytes(), &list)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &list
+}
+
+func removeListMember(s *gin.Engine, listID, userID int64, authToken string) *model.List {
+	path := fmt.Sprintf("/lists/%v/members/%v", listID, userID)
+	req := request("DELETE", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var list model.List
+	err := json.Unmarshal(w.Body.Bytes(), &list)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &list
+}
+
+func retrieveListMembers(s *gin.Engine, listID int64, authToken string) []*model.PublicUser {
+	path := fmt.Sprintf("/lists/%v/members", listID)
+	req := request("GET", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.UsersPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return page.Users
+}
+
+func retrieveListTimeline(s *gin.Engine, listID int64, authToken string) []*model.Tweet {
+	return retrieveListTimelinePage(s, listID, "", 20, authToken).Tweets
+}
+
+func retrieveListTimelinePage(s *gin.Engine, listID int64, cursor string, limit int, authToken string) *model.TweetsPage {
+	path := fmt.Sprintf("/lists/%v/timeline", listID)
+	req := request("GET", path, nil).authorize(authToken).urlQuery("cursor", cursor).urlQuery("limit", int64(limit)).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var page model.TweetsPage
+	err := json.Unmarshal(w.Body.Bytes(), &page)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &page
+}
+
+func subscribeList(s *gin.Engine, listID int64, authToken string) *model.List {
+	path := fmt.Sprintf("/lists/%v/subscribe", listID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var list model.List
+	err := json.Unmarshal(w.Body.Bytes(), &list)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &list
+}
+
+func unsubscribeList(s *gin.Engine, listID int64, authToken string) *model.List {
+	path := fmt.Sprintf("/lists/%v/unsubscribe", listID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var list model.List
+	err := json.Unmarshal(w.Body.Bytes(), &list)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &list
+}
+
+// Mutes
+func muteUser(s *gin.Engine, userID int64, authToken string) {
+	path := fmt.Sprintf("/users/%v/mute", userID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusNoContent))
+}
+
+func unmuteUser(s *gin.Engine, userID int64, authToken string) {
+	path := fmt.Sprintf("/users/%v/unmute", userID)
+	req := request("POST", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusNoContent))
+}
+
+func retrieveMutedUsers(s *gin.Engine, authToken string) []*model.PublicUser {
+	req := request("GET", "/mutes/users", nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusOK))
+
+	var users []*model.PublicUser
+	err := json.Unmarshal(w.Body.Bytes(), &users)
+	Expect(err).NotTo(HaveOccurred())
+
+	return users
+}
+
+func muteWord(s *gin.Engine, word string, authToken string) {
+	newMutedWord := &model.NewMutedWord{Word: word}
+	req := request("POST", "/mutes/words", body(newMutedWord)).json().authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusNoContent))
+}
+
+func unmuteWord(s *gin.Engine, word string, authToken string) {
+	path := "/mutes/words/" + (&url.URL{Path: word}).EscapedPath()
+	req := request("DELETE", path, nil).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusNoContent))
+}
+
+func retrieveMutedWords(s *gin.Engine, authToken string) []string {
+	req := request("GET", "/mutes/words", nil).authorize(authToken).build()
 	w := httptest.NewRecorder()
 	s.ServeHTTP(w, req)
 	Expect(w.Code).To(Equal(http.StatusOK))
 
-	var tweets []*model.Tweet
-	err := json.Unmarshal(w.Body.Bytes(), &tweets)
+	var words []string
+	err := json.Unmarshal(w.Body.Bytes(), &words)
+	Expect(err).NotTo(HaveOccurred())
+
+	return words
+}
+
+// Feed stream
+type streamEvent struct {
+	Type string
+	Data []byte
+}
+
+// openFeedStream connects to the feed stream of the server and returns
+// channel of received events and function which closes the stream.
+func openFeedStream(serverURL string, authToken string) (<-chan *streamEvent, func()) {
+	req, err := http.NewRequest("GET", serverURL+"/stream/feed", nil)
+	Expect(err).NotTo(HaveOccurred())
+	req.Header.Set("User-Agent", "testAgent")
+	// tokens are bound to the address of the test requests
+	req.Header.Set("X-Real-Ip", "192.0.2.1")
+	req.Header.Set("Authorization", "Bearer "+authToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/event-stream"))
+
+	events := make(chan *streamEvent, 10)
+	go func() {
+		defer close(events)
+
+		reader := bufio.NewReader(resp.Body)
+		event := &streamEvent{}
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case line == "":
+				if event.Type != "" {
+					events <- event
+				}
+				event = &streamEvent{}
+			case strings.HasPrefix(line, "event:"):
+				event.Type = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			case strings.HasPrefix(line, "data:"):
+				event.Data = append(event.Data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+			}
+		}
+	}()
+
+	return events, func() { resp.Body.Close() }
+}
+
+// Gateway
+type gatewayMessage struct {
+	ID    string          `json:"id"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+	Error string          `json:"error"`
+}
+
+// openGateway connects to the WebSocket gateway of the server and returns
+// channel of received messages and the connection.
+func openGateway(serverURL string, authToken string) (<-chan *gatewayMessage, *websocket.Conn) {
+	header := http.Header{}
+	header.Set("User-Agent", "testAgent")
+	// tokens are bound to the address of the test requests
+	header.Set("X-Real-Ip", "192.0.2.1")
+	header.Set("Authorization", "Bearer "+authToken)
+
+	url := "ws" + strings.TrimPrefix(serverURL, "http") + "/gateway"
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
 	Expect(err).NotTo(HaveOccurred())
 
-	return tweets
+	messages := make(chan *gatewayMessage, 10)
+	go func() {
+		defer close(messages)
+
+		for {
+			var message gatewayMessage
+			if err := conn.ReadJSON(&message); err != nil {
+				return
+			}
+
+			messages <- &message
+		}
+	}()
+
+	return messages, conn
 }
 
 // Interface to bytes marshaler (helper for body)
+// Media
+func uploadMedia(s *gin.Engine, data []byte, authToken string) *model.Media {
+	mediaBody, contentType := multipartBody("media", data)
+	req := request("POST", "/media", mediaBody).contentType(contentType).authorize(authToken).build()
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	Expect(w.Code).To(Equal(http.StatusCreated))
+
+	var media model.Media
+	err := json.Unmarshal(w.Body.Bytes(), &media)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &media
+}
+
+func pngImage(width, height int) []byte {
+	var buffer bytes.Buffer
+
+	err := png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, width, height)))
+	Expect(err).NotTo(HaveOccurred())
+
+	return buffer.Bytes()
+}
+
+// Body
+func multipartBody(field string, data []byte) (*bytes.Buffer, string) {
+	var buffer bytes.Buffer
+
+	writer := multipart.NewWriter(&buffer)
+	part, err := writer.CreateFormFile(field, "file")
+	Expect(err).NotTo(HaveOccurred())
+	_, err = part.Write(data)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(writer.Close()).To(Succeed())
+
+	return &buffer, writer.FormDataContentType()
+}
+
 func body(bodyData interface{}) *bytes.Buffer {
 	data, _ := json.Marshal(bodyData)
 	body := bytes.NewBuffer(data)
@@ -256,6 +999,11 @@ func (rb *requestBuilder) json() *requestBuilder {
 	return rb
 }
 
+func (rb *requestBuilder) contentType(contentType string) *requestBuilder {
+	rb.request.Header.Add("Content-Type", contentType)
+	return rb
+}
+
 func (rb *requestBuilder) authorize(authToken string) *requestBuilder {
 	rb.request.Header.Add("Authorization", "Bearer "+authToken)
 	return rb
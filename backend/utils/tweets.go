@@ -5,7 +5,8 @@ import (
 )
 
 // TweetsByCreationDateDesc is helper struct to implement sorting of `Tweet`
-// slices by creation date.
+// slices by creation date. Tweets created at the same time are sorted by ID
+// so the order is the same as the order of pages.
 type TweetsByCreationDateDesc []*model.Tweet
 
 func (s TweetsByCreationDateDesc) Len() int {
@@ -15,5 +16,8 @@ func (s TweetsByCreationDateDesc) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
 }
 func (s TweetsByCreationDateDesc) Less(i, j int) bool {
+	if s[i].CreatedAt.Equal(s[j].CreatedAt) {
+		return s[i].ID > s[j].ID
+	}
 	return s[i].CreatedAt.After(s[j].CreatedAt)
 }
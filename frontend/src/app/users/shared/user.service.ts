@@ -28,22 +28,26 @@ export class UserService {
 
   getTweets() {
     return this.apiService.get(this.user_path + "/" + this.user.id + "/tweets")
+      .map(page => page.tweets)
       .do(tweets => this.storeHelper.update("my_tweets", tweets))
   }
 
   getFeed() {
     return this.apiService.get("/feed")
+      .map(page => page.tweets)
       .do(tweets => this.storeHelper.update("feed", tweets))
   }
 
   getFollowing() {
     // NOTE: only here should happen name rewrite from followees => following
     return this.apiService.get(this.user_path + "/" + this.user.id + "/followees")
+      .map(page => page.users)
       .do(followees => this.storeHelper.update("my_following", followees))
   }
 
   getFollowers() {
     return this.apiService.get(this.user_path + "/" + this.user.id + "/followers")
+      .map(page => page.users)
       .do(followers => this.storeHelper.update("my_followers", followers))
   }
 
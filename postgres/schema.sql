@@ -40,6 +40,7 @@ CREATE INDEX users_active_idx ON users (active);
 CREATE TABLE follows (
   follower_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
   followee_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  followed_at   TIMESTAMP NOT NULL DEFAULT now(),
 
   PRIMARY KEY (follower_id, followee_id),
   CHECK       (follower_id != followee_id)
@@ -48,16 +49,51 @@ CREATE TABLE follows (
 CREATE INDEX follows_follower_idx ON follows (follower_id);
 CREATE INDEX follows_followee_idx ON follows (followee_id);
 CREATE INDEX follows_idx ON follows (follower_id, followee_id);
+CREATE INDEX follows_followers_page_idx ON follows (followee_id, followed_at DESC, follower_id DESC);
+CREATE INDEX follows_followees_page_idx ON follows (follower_id, followed_at DESC, followee_id DESC);
 
 
 CREATE TABLE tweets (
-  id          SERIAL PRIMARY KEY,
-  author_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
-  created_at  TIMESTAMP NOT NULL DEFAULT now(),
-  content     VARCHAR(150) NOT NULL
+  id              SERIAL PRIMARY KEY,
+  author_id       INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  edited_at       TIMESTAMP,
+  content         TEXT NOT NULL, -- length is limited by the content policy
+
+  -- no foreign keys here since replies and quotes should stay when
+  -- referenced tweet is deleted
+  in_reply_to_id  INTEGER,
+  root_id         INTEGER,
+  quoted_tweet_id INTEGER,
+
+  -- hashtags, mentions and urls found in the content (see `entities` package)
+  entities        JSONB,
+
+  visibility      VARCHAR(16) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'followers')),
+
+  -- deleted tweets are hidden and can be restored until they are purged
+  deleted_at      TIMESTAMP
 );
 
 CREATE INDEX tweets_idx ON tweets (id);
+CREATE INDEX tweets_in_reply_to_idx ON tweets (in_reply_to_id, created_at, id);
+CREATE INDEX tweets_root_idx ON tweets (root_id);
+CREATE INDEX tweets_authors_idx ON tweets (author_id, created_at DESC, id DESC);
+CREATE INDEX tweets_deleted_idx ON tweets (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- users table is created before tweets so the pinned tweet has to be added here
+ALTER TABLE users ADD COLUMN pinned_tweet_id INTEGER REFERENCES tweets (id) ON DELETE SET NULL;
+
+
+CREATE TABLE tweet_revisions (
+  id         SERIAL PRIMARY KEY,
+  tweet_id   INTEGER REFERENCES tweets (id) ON DELETE CASCADE,
+  content    TEXT NOT NULL,
+  -- time when this version of the tweet was published
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX tweet_revisions_tweets_idx ON tweet_revisions (tweet_id);
 
 
 CREATE TABLE likes (
@@ -68,8 +104,8 @@ CREATE TABLE likes (
   PRIMARY KEY (tweet_id, user_id)
 );
 
-CREATE INDEX likes_tweets_idx ON likes (tweet_id);
-CREATE INDEX likes_users_idx ON likes (user_id);
+CREATE INDEX likes_tweets_idx ON likes (tweet_id, liked_at DESC, user_id DESC);
+CREATE INDEX likes_users_idx ON likes (user_id, liked_at DESC, tweet_id DESC);
 CREATE INDEX likes_idx ON likes (tweet_id, user_id, liked_at);
 
 
@@ -86,21 +122,163 @@ CREATE INDEX retweets_users_idx ON retweets (user_id);
 CREATE INDEX retweets_idx ON retweets (tweet_id, user_id, retweeted_at);
 
 
-CREATE TABLE tags (
-  id        SERIAL PRIMARY KEY,
-  name      VARCHAR(150) UNIQUE NOT NULL
+-- bookmarks are private, only the owner can see them
+CREATE TABLE bookmarks (
+  tweet_id      INTEGER REFERENCES tweets (id) ON DELETE CASCADE,
+  user_id       INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  bookmarked_at TIMESTAMP NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (tweet_id, user_id)
+);
+
+CREATE INDEX bookmarks_users_idx ON bookmarks (user_id, bookmarked_at DESC, tweet_id DESC);
+
+
+-- impressions and profile clicks are counted in Redis and saved here
+-- periodically, likes and retweets are counted from their own tables
+CREATE TABLE tweet_stats (
+  tweet_id       INTEGER REFERENCES tweets (id) ON DELETE CASCADE,
+  hour           TIMESTAMP NOT NULL,
+  impressions    BIGINT NOT NULL DEFAULT 0,
+  profile_clicks BIGINT NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (tweet_id, hour)
+);
+
+
+-- `created_at` is copied from the tweet so newest tweets of the hashtag can be
+-- read using only the index
+CREATE TABLE tweet_hashtags (
+  tweet_id   INTEGER REFERENCES tweets (id) ON DELETE CASCADE,
+  hashtag    VARCHAR(150) NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+
+  PRIMARY KEY (tweet_id, hashtag)
 );
 
-CREATE UNIQUE INDEX tags_lowercase_name_idx ON tags ((lower(name)));
+CREATE INDEX tweet_hashtags_hashtag_idx ON tweet_hashtags (hashtag, created_at DESC, tweet_id DESC);
 
 
-CREATE TABLE tweets_tags (
+CREATE TABLE mentions (
   tweet_id  INTEGER REFERENCES tweets (id) ON DELETE CASCADE,
-  tag_id    INTEGER REFERENCES tags (id) ON DELETE CASCADE,
+  user_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
+
+  PRIMARY KEY (tweet_id, user_id)
+);
+
+CREATE INDEX mentions_tweets_idx ON mentions (tweet_id);
+CREATE INDEX mentions_users_idx ON mentions (user_id);
+
+
+CREATE TABLE media (
+  id         SERIAL PRIMARY KEY,
+  owner_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  -- NULL until media is attached to the tweet
+  tweet_id   INTEGER REFERENCES tweets (id) ON DELETE CASCADE,
+  position   SMALLINT,
+  blob_key   VARCHAR(255) UNIQUE NOT NULL,
+  mime_type  VARCHAR(255) NOT NULL,
+  width      INTEGER NOT NULL,
+  height     INTEGER NOT NULL,
+  size       INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX media_tweets_idx ON media (tweet_id);
+
+
+
+CREATE TABLE polls (
+  tweet_id   INTEGER PRIMARY KEY REFERENCES tweets (id) ON DELETE CASCADE,
+  closes_at  TIMESTAMP NOT NULL
+);
+
+
+CREATE TABLE poll_options (
+  tweet_id  INTEGER REFERENCES polls (tweet_id) ON DELETE CASCADE,
+  position  SMALLINT NOT NULL,
+  text      VARCHAR(25) NOT NULL,
+
+  PRIMARY KEY (tweet_id, position)
+);
+
+
+CREATE TABLE poll_votes (
+  tweet_id  INTEGER REFERENCES polls (tweet_id) ON DELETE CASCADE,
+  user_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  position  SMALLINT NOT NULL,
+  voted_at  TIMESTAMP NOT NULL DEFAULT now(),
+
+  -- each user can vote only once
+  PRIMARY KEY (tweet_id, user_id),
+  FOREIGN KEY (tweet_id, position) REFERENCES poll_options (tweet_id, position) ON DELETE CASCADE
+);
+
+CREATE TABLE scheduled_tweets (
+  id              SERIAL PRIMARY KEY,
+  author_id       INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  content         TEXT NOT NULL, -- length is limited by the content policy
+  in_reply_to_id  INTEGER,
+  quoted_tweet_id INTEGER,
+  media_ids       INTEGER[] NOT NULL DEFAULT '{}',
+  visibility      VARCHAR(16) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'followers')),
+  -- UTC time at which the tweet should be published
+  publish_at      TIMESTAMP NOT NULL,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  -- reason why the tweet could not be published, such tweets are not published again
+  failure         TEXT
+);
+
+CREATE INDEX scheduled_tweets_authors_idx ON scheduled_tweets (author_id);
+CREATE INDEX scheduled_tweets_publish_at_idx ON scheduled_tweets (publish_at);
+
+
+CREATE TABLE lists (
+  id          SERIAL PRIMARY KEY,
+  owner_id    INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  name        VARCHAR(25) NOT NULL,
+  private     BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at  TIMESTAMP NOT NULL DEFAULT now()
+);
 
-  PRIMARY KEY (tweet_id, tag_id)
+CREATE INDEX lists_owners_idx ON lists (owner_id);
+
+
+CREATE TABLE list_members (
+  list_id   INTEGER REFERENCES lists (id) ON DELETE CASCADE,
+  user_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  added_at  TIMESTAMP NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (list_id, user_id)
+);
+
+CREATE INDEX list_members_page_idx ON list_members (list_id, added_at DESC, user_id DESC);
+
+
+CREATE TABLE list_subscriptions (
+  list_id        INTEGER REFERENCES lists (id) ON DELETE CASCADE,
+  user_id        INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  subscribed_at  TIMESTAMP NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (list_id, user_id)
 );
 
-CREATE INDEX tweets_tags_tweets_idx ON tweets_tags (tweet_id);
-CREATE INDEX tweets_tags_tags_idx ON tweets_tags (tag_id);
-CREATE INDEX tweets_tags_idx ON tweets_tags (tweet_id, tag_id);
+CREATE INDEX list_subscriptions_users_idx ON list_subscriptions (user_id, subscribed_at DESC);
+
+
+CREATE TABLE mutes (
+  user_id        INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  muted_user_id  INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  muted_at       TIMESTAMP NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (user_id, muted_user_id)
+);
+
+
+CREATE TABLE muted_words (
+  user_id   INTEGER REFERENCES users (id) ON DELETE CASCADE,
+  word      VARCHAR(50) NOT NULL,
+  muted_at  TIMESTAMP NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (user_id, word)
+);